@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/texture"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// convert is the subcommand writing a scene file from the camera, light and objects json files
+// and .obj files, with the paths made relative to the written file.
+//
+// Parameters:
+//  args - the arguments after the subcommand.
+//
+// Returns:
+//  none
+//
+func convert(args []string) {
+	flags := newFlagSet("convert", "[model.obj...]")
+	cameraPath := flags.String("camera", "", "camera json file")
+	lightsPath := flags.String("lights", "", "lights json file")
+	objectsPath := flags.String("objects", "", "objects json file")
+	outPath := flags.String("o", "scene.json", "written scene file")
+	width := flags.Int("width", 0, "width of the image in pixels")
+	height := flags.Int("height", 0, "height of the image in pixels")
+	spp := flags.Int("spp", 0, "rays (samples) per pixel")
+	depth := flags.Int("depth", 0, "number of bounces of each ray")
+	flags.Parse(args)
+
+	outDir, err := filepath.Abs(filepath.Dir(*outPath))
+	utils.ShowError(err, "Invalid output path.")
+	file := scene.File{
+		Settings:  scene.Settings{Width: *width, Height: *height, RaysPerPixel: *spp, Iterations: *depth},
+		Materials: make(map[string]*general.Material),
+	}
+	if *objectsPath != "" {
+		// the objects are read as written, LoadJSONObjects would generate their normals
+		data, err := ioutil.ReadFile(*objectsPath)
+		utils.ShowError(err, "Unable to read objects.")
+		var objs general.Objects
+		utils.ShowError(utils.WrapFile("parse", *objectsPath, json.Unmarshal(data, &objs)), "Unable to read objects.")
+		for i := range objs.ObjList {
+			relativeImages(outDir, &objs.ObjList[i])
+			file.Objects = append(file.Objects, scene.ObjectDesc{Object: objs.ObjList[i]})
+		}
+		for i := range objs.Meshes {
+			relativeImages(outDir, &objs.Meshes[i])
+			file.Meshes = append(file.Meshes, scene.ObjectDesc{Object: objs.Meshes[i]})
+		}
+		for i, inst := range objs.Instances {
+			desc := scene.InstanceDesc{Name: inst.Name, Mesh: inst.Mesh, Transform: inst.Transform}
+			if inst.Material != nil {
+				desc.Material = fmt.Sprintf("instance%d", i)
+				inst.Material.Texture = relativeImage(outDir, inst.Material.Texture)
+				file.Materials[desc.Material] = inst.Material
+			}
+			file.Instances = append(file.Instances, desc)
+		}
+	}
+	for _, objPath := range flags.Args() {
+		file.Objects = append(file.Objects, scene.ObjectDesc{Path: relativePath(outDir, objPath)})
+	}
+	if *lightsPath != "" {
+		lgts, err := light.LoadJSONLights(*lightsPath)
+		utils.ShowError(err, "Unable to load lights.")
+		for _, lgt := range lgts.LightList {
+			desc := scene.InitLightDesc(lgt)
+			relativeImages(outDir, &desc.Object.Object)
+			desc.Image = relativeImage(outDir, desc.Image)
+			if desc.Profile != nil && desc.Profile.Path != "" {
+				desc.Profile.Path = relativePath(outDir, desc.Profile.Path)
+			}
+			file.Lights = append(file.Lights, desc)
+		}
+	}
+	if *cameraPath != "" {
+		cam, err := camera.LoadJSONCamera(*cameraPath)
+		utils.ShowError(err, "Unable to load camera.")
+		file.Cameras = append(file.Cameras, scene.CameraDesc{Name: "camera", Camera: *cam})
+	}
+	utils.ShowError(file.WriteJSON(*outPath), "Unable to write scene.")
+	fmt.Printf("%s: %d objects, %d meshes, %d instances, %d lights, %d cameras\n", *outPath, len(file.Objects), len(file.Meshes), len(file.Instances), len(file.Lights), len(file.Cameras))
+}
+
+// relativePath is a function to make a path relative to the folder of the written scene file.
+//
+// Parameters:
+//  outDir - absolute path to the folder of the scene file.
+//  inPath - the path, relative to the working directory.
+//
+// Returns:
+//  the relative path, or the absolute one when there is none.
+//
+func relativePath(outDir, inPath string) string {
+	absPath, err := filepath.Abs(inPath)
+	if err != nil {
+		return inPath
+	}
+	relPath, err := filepath.Rel(outDir, absPath)
+	if err != nil {
+		return absPath
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// relativeImage is a function to make the path of an image relative to the written scene file.
+//
+// Parameters:
+//  outDir - absolute path to the folder of the scene file.
+//  img    - the image, may be nil.
+//
+// Returns:
+//  the image.
+//
+func relativeImage(outDir string, img *texture.Image) *texture.Image {
+	if img != nil && img.Path != "" {
+		img.Path = relativePath(outDir, img.Path)
+	}
+	return img
+}
+
+// relativeImages is a function to make the paths of the images of an object relative to the written scene file.
+//
+// Parameters:
+//  outDir - absolute path to the folder of the scene file.
+//  obj    - the object.
+//
+// Returns:
+//  none
+//
+func relativeImages(outDir string, obj *general.Object) {
+	obj.Texture = relativeImage(outDir, obj.Texture)
+	obj.NormalMap = relativeImage(outDir, obj.NormalMap)
+	obj.BumpMap = relativeImage(outDir, obj.BumpMap)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// inspect is the subcommand printing the settings, objects, lights, cameras and bounds of a scene file.
+//
+// Parameters:
+//  args - the arguments after the subcommand.
+//
+// Returns:
+//  none
+//
+func inspect(args []string) {
+	flags := newFlagSet("inspect", "[scene.json]")
+	var sets overrides
+	flags.Var(&sets, "set", "override a scene parameter as `path=value` before printing it (repeatable)")
+	flags.Parse(args)
+	scenePath := sceneArg(flags)
+	sc, err := scene.Load(scenePath)
+	utils.ShowError(err, "Unable to load scene.")
+	sets.apply(sc)
+
+	settings := sc.Settings
+	fmt.Printf("%s\n\nSettings\n", scenePath)
+	fmt.Printf("  image       %dx%d\n", settings.Width, settings.Height)
+	fmt.Printf("  rays        %d per pixel, %d iterations\n", settings.RaysPerPixel, settings.Iterations)
+	fmt.Printf("  output      %s\n", settings.Output)
+	fmt.Printf("  camera      %s\n", settings.Camera)
+	fmt.Printf("  sampler     %s\n", settings.LightSampler)
+
+	triangles := 0
+	fmt.Printf("\nObjects (%d)\n", len(sc.Objects.ObjList))
+	for i := range sc.Objects.ObjList {
+		triangles += printObject(&sc.Objects.ObjList[i])
+	}
+	if len(sc.Objects.Meshes) > 0 {
+		fmt.Printf("\nMeshes (%d)\n", len(sc.Objects.Meshes))
+		for i := range sc.Objects.Meshes {
+			printObject(&sc.Objects.Meshes[i])
+		}
+	}
+	if len(sc.Objects.Instances) > 0 {
+		fmt.Printf("\nInstances (%d)\n", len(sc.Objects.Instances))
+		for _, inst := range sc.Objects.Instances {
+			if mesh, found := sc.Objects.FindMesh(inst.Mesh); found {
+				triangles += len(mesh.Triangles)
+			}
+			fmt.Printf("  %-20s mesh %s\n", inst.Name, inst.Mesh)
+		}
+	}
+
+	fmt.Printf("\nLights (%d)\n", len(sc.Lights.LightList))
+	for _, lgt := range sc.Lights.LightList {
+		emitter := lgt.GetEmitter()
+		name := emitter.Type
+		if area, ok := lgt.(*light.AreaLight); ok {
+			name = area.LightObject.Name
+		}
+		fmt.Printf("  %-20s intensity %g, ambient %g, color %v\n", name, emitter.LightIntensity, emitter.AmbientIntensity, emitter.Color)
+		var profile *light.Profile
+		switch lgt := lgt.(type) {
+		case *light.PointLight:
+			profile = lgt.Profile
+		case *light.SpotLight:
+			profile = lgt.Profile
+		}
+		if profile != nil && profile.Loaded() {
+			fmt.Printf("  %-20s profile %s, %g cd at most\n", "", profile.Path, profile.MaxCandela())
+		}
+	}
+
+	names := make([]string, 0, len(sc.Cameras))
+	for name := range sc.Cameras {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("\nCameras (%d)\n", len(names))
+	for _, name := range names {
+		cam := sc.Cameras[name]
+		selected := " "
+		if cam == sc.Camera {
+			selected = "*"
+		}
+		fmt.Printf("%s %-20s pos %v, look %v, fov %g\n", selected, name, cam.Pos.Coordinates, cam.Look.Coordinates, cam.FieldOfView)
+	}
+
+	shapes, err := shape.FromObjects(sc.Objects)
+	utils.ShowError(err, "Unable to build the shapes.")
+	bb := shapes.Bounds()
+	fmt.Printf("\n%d triangles, bounds %v to %v\n", triangles, bb[:3], bb[3:])
+}
+
+// printObject is a function to print a line about an object.
+//
+// Parameters:
+//  obj - the object.
+//
+// Returns:
+//  the number of triangles of the object.
+//
+func printObject(obj *general.Object) int {
+	emission := ""
+	if radiance := obj.GetEmission(); radiance != nil {
+		emission = fmt.Sprintf(", emission %v", radiance)
+	}
+	if obj.Primitive != nil {
+		fmt.Printf("  %-20s %s, color %v%s\n", obj.Name, obj.Primitive.Type, obj.Color, emission)
+		return 0
+	}
+	fmt.Printf("  %-20s %d triangles, %d vertices, color %v%s\n", obj.Name, len(obj.Triangles), len(obj.Vertices.Points), obj.Color, emission)
+	return len(obj.Triangles)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lucas625/Projeto-CG/src/algorithms/ambientocclusion"
+	"github.com/lucas625/Projeto-CG/src/algorithms/debugview"
+	"github.com/lucas625/Projeto-CG/src/algorithms/pathtracing"
+	"github.com/lucas625/Projeto-CG/src/algorithms/raycasting"
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/utils"
+	"github.com/lucas625/Projeto-CG/src/validation"
+	"github.com/lucas625/Projeto-CG/src/visualizer"
+)
+
+// Integrators of the render command.
+const (
+	integratorPathTracing      = "pathtracing"
+	integratorRayCasting       = "raycasting"
+	integratorAmbientOcclusion = "ambientocclusion"
+)
+
+// defaultInterocular is the distance between the eyes of a stereo render, the human one in meters.
+const defaultInterocular = 0.065
+
+// render is the subcommand rendering a scene file.
+// The flags replace the settings of the file, zero values keep them.
+//
+// Parameters:
+//  args - the arguments after the subcommand.
+//
+// Returns:
+//  none
+//
+func render(args []string) {
+	flags := newFlagSet("render", "[scene.json]")
+	width := flags.Int("width", 0, "width of the image in pixels")
+	height := flags.Int("height", 0, "height of the image in pixels")
+	spp := flags.Int("spp", 0, "rays (samples) per pixel")
+	depth := flags.Int("depth", 0, "number of bounces of each ray")
+	integrator := flags.String("integrator", integratorPathTracing, "rendering algorithm, "+integratorPathTracing+", "+integratorRayCasting+", "+integratorAmbientOcclusion+" or a debug view: "+strings.Join(debugview.Modes, ", "))
+	seed := flags.Int64("seed", 0, "seed of the random numbers, 0 for a time based seed (reproducible with -threads 1)")
+	sampler := flags.String("sampler", "", "light sampler of the path tracer, "+light.SamplerUniform+", "+light.SamplerPower+" or "+light.SamplerBVH)
+	aoSamples := flags.Int("ao-samples", ambientocclusion.DefaultSamples, "occlusion rays at each hit of "+integratorAmbientOcclusion)
+	aoDistance := flags.Float64("ao-distance", 0, "distance beyond which objects do not occlude in "+integratorAmbientOcclusion+", 0 for a quarter of the scene")
+	aoTint := flags.Bool("ao-tint", false, "multiply "+integratorAmbientOcclusion+" by the color of each object")
+	stereo := flags.String("stereo", "", "stereo projection of "+integratorPathTracing+", "+camera.StereoParallel+", "+camera.StereoToeIn+" or "+camera.StereoOmnidirectional+" (equirectangular), empty for a single image")
+	interocular := flags.Float64("interocular", defaultInterocular, "distance between the eyes of -stereo")
+	convergence := flags.Float64("convergence", 0, "distance to the zero parallax plane of -stereo, 0 for infinity")
+	layout := flags.String("stereo-layout", camera.LayoutSideBySide, "images of -stereo, "+camera.LayoutSeparate+" (name-left and name-right), "+camera.LayoutSideBySide+" or "+camera.LayoutTopBottom)
+	threads := flags.Int("threads", pathtracing.DefaultThreads, "goroutines tracing the lines of the image, the default when 0")
+	output := flags.String("o", "", "output folder")
+	name := flags.String("name", "object", "name of the image file without extension")
+	format := flags.String("format", visualizer.FormatPPM, "image format, "+visualizer.FormatPPM+" or "+visualizer.FormatPNG)
+	cameraName := flags.String("camera", "", "name of the rendered camera")
+	var sets overrides
+	flags.Var(&sets, "set", "override a scene parameter as `path=value`, like ObjList[0].Color=[1,0,0] or Cameras[\"front\"].FieldOfView=40 (repeatable)")
+	flags.Parse(args)
+	scenePath := sceneArg(flags)
+	// checking the flags before a long render
+	switch *integrator {
+	case integratorPathTracing, integratorRayCasting, integratorAmbientOcclusion:
+	default:
+		if !debugview.IsMode(*integrator) {
+			utils.ShowError(fmt.Errorf("unknown integrator %q", *integrator), "Invalid integrator.")
+		}
+	}
+	if *aoSamples < 0 || *aoDistance < 0 {
+		utils.ShowError(fmt.Errorf("-ao-samples %d -ao-distance %g", *aoSamples, *aoDistance), "Invalid ambient occlusion.")
+	}
+	if *stereo != "" && *integrator != integratorPathTracing {
+		utils.ShowError(fmt.Errorf("-stereo with -integrator %s", *integrator), "Stereo needs "+integratorPathTracing+".")
+	}
+	if *interocular < 0 || *convergence < 0 {
+		utils.ShowError(fmt.Errorf("-interocular %g -convergence %g", *interocular, *convergence), "Invalid stereo rig.")
+	}
+	if *format != visualizer.FormatPPM && *format != visualizer.FormatPNG {
+		utils.ShowError(fmt.Errorf("unknown image format %q", *format), "Invalid format.")
+	}
+	if *threads <= 0 {
+		utils.ShowError(fmt.Errorf("-threads %d", *threads), "Invalid number of threads.")
+	}
+
+	sc, err := scene.Load(scenePath)
+	utils.ShowError(err, "Unable to load scene.")
+	sets.apply(sc)
+	utils.ShowError(sc.SelectCamera(*cameraName), "Invalid camera.")
+	settings := &sc.Settings
+	for _, setting := range []struct {
+		flag  int
+		value *int
+	}{{*width, &settings.Width}, {*height, &settings.Height}, {*spp, &settings.RaysPerPixel}, {*depth, &settings.Iterations}} {
+		if setting.flag != 0 {
+			*setting.value = setting.flag
+		}
+	}
+	if *output != "" {
+		settings.Output = *output
+	}
+	if *sampler != "" {
+		settings.LightSampler = *sampler
+	}
+
+	report := validation.ValidateScene(sc)
+	for _, issue := range report.Issues {
+		if issue.Severity == validation.SeverityWarning {
+			fmt.Fprintln(os.Stderr, issue)
+		}
+	}
+	utils.ShowError(report.Err(), "Invalid scene.")
+
+	// getting screen
+	camMatrix := camera.CamToWorld(sc.Camera)
+	pixelScreen, err := screen.InitScreen(settings.Width, settings.Height)
+	utils.ShowError(err, "Invalid screen.")
+	pixelScreen.CamToWorld = &camMatrix
+
+	var colorScreen *screen.ColoredScreen
+	start := time.Now()
+	switch *integrator {
+	case integratorPathTracing:
+		pathTracer, err := pathtracing.InitPathTracer(sc.Objects, &pixelScreen, sc.Camera, sc.Lights)
+		utils.ShowError(err, "Unable to build the shapes.")
+		pathTracer.Threads = *threads
+		utils.ShowError(pathTracer.SetLightSampler(settings.LightSampler), "Invalid light sampler.")
+		if *seed != 0 {
+			rand.Seed(*seed)
+		}
+		if *stereo != "" {
+			rig, err := camera.InitStereoRig(*sc.Camera, *interocular, *convergence, *stereo, *layout)
+			utils.ShowError(err, "Invalid stereo rig.")
+			left, right := pathTracer.RunStereo(&rig, settings.RaysPerPixel, settings.Iterations)
+			fmt.Fprintf(os.Stderr, "rendered %dx%d stereo in %s\n", settings.Width, settings.Height, time.Since(start).Round(time.Millisecond))
+			err = visualizer.WriteNamedStereoImage(left, right, rig.Layout, settings.Output, *name, *format)
+			utils.ShowError(err, "Unable to write image.")
+			return
+		}
+		colorScreen = pathTracer.Run(settings.RaysPerPixel, settings.Iterations)
+	case integratorRayCasting:
+		rayCaster, err := raycasting.InitRayCaster(sc.Objects, &pixelScreen, sc.Camera, sc.Lights)
+		utils.ShowError(err, "Unable to build the shapes.")
+		rayCaster.Depth = settings.Iterations
+		colorScreen = rayCaster.Run()
+	case integratorAmbientOcclusion:
+		ao, err := ambientocclusion.InitAmbientOcclusion(sc.Objects, &pixelScreen, sc.Camera)
+		utils.ShowError(err, "Unable to build the shapes.")
+		ao.Samples = *aoSamples
+		ao.MaxDistance = *aoDistance
+		ao.Tint = *aoTint
+		if *seed != 0 {
+			rand.Seed(*seed)
+		}
+		// one ray through the center of each pixel unless -spp asks for more
+		colorScreen = ao.Run(*spp)
+	default:
+		view, err := debugview.InitDebugView(sc.Objects, &pixelScreen, sc.Camera, *integrator)
+		utils.ShowError(err, "Unable to build the shapes.")
+		colorScreen = view.Run()
+	}
+	fmt.Fprintf(os.Stderr, "rendered %dx%d in %s\n", settings.Width, settings.Height, time.Since(start).Round(time.Millisecond))
+
+	err = visualizer.WriteNamedImage(*colorScreen, settings.Output, *name, *format)
+	utils.ShowError(err, "Unable to write image.")
+}
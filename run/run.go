@@ -1,31 +1,165 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
 
-	"github.com/lucas625/Projeto-CG/src/algorithms/pathtracing"
-	"github.com/lucas625/Projeto-CG/src/camera"
-	"github.com/lucas625/Projeto-CG/src/general"
-	"github.com/lucas625/Projeto-CG/src/light"
-	"github.com/lucas625/Projeto-CG/src/screen"
-	"github.com/lucas625/Projeto-CG/src/visualizer"
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
+// defaultScene is the scene file used when no path is given.
+const defaultScene = "resources/run/scene.json"
+
+// command is a class for a subcommand of the command line.
+//
+// Members:
+//  name - the name typed after the program.
+//  help - one line description.
+//  run  - function receiving the arguments after the name.
+//
+type command struct {
+	name string
+	help string
+	run  func(args []string)
+}
+
+// commands is the list of subcommands, render is used when none is given.
+var commands = []command{
+	{"render", "render a scene file", render},
+	{"convert", "write a scene file from the camera, lights and objects json files", convert},
+	{"inspect", "print a summary of a scene file", inspect},
+	{"validate", "list every error and warning of a scene file", validate},
+}
+
+// Command line of the path tracer.
+//
+// Usage:
+//  go run ./run [command] [flags] [arguments]
+//
 func main() {
-	iterations := 5
-	raysPerPixel := 1000
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+			usage()
+			return
+		}
+		for _, cmd := range commands {
+			if cmd.name == args[0] {
+				cmd.run(args[1:])
+				return
+			}
+		}
+		if !strings.HasPrefix(args[0], "-") && !strings.HasSuffix(args[0], ".json") {
+			usage()
+			os.Exit(2)
+		}
+	}
+	render(args)
+}
+
+// usage is a function to print the subcommands.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  none
+//
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: run [command] [flags] [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", cmd.name, cmd.help)
+	}
+	fmt.Fprintln(os.Stderr, "\nrender is used when the command is omitted, run <command> -h for its flags.")
+}
+
+// newFlagSet is a function to create the flags of a subcommand.
+//
+// Parameters:
+//  cmd  - the name of the subcommand.
+//  args - the arguments after the flags, for the usage.
+//
+// Returns:
+//  the flag set, exiting on invalid flags.
+//
+func newFlagSet(cmd, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: run %s [flags] %s\n\nFlags:\n", cmd, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
 
-	cam := camera.LoadJSONCamera("resources/run/json/camera.json")
-	lights := light.LoadJSONLights("resources/run/json/light.json")
-	objects := general.LoadJSONObjects("resources/run/json/objects.json")
-	outPath := "out/pathtracing"
+// sceneArg is a function to get the scene file of a subcommand.
+//
+// Parameters:
+//  flags - the parsed flags.
+//
+// Returns:
+//  the first argument, or the default scene.
+//
+func sceneArg(flags *flag.FlagSet) string {
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if flags.NArg() == 1 {
+		return flags.Arg(0)
+	}
+	return defaultScene
+}
 
-	// getting screen
-	camMatrix := camera.CamToWorld(cam)
-	sc := screen.InitScreen(600, 600)
-	sc.CamToWorld = &camMatrix
+// overrides is a class for the repeated -set flag.
+//
+// Members:
+//  [i] - the overrides as path=value.
+//
+type overrides []string
 
-	pathTracer := pathtracing.InitPathTracer(objects, &sc, cam, lights)
+// String is a function to format the overrides.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the overrides separated by spaces.
+//
+func (list *overrides) String() string {
+	return strings.Join(*list, " ")
+}
+
+// Set is a function to add an override.
+//
+// Parameters:
+//  value - the override as path=value.
+//
+// Returns:
+//  an error if the override has no =.
+//
+func (list *overrides) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("%q is not path=value", value)
+	}
+	*list = append(*list, value)
+	return nil
+}
 
-	colorScreen := pathTracer.Run(raysPerPixel, iterations)
-	visualizer.WritePPM(*colorScreen, outPath)
+// apply is a function to override the parameters of a scene.
+//
+// Parameters:
+//  sc - the scene.
+//
+// Returns:
+//  none
+//
+func (list *overrides) apply(sc *scene.Scene) {
+	for _, override := range *list {
+		parts := strings.SplitN(override, "=", 2)
+		utils.ShowError(sc.Set(parts[0], parts[1]), "Invalid override.")
+	}
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/utils"
+	"github.com/lucas625/Projeto-CG/src/validation"
+)
+
+// validate is the subcommand printing every issue of a scene file.
+// Exits with status 1 when the scene has errors.
+//
+// Parameters:
+//  args - the arguments after the subcommand.
+//
+// Returns:
+//  none
+//
+func validate(args []string) {
+	flags := newFlagSet("validate", "[scene.json]")
+	flags.Parse(args)
+	scenePath := sceneArg(flags)
+	sc, err := scene.Read(scenePath)
+	utils.ShowError(err, "Unable to load scene.")
+
+	report := validation.ValidateScene(sc)
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%s: %d errors, %d warnings\n", scenePath, report.Count(validation.SeverityError), report.Count(validation.SeverityWarning))
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,202 @@
+package ambientocclusion
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/lucas625/Projeto-CG/src/algorithms/pathtracing"
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// DefaultSamples is the number of occlusion rays shot at each primary hit.
+const DefaultSamples = 16
+
+// defaultDistanceFraction is the fraction of the diagonal of the bounded objects used as MaxDistance when it is 0.
+const defaultDistanceFraction = 0.25
+
+// AmbientOcclusion is a class for the ambient occlusion algorithm: clay renders where every visible
+// point is as bright as the fraction of its hemisphere not blocked by nearby objects.
+//
+// Members:
+// 	Objs        - the list of objects.
+//  PixelScreen - the screen.
+//  Cam         - the camera.
+//  Samples     - number of cosine weighted rays shot at each primary hit.
+//  MaxDistance - distance beyond which objects do not occlude, a quarter of the diagonal of the
+//                bounded objects when 0.
+//  Tint        - true to multiply the result by the color of each object.
+//  shapes      - the shapes of the objects.
+//
+type AmbientOcclusion struct {
+	Objs        *general.Objects
+	PixelScreen *screen.Screen
+	Cam         *camera.Camera
+	Samples     int
+	MaxDistance float64
+	Tint        bool
+	shapes      shape.List
+}
+
+// maxDistance is a function to get the distance of the occluders.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  MaxDistance, or a quarter of the diagonal of the bounded objects when it is 0 (unlimited without them).
+//
+func (ao *AmbientOcclusion) maxDistance() float64 {
+	if ao.MaxDistance > 0 {
+		return ao.MaxDistance
+	}
+	bb := []float64{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, s := range ao.shapes.Shapes {
+		sbb := s.Bounds()
+		infinite := false
+		for _, value := range sbb {
+			infinite = infinite || math.IsInf(value, 0)
+		}
+		if infinite {
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			bb[j] = math.Min(bb[j], sbb[j])
+			bb[j+3] = math.Max(bb[j+3], sbb[j+3])
+		}
+	}
+	diagonal := utils.Vec3{bb[3] - bb[0], bb[4] - bb[1], bb[5] - bb[2]}.Length()
+	if !(diagonal > 0) || math.IsInf(diagonal, 0) {
+		return math.MaxFloat64
+	}
+	return diagonal * defaultDistanceFraction
+}
+
+// shade is a function to get the unoccluded fraction at the point hit by a ray.
+//
+// Parameters:
+//  ray         - the ray.
+//  tMin        - the smallest t accepted.
+//  maxDistance - distance beyond which objects do not occlude.
+//
+// Returns:
+// 	the rgb color, white when the ray hits nothing.
+//
+func (ao *AmbientOcclusion) shade(ray entity.Ray, tMin, maxDistance float64) utils.Vec3 {
+	hit, found := ao.shapes.Closest(ray, tMin, math.MaxFloat64)
+	if !found {
+		return utils.Vec3{1, 1, 1}
+	}
+	normal := hit.Shape.Normal(hit)
+	if normal.Dot(ray.Direction) > 0 {
+		// the hemisphere on the side seen by the ray
+		normal = normal.Neg()
+	}
+	visible := 0
+	for i := 0; i < ao.Samples; i++ {
+		occlusionRay := entity.Ray{Origin: hit.Point, Direction: pathtracing.RandomInSemiSphere(normal)}
+		if _, blocked := ao.shapes.Closest(occlusionRay, 0, maxDistance); !blocked {
+			visible++
+		}
+	}
+	fraction := 1.0
+	if ao.Samples > 0 {
+		fraction = float64(visible) / float64(ao.Samples)
+	}
+	color := utils.Vec3{fraction, fraction, fraction}
+	if ao.Tint {
+		albedo := hit.Object.GetAlbedo(hit.UV, entity.Vec3ToPoint(hit.Point), entity.Vec3ToPoint(hit.Local))
+		color = color.Mul(utils.Vec3{albedo[0], albedo[1], albedo[2]})
+	}
+	return color
+}
+
+// TraceRay is a function to trace the rays through a pixel.
+//
+// Parameters:
+// 	lp          - pixel line index.
+//  cp          - pixel column index.
+//  rays        - number of primary rays, through the center of the pixel when 1.
+//  maxDistance - distance beyond which objects do not occlude.
+//
+// Returns:
+// 	the averaged rgb color.
+//
+func (ao *AmbientOcclusion) TraceRay(lp, cp, rays int, maxDistance float64) []int {
+	origin := entity.PointToVec3(&ao.Cam.Pos)
+	var color utils.Vec3
+	for i := 0; i < rays; i++ {
+		offx, offy := 0.5, 0.5
+		if rays > 1 {
+			offx, offy = rand.Float64(), rand.Float64()
+		}
+		// the pixel is on the screen and CamToWorld is checked by InitAmbientOcclusion, ignoring the errors
+		screenV, _ := ao.PixelScreen.PixelToWorld(cp, lp, 1.0, offx, offy, ao.Cam.FieldOfView)
+		direction, _ := utils.VectorToVec3(&screenV)
+		// rays leaving the camera start on the screen
+		color = color.Add(ao.shade(entity.Ray{Origin: origin, Direction: direction}, 1, maxDistance))
+	}
+	intColor := make([]int, 3)
+	for i := range intColor {
+		// same gamma as the path tracer
+		intColor[i] = int(math.Floor(math.Sqrt(color[i]/float64(rays)) * 255))
+		if intColor[i] > 255 {
+			intColor[i] = 255
+		}
+	}
+	return intColor
+}
+
+// Run is a function to run the ambient occlusion.
+//
+// Parameters:
+// 	rays - number of primary rays per pixel.
+//
+// Returns:
+// 	the colored screen.
+//
+func (ao *AmbientOcclusion) Run(rays int) *screen.ColoredScreen {
+	if rays < 1 {
+		rays = 1
+	}
+	maxDistance := ao.maxDistance()
+	coloredScreen := screen.InitColoredScreen(ao.PixelScreen.Width, ao.PixelScreen.Height)
+	for i := 0; i < ao.PixelScreen.Height; i++ {
+		for j := 0; j < ao.PixelScreen.Width; j++ {
+			coloredScreen.Colors[i][j] = ao.TraceRay(i, j, rays, maxDistance)
+		}
+	}
+	return &coloredScreen
+}
+
+// InitAmbientOcclusion is a function to initialize an AmbientOcclusion.
+//
+// Parameters:
+// 	objs        - the list of objects.
+//  pixelScreen - the screen.
+//  cam         - the camera.
+//
+// Returns:
+// 	an AmbientOcclusion with DefaultSamples rays per hit.
+//  an error if the shapes of the objects could not be built.
+//
+func InitAmbientOcclusion(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera) (AmbientOcclusion, error) {
+	if err := pixelScreen.CheckIntegrity(); err != nil {
+		return AmbientOcclusion{}, utils.WrapField("PixelScreen", err)
+	}
+	shapes, err := shape.FromObjects(objs)
+	if err != nil {
+		return AmbientOcclusion{}, err
+	}
+	return AmbientOcclusion{
+		Objs:        objs,
+		PixelScreen: pixelScreen,
+		Cam:         cam,
+		Samples:     DefaultSamples,
+		shapes:      shapes,
+	}, nil
+}
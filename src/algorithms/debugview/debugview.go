@@ -0,0 +1,262 @@
+package debugview
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Modes of the DebugView.
+const (
+	ModeNormals          = "normals"
+	ModeGeometricNormals = "geometricnormals"
+	ModeBarycentrics     = "barycentrics"
+	ModeUV               = "uv"
+	ModeDepth            = "depth"
+	ModeObjectID         = "objectid"
+	ModeTriangleID       = "triangleid"
+	ModeFacing           = "facing"
+	ModeBVHCost          = "bvhcost"
+)
+
+// Modes is the list of all modes of the DebugView.
+var Modes = []string{
+	ModeNormals, ModeGeometricNormals, ModeBarycentrics, ModeUV, ModeDepth,
+	ModeObjectID, ModeTriangleID, ModeFacing, ModeBVHCost,
+}
+
+// farthestDepth is the gray of the farthest hit of ModeDepth, keeping it apart from the black background.
+const farthestDepth = 0.1
+
+// DebugView is a class for the debug visualizations: false colors of the data found by the ray
+// through the center of each pixel, without lights or materials.
+//
+// Members:
+// 	Objs        - the list of objects.
+//  PixelScreen - the screen.
+//  Cam         - the camera.
+//  Mode        - the data shown, one of Modes.
+//  shapes      - the shapes of the objects.
+//
+type DebugView struct {
+	Objs        *general.Objects
+	PixelScreen *screen.Screen
+	Cam         *camera.Camera
+	Mode        string
+	shapes      shape.List
+}
+
+// IsMode is a function to check if a name is a mode of the DebugView.
+//
+// Parameters:
+//  name - the name.
+//
+// Returns:
+//  true if the name is one of Modes.
+//
+func IsMode(name string) bool {
+	for _, mode := range Modes {
+		if name == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// encode is a function to map a normalized direction to a color.
+//
+// Parameters:
+//  dir - the direction.
+//
+// Returns:
+//  the rgb color, each coordinate moved from [-1, 1] to [0, 1].
+//
+func encode(dir utils.Vec3) utils.Vec3 {
+	return dir.Scale(0.5).Add(utils.Vec3{0.5, 0.5, 0.5})
+}
+
+// falseColor is a function to get a bright color that is unlikely to repeat on close ids.
+//
+// Parameters:
+//  ids - the ids, hashed together.
+//
+// Returns:
+//  the rgb color.
+//
+func falseColor(ids ...int) utils.Vec3 {
+	// FNV-1a of the ids followed by a final mix, so consecutive ids are far apart
+	hash := uint32(2166136261)
+	for _, id := range ids {
+		hash ^= uint32(id)
+		hash *= 16777619
+	}
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	var color utils.Vec3
+	for i := range color {
+		color[i] = 0.2 + 0.8*float64((hash>>(8*uint(i)))&0xff)/255
+	}
+	return color
+}
+
+// heat is a function to map a value to the colors of a heatmap.
+//
+// Parameters:
+//  value - the value in [0, 1].
+//
+// Returns:
+//  the rgb color going through blue, cyan, green, yellow and red.
+//
+func heat(value float64) utils.Vec3 {
+	stops := []utils.Vec3{{0, 0, 1}, {0, 1, 1}, {0, 1, 0}, {1, 1, 0}, {1, 0, 0}}
+	position := math.Max(0, math.Min(1, value)) * float64(len(stops)-1)
+	i := int(math.Min(position, float64(len(stops)-2)))
+	return stops[i].Lerp(stops[i+1], position-float64(i))
+}
+
+// shade is a function to get the data of the closest hit of a ray.
+//
+// Parameters:
+//  ray - the ray.
+//
+// Returns:
+//  the rgb color, black when the ray hits nothing.
+//  the value normalized over the image on ModeDepth (the distance) and ModeBVHCost (the cost).
+//  false if the ray hit nothing.
+//
+func (view *DebugView) shade(ray entity.Ray) (utils.Vec3, float64, bool) {
+	// rays leaving the camera start on the screen
+	hit, found, cost := view.shapes.ClosestCost(ray, 1, math.MaxFloat64)
+	if view.Mode == ModeBVHCost {
+		return utils.Vec3{}, float64(cost), true
+	}
+	if !found {
+		return utils.Vec3{}, 0, false
+	}
+	switch view.Mode {
+	case ModeNormals:
+		return encode(hit.Shape.Normal(hit)), 0, true
+	case ModeGeometricNormals:
+		return encode(shape.GeometricNormal(hit)), 0, true
+	case ModeBarycentrics:
+		if len(hit.BCoords) == 3 {
+			return utils.Vec3{hit.BCoords[0], hit.BCoords[1], hit.BCoords[2]}, 0, true
+		}
+	case ModeUV:
+		if len(hit.UV) >= 2 {
+			// repeating textures wrap as well
+			u, v := hit.UV[0]-math.Floor(hit.UV[0]), hit.UV[1]-math.Floor(hit.UV[1])
+			return utils.Vec3{u, v, 0}, 0, true
+		}
+	case ModeDepth:
+		return utils.Vec3{}, hit.Point.Sub(ray.Origin).Length(), true
+	case ModeObjectID:
+		return falseColor(hit.Index), 0, true
+	case ModeTriangleID:
+		return falseColor(hit.Index, hit.Triangle), 0, true
+	case ModeFacing:
+		facing := -hit.Shape.Normal(hit).Dot(ray.Direction.Normalize())
+		if facing < 0 {
+			// back faces in red
+			return utils.Vec3{-facing, 0, 0}, 0, true
+		}
+		return utils.Vec3{facing, facing, facing}, 0, true
+	}
+	return utils.Vec3{}, 0, true
+}
+
+// Run is a function to run the DebugView.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the colored screen.
+//
+func (view *DebugView) Run() *screen.ColoredScreen {
+	width, height := view.PixelScreen.Width, view.PixelScreen.Height
+	colors := make([][]utils.Vec3, height)
+	values := make([][]float64, height)
+	hits := make([][]bool, height)
+	low, high := math.Inf(1), math.Inf(-1)
+	origin := entity.PointToVec3(&view.Cam.Pos)
+	for i := 0; i < height; i++ {
+		colors[i] = make([]utils.Vec3, width)
+		values[i] = make([]float64, width)
+		hits[i] = make([]bool, width)
+		for j := 0; j < width; j++ {
+			// the pixel is on the screen and CamToWorld is checked by InitDebugView, ignoring the errors
+			screenV, _ := view.PixelScreen.PixelToWorld(j, i, 1.0, 0.5, 0.5, view.Cam.FieldOfView)
+			direction, _ := utils.VectorToVec3(&screenV)
+			ray := entity.Ray{Origin: origin, Direction: direction}
+			colors[i][j], values[i][j], hits[i][j] = view.shade(ray)
+			if hits[i][j] {
+				low = math.Min(low, values[i][j])
+				high = math.Max(high, values[i][j])
+			}
+		}
+	}
+	coloredScreen := screen.InitColoredScreen(width, height)
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			color := colors[i][j]
+			switch {
+			case view.Mode == ModeDepth && hits[i][j]:
+				// the closest hit in white, the farthest in dark gray
+				gray := 1.0
+				if high > low {
+					gray = 1 - (1-farthestDepth)*(values[i][j]-low)/(high-low)
+				}
+				color = utils.Vec3{gray, gray, gray}
+			case view.Mode == ModeBVHCost:
+				color = heat(values[i][j] / math.Max(high, 1))
+			}
+			intColor := make([]int, 3)
+			for k := range intColor {
+				// the data is shown without gamma
+				intColor[k] = int(math.Round(math.Max(0, math.Min(1, color[k])) * 255))
+			}
+			coloredScreen.Colors[i][j] = intColor
+		}
+	}
+	return &coloredScreen
+}
+
+// InitDebugView is a function to initialize a DebugView.
+//
+// Parameters:
+// 	objs        - the list of objects.
+//  pixelScreen - the screen.
+//  cam         - the camera.
+//  mode        - the data shown, one of Modes.
+//
+// Returns:
+// 	a DebugView.
+//  an error if the mode is unknown or the shapes of the objects could not be built.
+//
+func InitDebugView(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera, mode string) (DebugView, error) {
+	if !IsMode(mode) {
+		return DebugView{}, fmt.Errorf("unknown debug mode %q", mode)
+	}
+	if err := pixelScreen.CheckIntegrity(); err != nil {
+		return DebugView{}, utils.WrapField("PixelScreen", err)
+	}
+	shapes, err := shape.FromObjects(objs)
+	if err != nil {
+		return DebugView{}, err
+	}
+	return DebugView{
+		Objs:        objs,
+		PixelScreen: pixelScreen,
+		Cam:         cam,
+		Mode:        mode,
+		shapes:      shapes,
+	}, nil
+}
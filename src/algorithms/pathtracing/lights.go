@@ -0,0 +1,180 @@
+package pathtracing
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// shadowEpsilon is the fraction of the distance to a light not tested by shadow rays, so they do not hit the light.
+const shadowEpsilon = 1e-6
+
+// bounce is a class for the scattering that created a ray, used to weight the lights it hits.
+//
+// Members:
+//  point  - the origin of the ray.
+//  normal - the normal at the origin.
+//  pdf    - solid angle density of the diffuse direction, 0 when the lights were not sampled
+//           at the origin (rays of the camera and specular reflections).
+//
+type bounce struct {
+	point  utils.Vec3
+	normal utils.Vec3
+	pdf    float64
+}
+
+// powerHeuristic is a function to get the multiple importance sampling weight of a strategy.
+//
+// Parameters:
+//  pdf      - the density of the strategy.
+//  otherPdf - the density of the other strategy.
+//
+// Returns:
+//  pdf² / (pdf² + otherPdf²).
+//
+func powerHeuristic(pdf, otherPdf float64) float64 {
+	if pdf == 0 {
+		return 0
+	}
+	return pdf * pdf / (pdf*pdf + otherPdf*otherPdf)
+}
+
+// misWeight is a function to get the weight of a light hit by a ray, since the light could also
+// have been sampled at the origin of the ray.
+//
+// Parameters:
+//  from - the scattering that created the ray.
+//  lgt  - the light hit.
+//  dir  - the direction of the ray.
+//
+// Returns:
+//  the weight, 1 when the light was not sampled at the origin.
+//
+func (ptracer *PathTracer) misWeight(from bounce, lgt light.Light, dir utils.Vec3) float64 {
+	if from.pdf == 0 || lgt.IsDelta() {
+		return 1
+	}
+	lightPdf := lgt.PDF(from.point, dir) * ptracer.sampler.PMF(from.point, from.normal, lgt)
+	return powerHeuristic(from.pdf, lightPdf)
+}
+
+// sampleLights is a function to estimate the light arriving directly at a diffuse point from a light chosen
+// by the light sampler.
+//
+// Parameters:
+//  point  - the shaded point.
+//  normal - the normal at the point.
+//
+// Returns:
+//  the incoming light weighted as the diffuse reflections, before the albedo.
+//
+func (ptracer *PathTracer) sampleLights(point, normal utils.Vec3) utils.Vec3 {
+	lgt, pmf := ptracer.sampler.Sample(point, normal, rand.Float64())
+	if lgt == nil || pmf == 0 {
+		return utils.Vec3{}
+	}
+	sample, ok := lgt.Sample(point, rand.Float64(), rand.Float64())
+	if !ok {
+		return utils.Vec3{}
+	}
+	cosTheta := normal.Dot(sample.Dir)
+	if cosTheta <= 0 || ptracer.occluded(point, sample, lgt) {
+		return utils.Vec3{}
+	}
+	pdf := sample.PDF * pmf
+	diffusePdf := cosTheta / math.Pi
+	weight := 1.0
+	if !lgt.IsDelta() {
+		weight = powerHeuristic(pdf, diffusePdf)
+	}
+	return sample.Radiance.Scale(diffusePdf * weight / pdf)
+}
+
+// occluded is a function to check if an object or another light is between a point and a light sample.
+//
+// Parameters:
+//  point  - the shaded point.
+//  sample - the light sample.
+//  lgt    - the sampled light.
+//
+// Returns:
+//  true if the sample is not visible.
+//
+func (ptracer *PathTracer) occluded(point utils.Vec3, sample light.Sample, lgt light.Light) bool {
+	ray := entity.Ray{Origin: point, Direction: sample.Dir}
+	tMax := math.MaxFloat64
+	if !math.IsInf(sample.Dist, 1) {
+		tMax = sample.Dist * (1 - shadowEpsilon)
+	}
+	if _, found := ptracer.shapes.Closest(ray, 0, tMax); found {
+		return true
+	}
+	for _, other := range ptracer.Lgts.LightList {
+		if other == lgt {
+			continue
+		}
+		if _, found := other.Intersect(ray, 0, tMax); found {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLightSampler is a function to change the strategy choosing the light sampled at the diffuse hits.
+//
+// Parameters:
+//  kind - light.SamplerUniform, light.SamplerPower or light.SamplerBVH.
+//
+// Returns:
+//  an error if the strategy is unknown.
+//
+func (ptracer *PathTracer) SetLightSampler(kind string) error {
+	sampler, err := light.InitSampler(kind, ptracer.lights, sceneRadius(ptracer.shapes, ptracer.lights))
+	if err != nil {
+		return err
+	}
+	ptracer.sampler = sampler
+	return nil
+}
+
+// sceneRadius is a function to get the radius of the sphere around the bounded shapes and lights.
+//
+// Parameters:
+//  shapes - the shapes.
+//  lights - the lights.
+//
+// Returns:
+//  the radius, 1 when nothing is bounded or everything is a single point.
+//
+func sceneRadius(shapes shape.List, lights []light.Light) float64 {
+	bb := []float64{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	merge := func(sbb []float64) {
+		for _, value := range sbb {
+			if math.IsInf(value, 0) {
+				return
+			}
+		}
+		for j := 0; j < 3; j++ {
+			bb[j] = math.Min(bb[j], sbb[j])
+			bb[j+3] = math.Max(bb[j+3], sbb[j+3])
+		}
+	}
+	for _, s := range shapes.Shapes {
+		merge(s.Bounds())
+	}
+	for _, lgt := range lights {
+		merge(lgt.Bounds())
+	}
+	if bb[0] > bb[3] {
+		return 1
+	}
+	halfSize := utils.Vec3{bb[3] - bb[0], bb[4] - bb[1], bb[5] - bb[2]}.Scale(0.5)
+	if radius := math.Sqrt(halfSize.Dot(halfSize)); radius > 0 {
+		return radius
+	}
+	return 1
+}
@@ -4,56 +4,52 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 	"sync"
+	"time"
 
 	"github.com/lucas625/Projeto-CG/src/camera"
 	"github.com/lucas625/Projeto-CG/src/entity"
 	"github.com/lucas625/Projeto-CG/src/general"
 	"github.com/lucas625/Projeto-CG/src/light"
 	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/shape"
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
-type Locker struct {
-	threads int
-	sync.Mutex
-}
-
-func (lock *Locker) getThreads() int {
-	lock.Lock()
-	defer lock.Unlock()
-	return lock.threads
-}
-
-func (lock *Locker) addThreads() {
-	lock.Lock()
-	defer lock.Unlock()
-	lock.threads += 1
-}
-
-func (lock *Locker) removeThreads() {
-	lock.Lock()
-	defer lock.Unlock()
-	lock.threads -= 1
-}
+// DefaultThreads is the number of goroutines tracing the lines of the screen.
+const DefaultThreads = 12
 
 // PathTracer is a class for path tracing algorithm.
 //
 // Members:
-// 	Objs        - the list of objects.
-//  PixelScreen - the screen.
-//  Cam         - the camera.
-//  Lgts        - the lights.
+// 	Objs          - the list of objects.
+//  PixelScreen   - the screen.
+//  Cam           - the camera.
+//  Lgts          - the lights.
+//  Threads       - number of goroutines tracing the lines of the screen, DefaultThreads when 0 or less.
+//  shapes        - the shapes of the objects.
+//  lights        - the lights sampled at the diffuse hits, the Lgts followed by the emissive objects.
+//  emitters      - the lights of the emissive objects by their shape.
+//  sampler       - the strategy choosing the light sampled at the diffuse hits.
+//  lightCenter   - the center of the first light when it is an area light, target of specular reflections.
+//  specularLight - the first light when it is not an area light, sampled as target of specular reflections.
 //
 type PathTracer struct {
-	Objs        *general.Objects
-	PixelScreen *screen.Screen
-	Cam         *camera.Camera
-	Lgts        *light.Lights
+	Objs          *general.Objects
+	PixelScreen   *screen.Screen
+	Cam           *camera.Camera
+	Lgts          *light.Lights
+	Threads       int
+	shapes        shape.List
+	lights        []light.Light
+	emitters      map[shape.Shape]light.Light
+	sampler       light.Sampler
+	lightCenter   utils.Vec3
+	specularLight light.Light
 }
 
-// RandomInSemiSphere is a function to find a ray for diffuse reflection in semisphere.
+// RandomInSemiSphere is a function to find a ray for diffuse reflection in semisphere, cosine weighted
+// (a point on the unit sphere around the tip of the normal).
 //
 // Parameters:
 //  normal - the normal.
@@ -61,32 +57,11 @@ type PathTracer struct {
 // Returns:
 // 	the vector.
 //
-func RandomInSemiSphere(normal utils.Vector, pos entity.Point) utils.Vector {
-	
-	found := false
-
-	var random1,random2,random3 float64
-	var v utils.Vector
-	for !found {
-		found = true
-		random1 = rand.Float64()
-		random2 = rand.Float64()
-		random3 = rand.Float64()
-
-		v = utils.Vector{Coordinates: []float64{random1, random2, random3}}
-		v = utils.CMultVector(&v, 2)
-		vaux := utils.Vector{Coordinates: []float64{1,1,1}}
-		v = utils.SumVector(&v, &vaux, 1, -1)
-		if math.Pow(utils.VectorNorm(&v),2) >= 1{
-			found = false
-		}
-	}
-	vect := utils.SumVector(&normal, &v, 1, 1)
-	vect = utils.NormalizeVector(&vect)
-	return vect
+func RandomInSemiSphere(normal utils.Vec3) utils.Vec3 {
+	return normal.Add(RandomInSemiSphereSpecular().Normalize()).Normalize()
 }
 
-// RandomInSemiSphereSpecular is a function to find a ray for diffuse reflection in semisphere.
+// RandomInSemiSphereSpecular is a function to find a random vector inside the unit sphere.
 //
 // Parameters:
 //  none
@@ -94,74 +69,78 @@ func RandomInSemiSphere(normal utils.Vector, pos entity.Point) utils.Vector {
 // Returns:
 // 	the vector.
 //
-func RandomInSemiSphereSpecular() utils.Vector {
-	
-	found := false
-
-	var random1,random2,random3 float64
-	var v utils.Vector
-	for !found {
-		found = true
-		random1 = rand.Float64()
-		random2 = rand.Float64()
-		random3 = rand.Float64()
-
-		v = utils.Vector{Coordinates: []float64{random1, random2, random3}}
-		v = utils.CMultVector(&v, 2)
-		vaux := utils.Vector{Coordinates: []float64{1,1,1}}
-		v = utils.SumVector(&v, &vaux, 1, -1)
-		if math.Pow(utils.VectorNorm(&v),2) >= 1{
-			found = false
+func RandomInSemiSphereSpecular() utils.Vec3 {
+	for {
+		v := utils.Vec3{2*rand.Float64() - 1, 2*rand.Float64() - 1, 2*rand.Float64() - 1}
+		if v.Dot(v) < 1 {
+			return v
 		}
 	}
-	return v
 }
 
-// FindNextRay is a function to find the next line.
+// FindNextRay is a function to find the next ray.
 //
 // Parameters:
-//  pos       - the point.
-//  obj       - the object.
-//  triangIdx - the index of the triangle.
-//  bCoords   - baricentric coords for each respective normal.
+//  hit - the intersection.
 //
 // Returns:
-// 	the line
+// 	the ray.
 //
-func (ptracer *PathTracer) FindNextRay(pos entity.Point, obj general.Object, triangleIdx int, bCoords []float64) entity.Line {
-	normals := make([]utils.Vector, 3)
-	for i := 0; i < 3; i++ {
-		normals[i] = obj.Normals[obj.Triangles[triangleIdx].Normals[i]]
-	}
-	resultingNormal := utils.SumVector(&normals[0], &normals[1], bCoords[0], bCoords[1])
-	resultingNormal = utils.SumVector(&resultingNormal, &normals[2], 1, bCoords[2])
-	resultingNormal = utils.NormalizeVector(&resultingNormal)
+func (ptracer *PathTracer) FindNextRay(hit shape.Hit) entity.Ray {
+	ray, _ := ptracer.nextRay(hit)
+	return ray
+}
+
+// nextRay is a function to find the next ray and the lobe that chose it.
+//
+// Parameters:
+//  hit - the intersection.
+//
+// Returns:
+// 	the ray.
+//  true if the ray is a diffuse reflection.
+//
+func (ptracer *PathTracer) nextRay(hit shape.Hit) (entity.Ray, bool) {
+	pos := hit.Point
+	obj := hit.Object
+	resultingNormal := hit.Shape.Normal(hit)
 
 	ktot := obj.DiffuseReflection + obj.SpecularReflection // + obj.TransReflection
 	r := 0.0 + rand.Float64()*ktot
-	vector := utils.Vector{Coordinates: []float64{1.0, 1.0, 1.0}}
+	vector := utils.Vec3{1.0, 1.0, 1.0}
 	if r <= obj.DiffuseReflection {
-		vector = RandomInSemiSphere(resultingNormal, pos)
+		vector = RandomInSemiSphere(resultingNormal)
+		return entity.Ray{Origin: pos, Direction: vector.Normalize()}, true
 	} else if r <= obj.DiffuseReflection+obj.SpecularReflection {
-		lightPos := ptracer.Lgts.LightList[0].LightObject.GetCenter()
-		Lvector := entity.ExtractVector(&pos, &lightPos)
-		Lvector = utils.NormalizeVector(&Lvector)
-
-		constantPart := 2 * utils.DotProduct(&resultingNormal, &Lvector)
+		Lvector := ptracer.specularDirection(pos)
 
-		vector = utils.SumVector(&resultingNormal, &Lvector, constantPart, -1) // R = 2N(N.L) - L
+		constantPart := 2 * resultingNormal.Dot(Lvector)
 
-		offsetVector := RandomInSemiSphereSpecular()
-		offsetVector = utils.CMultVector(&offsetVector, obj.RoughNess)
+		vector = resultingNormal.Scale(constantPart).Sub(Lvector) // R = 2N(N.L) - L
 
-		vector = utils.SumVector(&vector, &offsetVector, 1, 1)
+		vector = vector.AddScaled(RandomInSemiSphereSpecular(), obj.RoughNess)
 
 	} else {
 		// use transmission (unavailable)
 	}
-	vector = utils.NormalizeVector(&vector)
-	line := entity.Line{Start: pos, Director: vector}
-	return line
+	return entity.Ray{Origin: pos, Direction: vector.Normalize()}, false
+}
+
+// specularDirection is a function to get the direction of the first light, the target of specular reflections.
+//
+// Parameters:
+//  pos - the reflecting point.
+//
+// Returns:
+//  the normalized direction.
+//
+func (ptracer *PathTracer) specularDirection(pos utils.Vec3) utils.Vec3 {
+	if ptracer.specularLight != nil {
+		if sample, ok := ptracer.specularLight.Sample(pos, 0.5, 0.5); ok {
+			return sample.Dir
+		}
+	}
+	return ptracer.lightCenter.Sub(pos).Normalize()
 }
 
 // TraceRayDepth is a function to trace a ray and return the resulting color.
@@ -172,67 +151,83 @@ func (ptracer *PathTracer) FindNextRay(pos entity.Point, obj general.Object, tri
 //
 // Returns:
 // 	the rgb color at a given position.
+// 	a *utils.SizeError if the line is not 3D.
 //
-func (ptracer *PathTracer) TraceRayDepth(line entity.Line, recursions int) []float64 {
-	color := make([]float64, 3)
-
-	closestT := math.MaxFloat64
-	closestObjIdx := -1
-	closestTriangleIndex := -1
-	closestBCoords := make([]float64, 3)
-	for objIdx, obj := range ptracer.Objs.ObjList { // iterating through all objects
-		for triangIdx, triangle := range obj.Triangles { // iterating through all triangles of an object
-			points := make([]entity.Point, 3)
-			for pi := 0; pi < 3; pi++ { // getting triangle points
-				points[pi] = obj.Vertices.Points[triangle.Vertices[pi]]
-			}
-			t, bCoords, intersected := line.IntersectTriangle(points)
-			if intersected {
-				if t > 0 && t < closestT {
-					closestT = t
-					closestObjIdx = objIdx
-					closestTriangleIndex = triangIdx
-					closestBCoords = bCoords
-				}
-			}
-		}
+func (ptracer *PathTracer) TraceRayDepth(line entity.Line, recursions int) ([]float64, error) {
+	ray, err := line.ToRay()
+	if err != nil {
+		return nil, err
 	}
+	color := ptracer.shade(ray, 0, recursions, utils.Vec3{1, 1, 1}, bounce{})
+	return color[:], nil
+}
 
+// shade is a function to intersect a ray with the objects and lights and return the resulting color.
+// Diffuse hits with recursions left add the direct light of a sampled light, the lights and emissive
+// objects hit by their next ray are weighted by multiple importance sampling.
+//
+// Parameters:
+//  ray        - the ray.
+//  tMin       - the smallest t accepted, 1 for rays leaving the camera so they start on the screen.
+//  recursions - number of recursions.
+//  lastColor  - the color reflected when there are no recursions left.
+//  from       - the scattering that created the ray.
+//
+// Returns:
+// 	the rgb color.
+//
+func (ptracer *PathTracer) shade(ray entity.Ray, tMin float64, recursions int, lastColor utils.Vec3, from bounce) utils.Vec3 {
+	var color utils.Vec3
+
+	hit, found := ptracer.shapes.Closest(ray, tMin, math.MaxFloat64)
+	tMax := math.MaxFloat64
+	if found {
+		// lights at the same distance are still visible
+		tMax = math.Nextafter(hit.T, math.MaxFloat64)
+	}
 	// intersecting lights
-	lightClosest := false
-	for lgtIdx, lgt := range ptracer.Lgts.LightList {
-		for _, triangle := range lgt.LightObject.Triangles { // iterating through all triangles of an object
-			points := make([]entity.Point, 3)
-			for pi := 0; pi < 3; pi++ { // getting triangle points
-				points[pi] = lgt.LightObject.Vertices.Points[triangle.Vertices[pi]]
-			}
-			t, _, intersected := line.IntersectTriangle(points)
-			if intersected {
-				if t > 0 && t <= closestT {
-					lightClosest = true
-					closestT = t
-					closestObjIdx = lgtIdx
-				}
-			}
+	var lightHit light.Light
+	for _, lgt := range ptracer.Lgts.LightList {
+		if t, ok := lgt.Intersect(ray, tMin, tMax); ok {
+			tMax = t
+			lightHit = lgt
 		}
 	}
 
-	if !lightClosest {
-		if closestObjIdx != -1 {
-			colorAux := []float64{1, 1, 1}
-			if recursions > 0 {
-				newLine := ptracer.FindNextRay(line.FindPos(closestT), ptracer.Objs.ObjList[closestObjIdx], closestTriangleIndex, closestBCoords)
-				colorAux = ptracer.TraceRayDepth(newLine, recursions-1)
-			}
-			for i := 0; i < 3; i++ {
-				color[i] = ptracer.Objs.ObjList[closestObjIdx].Color[i] * colorAux[i]
+	if lightHit != nil {
+		return lightHit.Radiance(ray.Direction).Scale(ptracer.misWeight(from, lightHit, ray.Direction))
+	}
+	if !found {
+		// escaping rays reach the lights at infinity
+		for _, lgt := range ptracer.Lgts.LightList {
+			if lgt.IsInfinite() {
+				color = color.Add(lgt.Radiance(ray.Direction).Scale(ptracer.misWeight(from, lgt, ray.Direction)))
 			}
 		}
-	} else {
-		for i := 0; i < 3; i++ {
-			lgtAux := ptracer.Lgts.LightList[closestObjIdx]
-			color[i] = lgtAux.Color[i] * lgtAux.LightIntensity
+		return color
+	}
+	if emission := hit.Object.GetEmission(); emission != nil {
+		weight := 1.0
+		if lgt, ok := ptracer.emitters[hit.Shape]; ok {
+			weight = ptracer.misWeight(from, lgt, ray.Direction)
 		}
+		color = utils.Vec3{emission[0], emission[1], emission[2]}.Scale(weight)
+	}
+	colorAux := lastColor
+	if recursions > 0 {
+		next, diffuse := ptracer.nextRay(hit)
+		nextFrom := bounce{}
+		colorAux = utils.Vec3{}
+		if diffuse {
+			normal := hit.Shape.Normal(hit)
+			nextFrom = bounce{point: hit.Point, normal: normal, pdf: math.Max(0, normal.Dot(next.Direction)) / math.Pi}
+			colorAux = ptracer.sampleLights(hit.Point, normal)
+		}
+		colorAux = colorAux.Add(ptracer.shade(next, 0, recursions-1, utils.Vec3{1, 1, 1}, nextFrom))
+	}
+	albedo := hit.Object.GetAlbedo(hit.UV, entity.Vec3ToPoint(hit.Point), entity.Vec3ToPoint(hit.Local))
+	for i := 0; i < 3; i++ {
+		color[i] += albedo[i] * colorAux[i]
 	}
 	return color
 }
@@ -249,103 +244,35 @@ func (ptracer *PathTracer) TraceRayDepth(line entity.Line, recursions int) []flo
 // 	the colored screen painted at that position.
 //
 func (ptracer *PathTracer) TraceRay(lp, cp, rays, recursions int) []int {
-	floatColors := make([][]float64, rays)
-	lock := Locker{threads:0}
-	for ray := 0; ray < rays; ray++ {
-		if lock.getThreads() < 12 {
-			lock.addThreads()
-			go func(inRay int) {
-				offx := rand.Float64()
-				offy := rand.Float64()
-
-				screenV := ptracer.PixelScreen.PixelToWorld(lp, cp, 1.0, offx, offy, ptracer.Cam.FieldOfView)
-				line := entity.Line{Start: ptracer.Cam.Pos, Director: screenV}
-
-				color := make([]float64, 3)
-
-				closestT := math.MaxFloat64
-				closestObjIdx := -1
-				closestTriangleIndex := -1
-				closestBCoords := make([]float64, 3)
-				
-				for objIdx, obj := range ptracer.Objs.ObjList { // iterating through all objects
-					for triangIdx, triangle := range obj.Triangles { // iterating through all triangles of an object
-						points := make([]entity.Point, 3)
-						for pi := 0; pi < 3; pi++ { // getting triangle points
-							points[pi] = obj.Vertices.Points[triangle.Vertices[pi]]
-						}
-						t, bCoords, intersected := line.IntersectTriangle(points)
-						if intersected {
-							if t >= 1 && t < closestT {
-								closestT = t
-								closestObjIdx = objIdx
-								closestTriangleIndex = triangIdx
-								closestBCoords = bCoords
-							}
-						}
-					}
-				}
-
-				// intersecting lights
-				lightClosest := false
-				for lgtIdx, lgt := range ptracer.Lgts.LightList {
-					for _, triangle := range lgt.LightObject.Triangles { // iterating through all triangles of an object
-						points := make([]entity.Point, 3)
-						for pi := 0; pi < 3; pi++ { // getting triangle points
-							points[pi] = lgt.LightObject.Vertices.Points[triangle.Vertices[pi]]
-						}
-						t, _, intersected := line.IntersectTriangle(points)
-						if intersected {
-							if t >= 1 && t <= closestT {
-								lightClosest = true
-								closestT = t
-								closestObjIdx = lgtIdx
-							}
-						}
-					}
-				}
+	origin := entity.PointToVec3(&ptracer.Cam.Pos)
+	return ptracer.traceLines(rays, recursions, func() entity.Ray {
+		offx := rand.Float64()
+		offy := rand.Float64()
+
+		// the pixel is on the screen and CamToWorld is checked by InitPathTracer, ignoring the errors
+		screenV, _ := ptracer.PixelScreen.PixelToWorld(lp, cp, 1.0, offx, offy, ptracer.Cam.FieldOfView)
+		direction, _ := utils.VectorToVec3(&screenV)
+		return entity.Ray{Origin: origin, Direction: direction}
+	})
+}
 
-				if !lightClosest {
-					if closestObjIdx != -1 {
-						colorAux := []float64{0, 0, 0}
-						if recursions > 0 {
-							newLine := ptracer.FindNextRay(line.FindPos(closestT), ptracer.Objs.ObjList[closestObjIdx], closestTriangleIndex, closestBCoords)
-							colorAux = ptracer.TraceRayDepth(newLine, recursions-1)
-						}
-						for i := 0; i < 3; i++ {
-							color[i] = ptracer.Objs.ObjList[closestObjIdx].Color[i] * colorAux[i]
-						}
-					}
-				} else {
-					for i := 0; i < 3; i++ {
-						lgtAux := ptracer.Lgts.LightList[closestObjIdx]
-						color[i] = lgtAux.Color[i] * lgtAux.LightIntensity
-					}
-				}
-				floatColors[inRay] = color
-				lock.removeThreads()
-			}(ray)
-		} else {
-			time.Sleep(20)
-			ray--
-		}
-	}
-	ended := false
-	for !ended {
-		if lock.getThreads() == 0 {
-			ended = true
-		} else {
-			time.Sleep(25)
-		}
+// traceLines is a function to trace many primary rays and average their colors.
+//
+// Parameters:
+//  rays       - number of rays.
+//  recursions - number of recursions.
+//  nextRay    - function generating each primary ray.
+//
+// Returns:
+// 	the averaged rgb color.
+//
+func (ptracer *PathTracer) traceLines(rays, recursions int, nextRay func() entity.Ray) []int {
+	var color utils.Vec3
+	for ray := 0; ray < rays; ray++ {
+		color = color.Add(ptracer.shade(nextRay(), 1, recursions, utils.Vec3{0, 0, 0}, bounce{}))
 	}
 
 	// calculating average
-	color := make([]float64, 3)
-	for i := 0; i < rays; i++ {
-		for j := 0; j < 3; j++ {
-			color[j] = color[j] + floatColors[i][j]
-		}
-	}
 
 	intColor := make([]int, 3)
 	for i := 0; i < 3; i++ {
@@ -369,17 +296,67 @@ func (ptracer *PathTracer) TraceRay(lp, cp, rays, recursions int) []int {
 // 	the colored screen.
 //
 func (ptracer *PathTracer) Run(rays, recursions int) *screen.ColoredScreen {
-	coloredScreen := screen.InitColoredScreen(ptracer.PixelScreen.Width, ptracer.PixelScreen.Height)
-	for i := 0; i < ptracer.PixelScreen.Height; i++ {
-		for j := 0; j < ptracer.PixelScreen.Width; j++ {
-			
-			coloredScreen.Colors[i][j] = ptracer.TraceRay(j, i, rays, recursions)
-		}
-		fmt.Println(float64(i)/float64(ptracer.PixelScreen.Height),"%")
+	return ptracer.runPixels(func(i, j int) []int {
+		return ptracer.TraceRay(j, i, rays, recursions)
+	})
+}
+
+// runPixels is a function to paint every pixel of the screen, printing the progress of each line.
+// The lines are sent through a channel to the goroutines given by threads.
+//
+// Parameters:
+//  pixel - function getting the color of the pixel at a line and column.
+//
+// Returns:
+// 	the colored screen.
+//
+func (ptracer *PathTracer) runPixels(pixel func(i, j int) []int) *screen.ColoredScreen {
+	width := ptracer.PixelScreen.Width
+	height := ptracer.PixelScreen.Height
+	coloredScreen := screen.InitColoredScreen(width, height)
+	lines := make(chan int, height)
+	for i := 0; i < height; i++ {
+		lines <- i
+	}
+	close(lines)
+
+	var wg sync.WaitGroup
+	var progress sync.Mutex
+	done := 0
+	for worker := 0; worker < ptracer.threads(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range lines {
+				for j := 0; j < width; j++ {
+					coloredScreen.Colors[i][j] = pixel(i, j)
+				}
+				progress.Lock()
+				done++
+				fmt.Printf("%.1f%%\n", 100*float64(done)/float64(height))
+				progress.Unlock()
+			}
+		}()
 	}
+	wg.Wait()
 	return &coloredScreen
 }
 
+// threads is a function to get the number of goroutines tracing the lines of the screen.
+//
+// Parameters:
+//  none
+//
+// Returns:
+// 	Threads, or DefaultThreads when it is 0 or less.
+//
+func (ptracer *PathTracer) threads() int {
+	if ptracer.Threads <= 0 {
+		return DefaultThreads
+	}
+	return ptracer.Threads
+}
+
 // InitPathTracer is a function to initialize a PathTracer.
 //
 // Parameters:
@@ -390,8 +367,50 @@ func (ptracer *PathTracer) Run(rays, recursions int) *screen.ColoredScreen {
 //
 // Returns:
 // 	a PathTracer.
+//  an error if the shapes of the objects could not be built or a light is invalid.
 //
-func InitPathTracer(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera, lgts *light.Lights) PathTracer {
+func InitPathTracer(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera, lgts *light.Lights) (PathTracer, error) {
 	rand.Seed(time.Now().UnixNano())
-	return PathTracer{Objs: objs, PixelScreen: pixelScreen, Cam: cam, Lgts: lgts}
+	if err := pixelScreen.CheckIntegrity(); err != nil {
+		return PathTracer{}, utils.WrapField("PixelScreen", err)
+	}
+	shapes, err := shape.FromObjects(objs)
+	if err != nil {
+		return PathTracer{}, err
+	}
+	if err = lgts.Prepare(); err != nil {
+		return PathTracer{}, err
+	}
+	// registering the emissive objects
+	emissive, emitters := light.InitEmissiveLights(objs, shapes)
+	lights := append(append([]light.Light{}, lgts.LightList...), emissive...)
+	var lightCenter utils.Vec3
+	var specularLight light.Light
+	if len(lgts.LightList) > 0 {
+		if area, ok := lgts.LightList[0].(*light.AreaLight); ok {
+			center := area.LightObject.GetCenter()
+			lightCenter = entity.PointToVec3(&center)
+		} else {
+			specularLight = lgts.LightList[0]
+		}
+	} else if len(lights) > 0 {
+		specularLight = lights[0]
+	}
+	sampler, err := light.InitSampler(light.SamplerPower, lights, sceneRadius(shapes, lights))
+	if err != nil {
+		return PathTracer{}, err
+	}
+	return PathTracer{
+		Objs:          objs,
+		PixelScreen:   pixelScreen,
+		Cam:           cam,
+		Lgts:          lgts,
+		Threads:       DefaultThreads,
+		shapes:        shapes,
+		lights:        lights,
+		emitters:      emitters,
+		sampler:       sampler,
+		lightCenter:   lightCenter,
+		specularLight: specularLight,
+	}, nil
 }
@@ -0,0 +1,36 @@
+package pathtracing
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/screen"
+)
+
+func TestRunPixelsThreads(t *testing.T) {
+	sc, err := screen.InitScreen(7, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, threads := range []int{-1, 0, 1, 3, 64} {
+		ptracer := PathTracer{PixelScreen: &sc, Threads: threads}
+		colored := ptracer.runPixels(func(i, j int) []int {
+			return []int{i, j, 1}
+		})
+		for i := range colored.Colors {
+			for j, color := range colored.Colors[i] {
+				if len(color) != 3 || color[0] != i || color[1] != j || color[2] != 1 {
+					t.Fatalf("%d threads: pixel (%d, %d) got %v", threads, i, j, color)
+				}
+			}
+		}
+	}
+}
+
+func TestThreadsDefault(t *testing.T) {
+	for threads, want := range map[int]int{-2: DefaultThreads, 0: DefaultThreads, 1: 1, 30: 30} {
+		ptracer := PathTracer{Threads: threads}
+		if got := ptracer.threads(); got != want {
+			t.Errorf("Threads %d: got %d goroutines, want %d", threads, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package pathtracing
+
+import (
+	"math/rand"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/screen"
+)
+
+// runEye is a function to render the image of a single eye of a stereo rig.
+//
+// Parameters:
+//  rig        - the stereo rig.
+//  eye        - camera.LeftEye or camera.RightEye.
+//  rays       - number of rays per pixel.
+//  recursions - number of recursions.
+//
+// Returns:
+// 	the colored screen of the eye.
+//
+func (ptracer *PathTracer) runEye(rig *camera.StereoRig, eye, rays, recursions int) *screen.ColoredScreen {
+	if rig.Mode != camera.StereoOmnidirectional {
+		eyeCam := rig.EyeCamera(eye)
+		camMatrix := camera.CamToWorld(&eyeCam)
+		eyeScreen := *ptracer.PixelScreen
+		eyeScreen.CamToWorld = &camMatrix
+		eyeScreen.Shift = rig.EyeShift(eye)
+		eyeTracer := *ptracer
+		eyeTracer.Cam = &eyeCam
+		eyeTracer.PixelScreen = &eyeScreen
+		return eyeTracer.Run(rays, recursions)
+	}
+
+	width := ptracer.PixelScreen.Width
+	height := ptracer.PixelScreen.Height
+	return ptracer.runPixels(func(i, j int) []int {
+		return ptracer.traceLines(rays, recursions, func() entity.Ray {
+			ray, _ := rig.ODSLine(eye, j, i, rand.Float64(), rand.Float64(), width, height).ToRay() // the line is 3D
+			return ray
+		})
+	})
+}
+
+// RunStereo is a function to run the path tracing for both eyes of a stereo rig.
+//
+// Parameters:
+//  rig        - the stereo rig, its camera replaces the tracer camera.
+//  rays       - number of rays per pixel.
+//  recursions - number of recursions.
+//
+// Returns:
+// 	the colored screens of the left and right eyes.
+//
+func (ptracer *PathTracer) RunStereo(rig *camera.StereoRig, rays, recursions int) (*screen.ColoredScreen, *screen.ColoredScreen) {
+	left := ptracer.runEye(rig, camera.LeftEye, rays, recursions)
+	right := ptracer.runEye(rig, camera.RightEye, rays, recursions)
+	return left, right
+}
@@ -8,21 +8,45 @@ import (
 	"github.com/lucas625/Projeto-CG/src/general"
 	"github.com/lucas625/Projeto-CG/src/light"
 	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
-// RayCaster is a class for ray casting algorithm.
+// DefaultDepth is the number of reflections and refractions followed by each ray.
+const DefaultDepth = 5
+
+// DefaultLightSamples is the number of samples on each side of the grid of directions of the area,
+// environment and sky lights.
+const DefaultLightSamples = 4
+
+// shadowEpsilon is the fraction of the distance to a light not tested by shadow rays, so they do not hit the light.
+const shadowEpsilon = 1e-6
+
+// RayCaster is a class for the Whitted ray tracing algorithm: a deterministic preview lit by the
+// Phong model, with shadows, mirror reflections and refractions.
 //
 // Members:
-// 	Objs        - the list of objects.
-//  PixelScreen - the screen.
-//  Cam         - the camera.
-//  Lgts        - the lights.
+// 	Objs         - the list of objects.
+//  PixelScreen  - the screen.
+//  Cam          - the camera.
+//  Lgts         - the lights.
+//  Depth        - number of reflections and refractions followed by each ray.
+//  LightSamples - number of samples on each side of the grid of the lights that are not a single point
+//                 or direction.
+//  shapes       - the shapes of the objects.
+//  lights       - the lights shading the objects, the Lgts followed by the emissive objects.
+//  ambient      - the sum of the ambient intensities of the lights times their colors.
 //
 type RayCaster struct {
-	Objs        *general.Objects
-	PixelScreen *screen.Screen
-	Cam         *camera.Camera
-	Lgts        *light.Lights
+	Objs         *general.Objects
+	PixelScreen  *screen.Screen
+	Cam          *camera.Camera
+	Lgts         *light.Lights
+	Depth        int
+	LightSamples int
+	shapes       shape.List
+	lights       []light.Light
+	ambient      utils.Vec3
 }
 
 // TraceRay is a function to trace a ray through a pixel.
@@ -36,54 +60,206 @@ type RayCaster struct {
 // 	the colored screen painted at that position.
 //
 func (rcaster *RayCaster) TraceRay(coloredScreen *screen.ColoredScreen, lp, cp int) {
-	screenV := rcaster.PixelScreen.PixelToWorld(lp, cp, 1.0, 0.5, 0.5, 50)
-	line := entity.Line{Start: rcaster.Cam.Pos, Director: screenV}
-	color := make([]int, 3)
+	// the pixel is on the screen and CamToWorld is checked by InitRayCaster, ignoring the errors
+	screenV, _ := rcaster.PixelScreen.PixelToWorld(cp, lp, 1.0, 0.5, 0.5, rcaster.Cam.FieldOfView)
+	direction, _ := utils.VectorToVec3(&screenV)
+	ray := entity.Ray{Origin: entity.PointToVec3(&rcaster.Cam.Pos), Direction: direction}
+	// rays leaving the camera start on the screen
+	color := rcaster.shade(ray, 1, rcaster.Depth)
+	intColor := make([]int, 3)
+	for i := range intColor {
+		// same gamma as the path tracer
+		intColor[i] = int(math.Floor(math.Sqrt(math.Max(0, color[i])) * 255))
+		if intColor[i] > 255 {
+			intColor[i] = 255
+		}
+	}
+	coloredScreen.Colors[lp][cp] = intColor
+}
 
-	closestT := math.MaxFloat64
-	closestObjIdx := -1
-	// intersecting objects
-	for objIdx, obj := range rcaster.Objs.ObjList { // iterating through all objects
-		for _, triangle := range obj.Triangles { // iterating through all triangles of an object
-			points := make([]entity.Point, 3)
-			for pi := 0; pi < 3; pi++ { // getting triangle points
-				points[pi] = obj.Vertices.Points[triangle.Vertices[pi]]
+// shade is a function to intersect a ray with the objects and lights and return the resulting color.
+//
+// Parameters:
+//  ray   - the ray.
+//  tMin  - the smallest t accepted.
+//  depth - number of reflections and refractions left.
+//
+// Returns:
+// 	the rgb color.
+//
+func (rcaster *RayCaster) shade(ray entity.Ray, tMin float64, depth int) utils.Vec3 {
+	hit, found := rcaster.shapes.Closest(ray, tMin, math.MaxFloat64)
+	tMax := math.MaxFloat64
+	if found {
+		// lights at the same distance are still visible
+		tMax = math.Nextafter(hit.T, math.MaxFloat64)
+	}
+	var lightHit light.Light
+	for _, lgt := range rcaster.Lgts.LightList {
+		if t, ok := lgt.Intersect(ray, tMin, tMax); ok {
+			tMax = t
+			lightHit = lgt
+		}
+	}
+	if lightHit != nil {
+		return lightHit.Radiance(ray.Direction)
+	}
+	var color utils.Vec3
+	if !found {
+		// escaping rays show the lights at infinity
+		for _, lgt := range rcaster.Lgts.LightList {
+			if lgt.IsInfinite() {
+				color = color.Add(lgt.Radiance(ray.Direction))
 			}
-			t, _, intersected := line.IntersectTriangle(points)
-			if intersected {
-				p := line.FindPos(t)
-				if p.Coordinates[2] >= (1+rcaster.Cam.Pos.Coordinates[2]) && t < closestT {
-					closestT = t
-					closestObjIdx = objIdx
+		}
+		return color
+	}
+	obj := hit.Object
+	if emission := obj.GetEmission(); emission != nil {
+		color = utils.Vec3{emission[0], emission[1], emission[2]}
+	}
+	view := ray.Direction.Normalize()
+	normal := hit.Shape.Normal(hit)
+	entering := normal.Dot(view) < 0
+	if !entering {
+		// shading the side seen by the ray
+		normal = normal.Neg()
+	}
+	rgb := obj.GetAlbedo(hit.UV, entity.Vec3ToPoint(hit.Point), entity.Vec3ToPoint(hit.Local))
+	albedo := utils.Vec3{rgb[0], rgb[1], rgb[2]}
+
+	color = color.Add(albedo.Mul(rcaster.ambient).Scale(obj.AmbientReflection))
+	color = color.Add(rcaster.direct(hit.Point, normal, view, albedo, obj))
+	if depth <= 0 {
+		return color
+	}
+	reflected := view.Sub(normal.Scale(2 * normal.Dot(view)))
+	if obj.SpecularReflection > 0 {
+		mirror := rcaster.shade(entity.Ray{Origin: hit.Point, Direction: reflected}, 0, depth-1)
+		color = color.Add(albedo.Mul(mirror).Scale(obj.SpecularReflection))
+	}
+	if obj.TransReflection > 0 {
+		ior := obj.RefractiveIndex
+		if ior == 0 {
+			ior = 1
+		}
+		eta := 1 / ior
+		if !entering {
+			eta = ior
+		}
+		// total internal reflection sends the transmitted light back inside
+		next := reflected
+		if refracted, ok := refract(view, normal, eta); ok {
+			next = refracted
+		}
+		transmitted := rcaster.shade(entity.Ray{Origin: hit.Point, Direction: next}, 0, depth-1)
+		color = color.Add(albedo.Mul(transmitted).Scale(obj.TransReflection))
+	}
+	return color
+}
+
+// refract is a function to bend a direction crossing a surface by Snell's law.
+//
+// Parameters:
+//  dir    - the normalized direction arriving at the surface.
+//  normal - the normalized normal on the side of the arriving direction.
+//  eta    - index of refraction of the arriving side over the one of the other side.
+//
+// Returns:
+//  the normalized refracted direction.
+//  false for a total internal reflection.
+//
+func refract(dir, normal utils.Vec3, eta float64) (utils.Vec3, bool) {
+	cosIn := -normal.Dot(dir)
+	sin2Out := eta * eta * (1 - cosIn*cosIn)
+	if sin2Out > 1 {
+		return utils.Vec3{}, false
+	}
+	cosOut := math.Sqrt(1 - sin2Out)
+	return dir.Scale(eta).Add(normal.Scale(eta*cosIn - cosOut)).Normalize(), true
+}
+
+// direct is a function to get the light arriving directly at a point, reflected by the normalized
+// Phong model: a diffuse lobe tinted by the albedo and a white highlight.
+//
+// Parameters:
+//  point  - the shaded point.
+//  normal - the normal on the side of the viewer.
+//  view   - the normalized direction of the ray arriving at the point.
+//  albedo - the color at the point.
+//  obj    - the object.
+//
+// Returns:
+//  the reflected light.
+//
+func (rcaster *RayCaster) direct(point, normal, view, albedo utils.Vec3, obj *general.Object) utils.Vec3 {
+	var color utils.Vec3
+	if obj.DiffuseReflection == 0 && obj.SpecularReflection == 0 {
+		return color
+	}
+	for _, lgt := range rcaster.lights {
+		side := 1
+		if !lgt.IsDelta() && rcaster.LightSamples > 1 {
+			side = rcaster.LightSamples
+		}
+		var sum utils.Vec3
+		for i := 0; i < side; i++ {
+			for j := 0; j < side; j++ {
+				// the centers of a grid, the same for every pixel
+				u1 := (float64(i) + 0.5) / float64(side)
+				u2 := (float64(j) + 0.5) / float64(side)
+				sample, ok := lgt.Sample(point, u1, u2)
+				if !ok || !(sample.PDF > 0) {
+					continue
+				}
+				cosTheta := normal.Dot(sample.Dir)
+				if cosTheta <= 0 || rcaster.occluded(point, sample, lgt) {
+					continue
+				}
+				incoming := sample.Radiance.Scale(cosTheta / (sample.PDF * math.Pi))
+				sum = sum.Add(albedo.Mul(incoming).Scale(obj.DiffuseReflection))
+				if obj.SpecularReflection > 0 {
+					mirror := sample.Dir.Sub(normal.Scale(2 * normal.Dot(sample.Dir)))
+					if cosAlpha := mirror.Dot(view); cosAlpha > 0 {
+						highlight := (obj.SpecularDecay + 2) / 2 * math.Pow(cosAlpha, obj.SpecularDecay)
+						sum = sum.Add(incoming.Scale(obj.SpecularReflection * highlight))
+					}
 				}
 			}
 		}
+		color = color.Add(sum.Scale(1 / float64(side*side)))
+	}
+	return color
+}
+
+// occluded is a function to check if an object or another light is between a point and a light sample.
+//
+// Parameters:
+//  point  - the shaded point.
+//  sample - the light sample.
+//  lgt    - the sampled light.
+//
+// Returns:
+//  true if the sample is not visible.
+//
+func (rcaster *RayCaster) occluded(point utils.Vec3, sample light.Sample, lgt light.Light) bool {
+	ray := entity.Ray{Origin: point, Direction: sample.Dir}
+	tMax := math.MaxFloat64
+	if !math.IsInf(sample.Dist, 1) {
+		tMax = sample.Dist * (1 - shadowEpsilon)
+	}
+	if _, found := rcaster.shapes.Closest(ray, 0, tMax); found {
+		return true
 	}
-	// intersecting lights
-	lightClosest := false
-	// for _, lgt := range rcaster.Lgts.LightList {
-	// 	sphere := entity.InitSphere(lgt.LightPosition, lgt.Radius)
-	// 	ts, intersected := line.IntersectSphere(sphere)
-	// 	if intersected && (ts[0] >= 1 || ts[1] >= 1) {
-	// 		if ts[0] <= closestT || ts[1] <= closestT {
-	// 			lightClosest = true
-	// 			if ts[0] <= ts[1] && ts[0] >= 1 {
-	// 				closestT = ts[0]
-	// 			} else {
-	// 				closestT = ts[1]
-	// 			}
-	// 		}
-	// 	}
-	// }
-	if !lightClosest {
-		if closestObjIdx != -1 {
-			color = []int{255, 0, 0}
+	for _, other := range rcaster.Lgts.LightList {
+		if other == lgt {
+			continue
+		}
+		if _, found := other.Intersect(ray, 0, tMax); found {
+			return true
 		}
-		coloredScreen.Colors[lp][cp] = color
-	} else {
-		coloredScreen.Colors[lp][cp] = []int{255, 255, 255}
 	}
-
+	return false
 }
 
 // Run is a function to run the ray casting.
@@ -114,7 +290,36 @@ func (rcaster *RayCaster) Run() *screen.ColoredScreen {
 //
 // Returns:
 // 	a RayCaster.
+//  an error if the shapes of the objects could not be built or a light is invalid.
 //
-func InitRayCaster(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera, lgts *light.Lights) RayCaster {
-	return RayCaster{Objs: objs, PixelScreen: pixelScreen, Cam: cam, Lgts: lgts}
+func InitRayCaster(objs *general.Objects, pixelScreen *screen.Screen, cam *camera.Camera, lgts *light.Lights) (RayCaster, error) {
+	if err := pixelScreen.CheckIntegrity(); err != nil {
+		return RayCaster{}, utils.WrapField("PixelScreen", err)
+	}
+	shapes, err := shape.FromObjects(objs)
+	if err != nil {
+		return RayCaster{}, err
+	}
+	if err = lgts.Prepare(); err != nil {
+		return RayCaster{}, err
+	}
+	emissive, _ := light.InitEmissiveLights(objs, shapes)
+	var ambient utils.Vec3
+	for _, lgt := range lgts.LightList {
+		emitter := lgt.GetEmitter()
+		if len(emitter.Color) == 3 {
+			ambient = ambient.Add(utils.Vec3{emitter.Color[0], emitter.Color[1], emitter.Color[2]}.Scale(emitter.AmbientIntensity))
+		}
+	}
+	return RayCaster{
+		Objs:         objs,
+		PixelScreen:  pixelScreen,
+		Cam:          cam,
+		Lgts:         lgts,
+		Depth:        DefaultDepth,
+		LightSamples: DefaultLightSamples,
+		shapes:       shapes,
+		lights:       append(append([]light.Light{}, lgts.LightList...), emissive...),
+		ambient:      ambient,
+	}, nil
 }
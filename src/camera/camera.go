@@ -2,9 +2,7 @@ package camera
 
 import (
 	"encoding/json"
-	"errors"
 	"io/ioutil"
-	"log"
 	"os"
 	"path"
 	"path/filepath"
@@ -41,7 +39,7 @@ type Camera struct {
 // 	a Matrix.
 //
 func CamToHomogeneousMatrix(cam *Camera) utils.Matrix {
-	maux := utils.InitMatrix(3, 3)
+	maux, _ := utils.InitMatrix(3, 3) // 3x3 is always valid
 	// placing vectors on the matrix on the right form
 	maux.Values[0] = cam.Right.Coordinates
 	maux.Values[1] = cam.Up.Coordinates
@@ -66,7 +64,7 @@ func CamToHomogeneousMatrix(cam *Camera) utils.Matrix {
 // 	a Matrix.
 //
 func CamToWorld(cam *Camera) utils.Matrix {
-	maux := utils.InitMatrix(3, 3)
+	maux, _ := utils.InitMatrix(3, 3) // 3x3 is always valid
 	// placing vectors on the matrix on the right form
 	for j := 0; j < 3; j++ {
 		maux.Values[j][0] = cam.Right.Coordinates[j]
@@ -104,12 +102,34 @@ func (cam *Camera) NormalizeCam() {
 // 	vect - a vector.
 //
 // Returns:
-// 	none
+// 	a *utils.SizeError if the vector is not 3D.
+//
+func CheckLenVector(vect utils.Vector) error {
+	return utils.CheckSize(len(vect.Coordinates), 3)
+}
+
+// checkCamera is a function to check the position and the vectors of a camera.
+//
+// Parameters:
+// 	pos   - the position of the camera.
+// 	look  - vector to were the camera is looking.
+//  up    - vector head of the camera.
+//  right - side vector of the camera.
+//
+// Returns:
+// 	a *utils.FieldError with the invalid field.
 //
-func CheckLenVector(vect utils.Vector) {
-	if len(vect.Coordinates) != 3 {
-		log.Fatalf("Invalid length of Camera vector %d.\n", len(vect.Coordinates))
+func checkCamera(pos entity.Point, look, up, right utils.Vector) error {
+	if err := CheckLenVector(look); err != nil {
+		return utils.WrapField("Look", err)
 	}
+	if err := CheckLenVector(up); err != nil {
+		return utils.WrapField("Up", err)
+	}
+	if err := CheckLenVector(right); err != nil {
+		return utils.WrapField("Right", err)
+	}
+	return utils.WrapField("Pos", utils.CheckSize(len(pos.Coordinates), 3))
 }
 
 // WriteJSONCamera is a function to write all Camera data as json.
@@ -118,23 +138,27 @@ func CheckLenVector(vect utils.Vector) {
 //  outPath - path to the output folder.
 //
 // Returns:
-//  none
+//  an error if the file can not be written.
 //
-func (cam *Camera) WriteJSONCamera(outPath string) {
+func (cam *Camera) WriteJSONCamera(outPath string) error {
 	// creating the json
 	file, err := json.MarshalIndent(*cam, "", "	")
-	utils.ShowError(err, "Unable to convert camera to json.")
+	if err != nil {
+		return err
+	}
 	// getting the right path
 	filePath, err := filepath.Abs(path.Join(outPath, "camera.json"))
-	utils.ShowError(err, "Unable to get camera's absolute path.")
+	if err != nil {
+		return err
+	}
 	// creating the folder if it doesn't exists.
 	if !utils.PathExists(filePath) {
-		err = os.MkdirAll(outPath, 0700)
-		utils.ShowError(err, "Unable to create dirs.")
+		if err = os.MkdirAll(outPath, 0700); err != nil {
+			return err
+		}
 	}
 	// writing
-	err = ioutil.WriteFile(filePath, file, 0700)
-	utils.ShowError(err, "Unable to write camera.")
+	return ioutil.WriteFile(filePath, file, 0700)
 }
 
 // LoadJSONCamera is a function to read all Camera data as json.
@@ -144,27 +168,24 @@ func (cam *Camera) WriteJSONCamera(outPath string) {
 //
 // Returns:
 //  the camera.
+//  an error if the file can not be read, a *utils.FileError if it is invalid.
 //
-func LoadJSONCamera(inPath string) *Camera {
-	// opening the file
-	camFile, err := os.Open(inPath)
-	utils.ShowError(err, "Unable to open camera.")
-	// converting to cam
-	byteCamera, err := ioutil.ReadAll(camFile)
-	utils.ShowError(err, "Unable to convert camera file to bytes.")
+func LoadJSONCamera(inPath string) (*Camera, error) {
+	// reading the file
+	byteCamera, err := ioutil.ReadFile(inPath)
+	if err != nil {
+		return nil, err
+	}
 	var camAux Camera
-	err = json.Unmarshal(byteCamera, &camAux)
-	utils.ShowError(err, "Failed to unmarshal camera.")
+	if err = json.Unmarshal(byteCamera, &camAux); err != nil {
+		return nil, utils.WrapFile("parse", inPath, err)
+	}
 	// Validating the camera
-	if len(camAux.Look.Coordinates) == 3 && len(camAux.Up.Coordinates) == 3 && len(camAux.Right.Coordinates) == 3 {
-		if len(camAux.Pos.Coordinates) != 3 {
-			utils.ShowError(errors.New("Invalid camera"), "Camera with vectors with 3D position, but camera position isn't 3D.")
-		}
-	} else {
-		utils.ShowError(errors.New("Invalid camera"), "Camera with invalid vectors.")
+	if err = checkCamera(camAux.Pos, camAux.Look, camAux.Up, camAux.Right); err != nil {
+		return nil, utils.WrapFile("load", inPath, err)
 	}
 	camAux.NormalizeCam()
-	return &camAux
+	return &camAux, nil
 }
 
 // InitCamera is a function to initialize a Camera.
@@ -179,16 +200,14 @@ func LoadJSONCamera(inPath string) *Camera {
 //
 // Returns:
 // 	A Camera.
+//  a *utils.FieldError if the position or a vector is not 3D.
 //
-func InitCamera(pos entity.Point, look, up, right utils.Vector, fov, near float64) Camera {
-	if len(pos.Coordinates) != 3 {
-		log.Fatalf("Invalid length of Camera point %d.\n", len(pos.Coordinates))
+func InitCamera(pos entity.Point, look, up, right utils.Vector, fov, near float64) (Camera, error) {
+	if err := checkCamera(pos, look, up, right); err != nil {
+		return Camera{}, err
 	}
-	CheckLenVector(look)
-	CheckLenVector(up)
-	CheckLenVector(right)
 	cam := Camera{Pos: pos, Look: look, Up: up, Right: right, FieldOfView: fov, Near: near}
-	return cam
+	return cam, nil
 }
 
 // InitCameraWithPoints is a function to initialize a Camera based only on its position and the target point.
@@ -199,16 +218,24 @@ func InitCamera(pos entity.Point, look, up, right utils.Vector, fov, near float6
 //
 // Returns:
 // 	A Camera.
+//  a *utils.FieldError if the points are not 3D.
 //
-func InitCameraWithPoints(pos, target *entity.Point) Camera {
-	look := entity.ExtractVector(pos, target)
+func InitCameraWithPoints(pos, target *entity.Point) (Camera, error) {
+	if err := utils.CheckSize(len(pos.Coordinates), 3); err != nil {
+		return Camera{}, utils.WrapField("Pos", err)
+	}
+	if err := utils.CheckSize(len(target.Coordinates), 3); err != nil {
+		return Camera{}, utils.WrapField("Target", err)
+	}
+	// the points are 3D, ignoring the errors
+	look, _ := entity.ExtractVector(pos, target)
 	look = utils.NormalizeVector(&look)
 
 	vectTemp := utils.Vector{Coordinates: []float64{0, 1, 0}}
 	vectTemp = utils.NormalizeVector(&vectTemp)
-	right := utils.VectorCrossProduct(&vectTemp, &look)
+	right, _ := utils.VectorCrossProduct(&look, &vectTemp)
 	right = utils.NormalizeVector(&right)
-	up := utils.VectorCrossProduct(&look, &right)
+	up, _ := utils.VectorCrossProduct(&right, &look)
 	up = utils.NormalizeVector(&up)
 
 	return InitCamera(*pos, look, up, right, 50.0, 1)
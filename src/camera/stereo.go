@@ -0,0 +1,161 @@
+package camera
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Stereo projection modes.
+const (
+	StereoParallel        = "parallel"
+	StereoToeIn           = "toe-in"
+	StereoOmnidirectional = "ods"
+)
+
+// Stereo output layouts.
+const (
+	LayoutSeparate   = "separate"
+	LayoutSideBySide = "side-by-side"
+	LayoutTopBottom  = "top-bottom"
+)
+
+// Eyes of a stereo rig.
+const (
+	LeftEye  = -1
+	RightEye = 1
+)
+
+// StereoRig is a class for stereo camera rigs.
+//
+// Members:
+//  Cam                 - the center camera of the rig.
+//  InterocularDistance - distance between the two eyes.
+//  Convergence         - distance from the camera to the zero parallax plane (0 means infinity).
+//  Mode                - the projection mode (parallel, toe-in or ods).
+//  Layout              - how the two images are written (separate, side-by-side or top-bottom).
+//
+type StereoRig struct {
+	Cam                 Camera
+	InterocularDistance float64
+	Convergence         float64
+	Mode                string
+	Layout              string
+}
+
+// eyeOffset is a function to get the signed offset of an eye along the camera right vector.
+//
+// Parameters:
+//  eye - LeftEye or RightEye.
+//
+// Returns:
+//  the offset.
+//
+func (rig *StereoRig) eyeOffset(eye int) float64 {
+	return float64(eye) * rig.InterocularDistance / 2
+}
+
+// EyeCamera is a function to get the camera of one eye for the planar modes.
+//
+// Parameters:
+//  eye - LeftEye or RightEye.
+//
+// Returns:
+//  the camera of the eye.
+//
+func (rig *StereoRig) EyeCamera(eye int) Camera {
+	offset := rig.eyeOffset(eye)
+	cam := rig.Cam
+	pos, _ := entity.InitPoint(3) // a valid size
+	for i := 0; i < 3; i++ {
+		pos.Coordinates[i] = rig.Cam.Pos.Coordinates[i] + offset*rig.Cam.Right.Coordinates[i]
+	}
+	cam.Pos = pos
+	if rig.Mode == StereoToeIn && rig.Convergence > 0 {
+		// rotating look and right on their own plane so the eye points to the convergence point
+		c := rig.Convergence
+		norm := math.Sqrt(c*c + offset*offset)
+		cosA := c / norm
+		sinA := -offset / norm
+		cam.Look = utils.SumVector(&rig.Cam.Look, &rig.Cam.Right, cosA, sinA)
+		cam.Right = utils.SumVector(&rig.Cam.Right, &rig.Cam.Look, cosA, -sinA)
+		cam.NormalizeCam()
+	}
+	return cam
+}
+
+// EyeShift is a function to get the horizontal screen shift of one eye.
+// Parallel rigs use an off-axis frustum so objects on the convergence plane have zero parallax.
+//
+// Parameters:
+//  eye - LeftEye or RightEye.
+//
+// Returns:
+//  the shift in camera space units at distance 1.
+//
+func (rig *StereoRig) EyeShift(eye int) float64 {
+	if rig.Mode != StereoParallel || rig.Convergence <= 0 {
+		return 0
+	}
+	return -rig.eyeOffset(eye) / rig.Convergence
+}
+
+// ODSLine is a function to get the ray of an equirectangular omni-directional stereo pixel.
+//
+// Parameters:
+//  eye    - LeftEye or RightEye.
+//  x      - the pixel column.
+//  y      - the pixel line.
+//  px     - the additional on x (0->1).
+//  py     - the additional on y (0->1).
+//  width  - the image width.
+//  height - the image height.
+//
+// Returns:
+//  the Line leaving the viewing circle.
+//
+func (rig *StereoRig) ODSLine(eye, x, y int, px, py float64, width, height int) entity.Line {
+	theta := (2*(float64(x)+px)/float64(width) - 1) * math.Pi
+	phi := (1 - 2*(float64(y)+py)/float64(height)) * math.Pi / 2
+
+	cam := rig.Cam
+	// valid sizes, ignoring the errors
+	director, _ := utils.InitVector(3)
+	start, _ := entity.InitPoint(3)
+	offset := rig.eyeOffset(eye)
+	for i := 0; i < 3; i++ {
+		director.Coordinates[i] = math.Sin(theta)*math.Cos(phi)*cam.Right.Coordinates[i] +
+			math.Sin(phi)*cam.Up.Coordinates[i] +
+			math.Cos(theta)*math.Cos(phi)*cam.Look.Coordinates[i]
+		// the eye lies on the viewing circle, perpendicular to the horizontal direction
+		start.Coordinates[i] = cam.Pos.Coordinates[i] +
+			offset*(math.Cos(theta)*cam.Right.Coordinates[i]-math.Sin(theta)*cam.Look.Coordinates[i])
+	}
+	director = utils.NormalizeVector(&director)
+	return entity.Line{Start: start, Director: director}
+}
+
+// InitStereoRig is a function to initialize a StereoRig.
+//
+// Parameters:
+//  cam                 - the center camera.
+//  interocularDistance - distance between the two eyes.
+//  convergence         - distance to the zero parallax plane (0 means infinity).
+//  mode                - the projection mode.
+//  layout              - the output layout.
+//
+// Returns:
+//  a StereoRig.
+//  a *utils.FieldError if the mode or the layout is unknown.
+//
+func InitStereoRig(cam Camera, interocularDistance, convergence float64, mode, layout string) (StereoRig, error) {
+	if mode != StereoParallel && mode != StereoToeIn && mode != StereoOmnidirectional {
+		return StereoRig{}, utils.WrapField("Mode", fmt.Errorf("unknown stereo mode %q", mode))
+	}
+	if layout != LayoutSeparate && layout != LayoutSideBySide && layout != LayoutTopBottom {
+		return StereoRig{}, utils.WrapField("Layout", fmt.Errorf("unknown stereo layout %q", layout))
+	}
+	return StereoRig{Cam: cam, InterocularDistance: interocularDistance, Convergence: convergence, Mode: mode, Layout: layout}, nil
+}
@@ -25,11 +25,15 @@ type Line struct {
 //
 // Returns:
 // 	the Line.
+// 	a *utils.SizeError if the sizes of the points differ.
 //
-func ExtractLine(p0, p1 Point) Line {
-	v := ExtractVector(&p0, &p1)
+func ExtractLine(p0, p1 Point) (Line, error) {
+	v, err := ExtractVector(&p0, &p1)
+	if err != nil {
+		return Line{}, err
+	}
 	line := Line{Start: p0, Director: v}
-	return line
+	return line, nil
 }
 
 // FindPos is a function to get the position of a line at a given t.
@@ -42,7 +46,7 @@ func ExtractLine(p0, p1 Point) Line {
 //
 func (line Line) FindPos(t float64) Point {
 	v := utils.CMultVector(&line.Director, t)
-	pos := InitPoint(3)
+	pos, _ := InitPoint(3) // a valid size
 	for i := 0; i < 3; i++ {
 		pos.Coordinates[i] = line.Start.Coordinates[i] + v.Coordinates[i]
 	}
@@ -113,21 +117,25 @@ func (line Line) IntersectSphere(sphere Sphere) ([]float64, bool) {
 //
 // Returns:
 //  The 3 baricentric coordinates.
+//  a *utils.SizeError if a point is not 3D.
 //
-func FindBaricentricCoordinates(triang []Point, pos Point) []float64 {
-
-	AB := ExtractVector(&triang[0], &triang[1])
-	AC := ExtractVector(&triang[0], &triang[2])
+func FindBaricentricCoordinates(triang []Point, pos Point) ([]float64, error) {
+	if err := checkTriangle(triang, &pos); err != nil {
+		return nil, err
+	}
+	// the points are 3D, ignoring the errors
+	AB, _ := ExtractVector(&triang[0], &triang[1])
+	AC, _ := ExtractVector(&triang[0], &triang[2])
 
-	PA := ExtractVector(&pos, &triang[0])
-	PB := ExtractVector(&pos, &triang[1])
-	PC := ExtractVector(&pos, &triang[2])
+	PA, _ := ExtractVector(&pos, &triang[0])
+	PB, _ := ExtractVector(&pos, &triang[1])
+	PC, _ := ExtractVector(&pos, &triang[2])
 
-	normal := utils.VectorCrossProduct(&AB, &AC)
+	normal, _ := utils.VectorCrossProduct(&AB, &AC)
 	AreaABC := utils.VectorNorm(&normal) / 2
 
-	normalA := utils.VectorCrossProduct(&PB, &PC)
-	normalB := utils.VectorCrossProduct(&PC, &PA)
+	normalA, _ := utils.VectorCrossProduct(&PB, &PC)
+	normalB, _ := utils.VectorCrossProduct(&PC, &PA)
 
 	AreaA := utils.VectorNorm(&normalA) / 2
 	AreaB := utils.VectorNorm(&normalB) / 2
@@ -137,8 +145,41 @@ func FindBaricentricCoordinates(triang []Point, pos Point) []float64 {
 	gama := (1 - alpha) - beta
 
 	coordinates := []float64{alpha, beta, gama}
-	return coordinates
+	return coordinates, nil
+
+}
+
+// checkTriangle is a function to check that a triangle and a point are 3D.
+//
+// Parameters:
+// 	triang - a list of 3 points.
+//  pos    - the other point.
+//
+// Returns:
+//  a *utils.SizeError if there are not 3 points or a point is not 3D.
+//
+func checkTriangle(triang []Point, pos *Point) error {
+	if err := utils.CheckSize(len(triang), 3); err != nil {
+		return err
+	}
+	return check3D(append([]Point{*pos}, triang...)...)
+}
 
+// check3D is a function to check that points are 3D.
+//
+// Parameters:
+// 	points - the points.
+//
+// Returns:
+//  a *utils.SizeError with the size of the first point that is not 3D.
+//
+func check3D(points ...Point) error {
+	for _, point := range points {
+		if err := utils.CheckSize(len(point.Coordinates), 3); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // IntersectTriangle is a function to intersect triangles.
@@ -150,8 +191,15 @@ func FindBaricentricCoordinates(triang []Point, pos Point) []float64 {
 //  The line t parameter (A + tV).
 //  The baricentric coordinates at that point.
 //  A flag checking if has intersection.
+//  a *utils.SizeError if the line or a point is not 3D.
 //
-func (line Line) IntersectTriangle(triang []Point) (float64, []float64, bool) {
+func (line Line) IntersectTriangle(triang []Point) (float64, []float64, bool, error) {
+	if err := checkTriangle(triang, &line.Start); err != nil {
+		return 0, nil, false, err
+	}
+	if err := utils.CheckSize(len(line.Director.Coordinates), 3); err != nil {
+		return 0, nil, false, err
+	}
 	rayOrigin := line.Start
 	rayVector := line.Director
 
@@ -165,34 +213,35 @@ func (line Line) IntersectTriangle(triang []Point) (float64, []float64, bool) {
 	bCoord := make([]float64, 3)
 
 
-	edge1 := ExtractVector(&vertex0, &vertex1)
-	edge2 := ExtractVector(&vertex0, &vertex2)
+	// the points are 3D, ignoring the errors
+	edge1, _ := ExtractVector(&vertex0, &vertex1)
+	edge2, _ := ExtractVector(&vertex0, &vertex2)
 
-    h := utils.VectorCrossProduct(&rayVector, &edge2)
+    h, _ := utils.VectorCrossProduct(&rayVector, &edge2)
 	a := utils.DotProduct(&edge1, &h);
 	
     if a > -EPSILON && a < EPSILON {
-		return 0,bCoord, false;    // This ray is parallel to this triangle.
+		return 0,bCoord, false, nil;    // This ray is parallel to this triangle.
 	}
 	f := 1.0/a
 	
-    s := ExtractVector(&vertex0,&rayOrigin)
+    s, _ := ExtractVector(&vertex0,&rayOrigin)
     u := f * utils.DotProduct(&s, &h)
     if u < 0.0 || u > 1.0 {
-		return 0,bCoord,false
+		return 0,bCoord,false,nil
 	}
-    q := utils.VectorCrossProduct(&s,&edge1);
+    q, _ := utils.VectorCrossProduct(&s,&edge1);
     v := f * utils.DotProduct(&rayVector,&q);
     if v < 0.0 || u + v > 1.0 {
-		return 0,bCoord,false
+		return 0,bCoord,false,nil
 	}
     // At this stage we can compute t to find out where the intersection point is on the line.
     t := f * utils.DotProduct(&edge2, &q)
     if t > EPSILON && t < 1/EPSILON {
 		// outIntersectionPoint = rayOrigin + rayVector * t
 		bCoord = []float64{1-u-v, u, v}
-        return t,bCoord,true
+        return t,bCoord,true,nil
     }
     
-	return 0,bCoord,false
+	return 0,bCoord,false,nil
 }
@@ -28,12 +28,17 @@ type Plane struct {
 //
 // Returns:
 // 	the plane.
+// 	a *utils.SizeError if a point is not 3D.
 //
-func ExtractPlane(p0, p1, p2 Point) Plane {
-	v1 := ExtractVector(&p0, &p1)
-	v2 := ExtractVector(&p0, &p2)
+func ExtractPlane(p0, p1, p2 Point) (Plane, error) {
+	if err := check3D(p0, p1, p2); err != nil {
+		return Plane{}, err
+	}
+	// the points are 3D, ignoring the errors
+	v1, _ := ExtractVector(&p0, &p1)
+	v2, _ := ExtractVector(&p0, &p2)
 
-	normV := utils.VectorCrossProduct(&v1, &v2)
+	normV, _ := utils.VectorCrossProduct(&v1, &v2)
 	normV = utils.NormalizeVector(&normV)
 	a := normV.Coordinates[0]
 	b := normV.Coordinates[1]
@@ -41,6 +46,6 @@ func ExtractPlane(p0, p1, p2 Point) Plane {
 	d := -1 * ((a * p0.Coordinates[0]) + (b * p0.Coordinates[1]) + (c * p0.Coordinates[2]))
 
 	plane := Plane{A: a, B: b, C: c, D: d}
-	return plane
+	return plane, nil
 
 }
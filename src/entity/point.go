@@ -1,8 +1,6 @@
 package entity
 
 import (
-	"log"
-
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
@@ -22,12 +20,10 @@ type Point struct {
 // 	p2 - The second vector.
 //
 // Returns:
-// 	none
+// 	a *utils.SizeError with the size of p2 if the sizes differ.
 //
-func CheckPointCoordinates(p1, p2 *Point) {
-	if len(p1.Coordinates) != len(p2.Coordinates) {
-		log.Fatalf("Invalid size of Point. Expected: %d and Got: %d.\n", len(p1.Coordinates), len(p2.Coordinates))
-	}
+func CheckPointCoordinates(p1, p2 *Point) error {
+	return utils.CheckSize(len(p2.Coordinates), len(p1.Coordinates))
 }
 
 // ExtractVector is a function to extract a vector between two points.
@@ -38,14 +34,17 @@ func CheckPointCoordinates(p1, p2 *Point) {
 //
 // Returns:
 // 	a Vector.
+// 	a *utils.SizeError if the sizes differ.
 //
-func ExtractVector(p1, p2 *Point) utils.Vector {
-	CheckPointCoordinates(p1, p2)
-	vaux := utils.InitVector(len(p1.Coordinates))
+func ExtractVector(p1, p2 *Point) (utils.Vector, error) {
+	if err := CheckPointCoordinates(p1, p2); err != nil {
+		return utils.Vector{}, err
+	}
+	vaux, _ := utils.InitVector(len(p1.Coordinates)) // a length is not negative
 	for i := 0; i < len(p1.Coordinates); i++ {
 		vaux.Coordinates[i] = p2.Coordinates[i] - p1.Coordinates[i]
 	}
-	return vaux
+	return vaux, nil
 }
 
 // PointToHomogeneousCoord is a function to add the extra 1 coord and transpose the Point converting it to Matrix.
@@ -57,7 +56,7 @@ func ExtractVector(p1, p2 *Point) utils.Vector {
 // 	a Matrix.
 //
 func PointToHomogeneousCoord(point *Point) utils.Matrix {
-	maux := utils.InitMatrix(len(point.Coordinates)+1, 1)
+	maux, _ := utils.InitMatrix(len(point.Coordinates)+1, 1) // at least 1x1
 	for i := 0; i < len(point.Coordinates); i++ {
 		maux.Values[i][0] = point.Coordinates[i]
 	}
@@ -65,18 +64,47 @@ func PointToHomogeneousCoord(point *Point) utils.Matrix {
 	return maux
 }
 
+// TransformPoint is a function to multiply a Point by a homogeneous Matrix.
+//
+// Parameters:
+// 	matrix - the (n+1)x(n+1) Matrix.
+//  point  - the Point with n coordinates.
+//
+// Returns:
+// 	the transformed Point, divided by the homogeneous coord when it is not 1.
+//
+func TransformPoint(matrix *utils.Matrix, point *Point) Point {
+	size := len(point.Coordinates)
+	paux, _ := InitPoint(size) // a length is not negative
+	w := matrix.Values[size][size]
+	for j := 0; j < size; j++ {
+		w += matrix.Values[size][j] * point.Coordinates[j]
+	}
+	for i := 0; i < size; i++ {
+		paux.Coordinates[i] = matrix.Values[i][size]
+		for j := 0; j < size; j++ {
+			paux.Coordinates[i] += matrix.Values[i][j] * point.Coordinates[j]
+		}
+		if w != 1 && w != 0 {
+			paux.Coordinates[i] /= w
+		}
+	}
+	return paux
+}
+
 // InitPoint is a function to initialize a Point.
 //
 // Parameters:
 // 	size - The size of the Point.
 //
 // Returns:
-// 	a Point
+// 	a Point.
+// 	a *utils.SizeError if the size is negative.
 //
-func InitPoint(size int) Point {
+func InitPoint(size int) (Point, error) {
 	if size < 0 {
-		log.Fatalf("Invalid Point size %d.\n", size)
+		return Point{}, &utils.SizeError{Size: size}
 	}
 	point := Point{Coordinates: make([]float64, size)}
-	return point
+	return point, nil
 }
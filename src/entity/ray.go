@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Ray is a class for lines stored by value, used on the intersection hot path.
+//
+// Members:
+// 	Origin    - the starting point.
+// 	Direction - the vector director.
+//
+type Ray struct {
+	Origin    utils.Vec3
+	Direction utils.Vec3
+}
+
+// PointToVec3 is a function to convert a 3D Point to a Vec3.
+//
+// Parameters:
+// 	point - the Point.
+//
+// Returns:
+// 	the Vec3.
+//
+func PointToVec3(point *Point) utils.Vec3 {
+	return utils.Vec3{point.Coordinates[0], point.Coordinates[1], point.Coordinates[2]}
+}
+
+// Vec3ToPoint is a function to convert a Vec3 to a Point.
+//
+// Parameters:
+// 	v - the Vec3.
+//
+// Returns:
+// 	the Point.
+//
+func Vec3ToPoint(v utils.Vec3) Point {
+	return Point{Coordinates: []float64{v[0], v[1], v[2]}}
+}
+
+// ToRay is a function to convert a Line to a Ray.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the Ray.
+// 	a *utils.SizeError if the line is not 3D.
+//
+func (line Line) ToRay() (Ray, error) {
+	if err := check3D(line.Start); err != nil {
+		return Ray{}, err
+	}
+	direction, err := utils.VectorToVec3(&line.Director)
+	if err != nil {
+		return Ray{}, err
+	}
+	return Ray{Origin: PointToVec3(&line.Start), Direction: direction}, nil
+}
+
+// ToLine is a function to convert a Ray to a Line.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the Line.
+//
+func (ray Ray) ToLine() Line {
+	return Line{Start: Vec3ToPoint(ray.Origin), Director: ray.Direction.ToVector()}
+}
+
+// At is a function to get the position of a ray at a given t.
+//
+// Parameters:
+// 	t - the t parameter.
+//
+// Returns:
+// 	the position.
+//
+func (ray Ray) At(t float64) utils.Vec3 {
+	return ray.Origin.AddScaled(ray.Direction, t)
+}
+
+// IntersectTriangle is a function to intersect triangles (Moller-Trumbore).
+//
+// Parameters:
+// 	v0, v1, v2 - the vertices.
+//
+// Returns:
+//  The ray t parameter.
+//  The baricentric coordinates u and v of v1 and v2 (v0 has 1 - u - v).
+//  A flag checking if has intersection.
+//
+func (ray Ray) IntersectTriangle(v0, v1, v2 utils.Vec3) (float64, float64, float64, bool) {
+	const epsilon = 0.0000001
+	edge1 := v1.Sub(v0)
+	edge2 := v2.Sub(v0)
+	h := ray.Direction.Cross(edge2)
+	a := edge1.Dot(h)
+	if a > -epsilon && a < epsilon {
+		return 0, 0, 0, false // This ray is parallel to this triangle.
+	}
+	f := 1.0 / a
+	s := ray.Origin.Sub(v0)
+	u := f * s.Dot(h)
+	if u < 0.0 || u > 1.0 {
+		return 0, 0, 0, false
+	}
+	q := s.Cross(edge1)
+	v := f * ray.Direction.Dot(q)
+	if v < 0.0 || u+v > 1.0 {
+		return 0, 0, 0, false
+	}
+	t := f * edge2.Dot(q)
+	if t > epsilon && t < 1/epsilon {
+		return t, u, v, true
+	}
+	return 0, 0, 0, false
+}
+
+// IntersectSphere is a function to intersect spheres.
+//
+// Parameters:
+// 	center - the center of the sphere.
+//  radius - the radius of the sphere.
+//
+// Returns:
+//  The two ray t parameters, the smallest first.
+//  A flag checking if has intersection.
+//
+func (ray Ray) IntersectSphere(center utils.Vec3, radius float64) (float64, float64, bool) {
+	oc := ray.Origin.Sub(center)
+	a := ray.Direction.Dot(ray.Direction)
+	halfB := oc.Dot(ray.Direction)
+	c := oc.Dot(oc) - radius*radius
+	delta := halfB*halfB - a*c
+	if delta < 0 {
+		return 0, 0, false
+	}
+	sqrtDelta := math.Sqrt(delta)
+	return (-halfB - sqrtDelta) / a, (-halfB + sqrtDelta) / a, true
+}
+
+// IntersectPlane is a function to intersect planes.
+//
+// Parameters:
+// 	point  - a point of the plane.
+//  normal - the normal of the plane.
+//
+// Returns:
+//  The ray t parameter.
+//  A flag checking if has a single intersection (false when parallel).
+//
+func (ray Ray) IntersectPlane(point, normal utils.Vec3) (float64, bool) {
+	denom := normal.Dot(ray.Direction)
+	if utils.CheckTolerance(denom, 0) {
+		return 0, false
+	}
+	return normal.Dot(point.Sub(ray.Origin)) / denom, true
+}
@@ -3,12 +3,16 @@ package entity
 // Triangle is a class for a triangle.
 //
 // Members:
-// 	Vertices - list of points indices on a Vertices object.
-//  Normals  - list of normal vectors indices.
+// 	Vertices  - list of points indices on a Vertices object.
+//  Normals   - list of normal vectors indices.
+//  TexCoords - list of texture coordinates indices (empty when the triangle has no UVs).
+//  Tangents  - list of tangents indices (empty when the triangle has no UVs).
 //
 type Triangle struct {
-	Vertices []int
-	Normals  []int
+	Vertices  []int
+	Normals   []int
+	TexCoords []int
+	Tangents  []int
 }
 
 // InitTriangle is a function to initialize a Triangle.
@@ -20,17 +20,24 @@ type Vertices struct {
 //
 // Returns:
 //  the corresponding matrix.
+//  an error if there are no vertices.
 //
-func VerticesToHomogeneousCoord(vertices *Vertices) utils.Matrix {
+func VerticesToHomogeneousCoord(vertices *Vertices) (utils.Matrix, error) {
 	points := vertices.Points
-	maux := utils.InitMatrix(len(points[0].Coordinates)+1, len(points))
+	if len(points) == 0 {
+		return utils.Matrix{}, utils.WrapField("Points", &utils.SizeError{Size: 0})
+	}
+	maux, err := utils.InitMatrix(len(points[0].Coordinates)+1, len(points))
+	if err != nil {
+		return maux, err
+	}
 	for i, point := range points {
 		paux := PointToHomogeneousCoord(&point) // paux is a matrix
 		for j := 0; j < len(paux.Values); j++ {
 			maux.Values[j][i] = paux.Values[j][0]
 		}
 	}
-	return maux
+	return maux, nil
 }
 
 // MatrixToVertices is a function to parse a Matrix into a Vertices (a point is a column, removes the homogeneous coord).
@@ -44,7 +51,7 @@ func VerticesToHomogeneousCoord(vertices *Vertices) utils.Matrix {
 func MatrixToVertices(matrix *utils.Matrix) Vertices {
 	points := make([]Point, len(matrix.Values[0]))
 	for j := 0; j < len(matrix.Values[0]); j++ {
-		pointAux := InitPoint(len(matrix.Values) - 1)
+		pointAux, _ := InitPoint(len(matrix.Values) - 1) // a Matrix has at least 1 line
 		for i := 0; i < len(matrix.Values)-1; i++ {
 			pointAux.Coordinates[i] = matrix.Values[i][j]
 		}
@@ -61,12 +68,19 @@ func MatrixToVertices(matrix *utils.Matrix) Vertices {
 //
 // Returns:
 // 	the Vertices multiplied by the matrix.
+//  an error if there are no vertices or the matrix has the wrong size.
 //
-func MultVertices(vertices *Vertices, matrix *utils.Matrix) Vertices {
-	pointMatrix := VerticesToHomogeneousCoord(vertices)
-	maux := utils.MultMatrix(matrix, &pointMatrix)
+func MultVertices(vertices *Vertices, matrix *utils.Matrix) (Vertices, error) {
+	pointMatrix, err := VerticesToHomogeneousCoord(vertices)
+	if err != nil {
+		return Vertices{}, err
+	}
+	if err = utils.CheckSize(matrix.Columns, pointMatrix.Lines); err != nil {
+		return Vertices{}, utils.WrapField("columns", err)
+	}
+	maux, _ := utils.MultMatrix(matrix, &pointMatrix) // the sizes are checked above
 	vertAux := MatrixToVertices(&maux)
-	return vertAux
+	return vertAux, nil
 }
 
 // InitVertices is a function to initialize a Vertices.
@@ -0,0 +1,79 @@
+package general
+
+import (
+	"fmt"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Instance is a class for a placement of a mesh of Objects.Meshes.
+//
+// Members:
+//  Name      - the name of the instance.
+//  Mesh      - the name of the mesh.
+//  Transform - placement of the mesh in the world, identity when empty.
+//  Material  - replaces the surface attributes of the mesh (optional).
+//
+type Instance struct {
+	Name      string
+	Mesh      string
+	Transform Transform
+	Material  *Material
+}
+
+// GetTransform is a function to get the transform of the Instance and its inverse.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the object to world matrix.
+//  the world to object matrix.
+//  an error if the transform is invalid or not invertible.
+//
+func (inst *Instance) GetTransform() (utils.Matrix, utils.Matrix, error) {
+	toWorld, toObject, err := inst.Transform.GetMatrices()
+	if err != nil {
+		return toWorld, toObject, utils.WrapField("Transform", err)
+	}
+	return toWorld, toObject, nil
+}
+
+// FindMesh is a function to find a mesh by name.
+//
+// Parameters:
+//  name - the name of the mesh.
+//
+// Returns:
+//  the mesh.
+//  false if there is no mesh with that name.
+//
+func (objs *Objects) FindMesh(name string) (*Object, bool) {
+	for i := range objs.Meshes {
+		if objs.Meshes[i].Name == name {
+			return &objs.Meshes[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetInstanceObject is a function to get the Object holding the geometry and material of an Instance.
+//
+// Parameters:
+//  inst - the instance.
+//
+// Returns:
+//  the mesh, or a copy of it with the material of the instance.
+//  an error if the mesh does not exist or a texture can not be loaded.
+//
+func (objs *Objects) GetInstanceObject(inst *Instance) (*Object, error) {
+	mesh, found := objs.FindMesh(inst.Mesh)
+	if !found {
+		return nil, utils.WrapField("Mesh", fmt.Errorf("mesh %q not found", inst.Mesh))
+	}
+	if inst.Material == nil {
+		return mesh, nil
+	}
+	obj, err := mesh.WithMaterial(inst.Material)
+	return obj, utils.WrapField("Material", err)
+}
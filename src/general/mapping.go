@@ -0,0 +1,242 @@
+package general
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// LoadTextures is a function to read all images of the object, generating the tangents used by normal and bump maps,
+// and to prepare the procedural texture.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  an error.
+//
+func (obj *Object) LoadTextures() error {
+	if obj.Texture != nil {
+		if err := obj.Texture.Load(); err != nil {
+			return err
+		}
+	}
+	if obj.NormalMap != nil {
+		obj.NormalMap.Linear = true
+		if err := obj.NormalMap.Load(); err != nil {
+			return err
+		}
+	}
+	if obj.BumpMap != nil {
+		obj.BumpMap.Linear = true
+		if err := obj.BumpMap.Load(); err != nil {
+			return err
+		}
+	}
+	if obj.NormalMap != nil || obj.BumpMap != nil {
+		obj.GenerateTangents()
+	}
+	if obj.Procedural != nil {
+		// the object space is the one of the vertices, instances move it with the object
+		center := obj.GetLocalCenter()
+		if err := obj.Procedural.Init(center.Coordinates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateTangents is a function to compute the tangents of every textured triangle corner.
+// Like MikkTSpace, the tangents of the faces sharing a vertex, normal and texture coordinate are
+// accumulated weighted by the corner angle, then ortogonalized against the normal. The fourth
+// coordinate of a tangent is the handedness of the bitangent (1 or -1).
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  none
+//
+func (obj *Object) GenerateTangents() {
+	type cornerKey struct {
+		vertex, normal, texCoord int
+	}
+	tangentIndices := make(map[cornerKey]int)
+	tangents := make([]utils.Vector, 0)
+	bitangents := make([]utils.Vector, 0)
+	normals := make([]int, 0)
+
+	for i, triangle := range obj.Triangles {
+		if len(triangle.TexCoords) != 3 {
+			obj.Triangles[i].Tangents = nil
+			continue
+		}
+		p0 := obj.Vertices.Points[triangle.Vertices[0]]
+		p1 := obj.Vertices.Points[triangle.Vertices[1]]
+		p2 := obj.Vertices.Points[triangle.Vertices[2]]
+		// the points are 3D after CheckIntegrity, ignoring the errors
+		edge1, _ := entity.ExtractVector(&p0, &p1)
+		edge2, _ := entity.ExtractVector(&p0, &p2)
+		uv0 := obj.TexCoords[triangle.TexCoords[0]].Coordinates
+		uv1 := obj.TexCoords[triangle.TexCoords[1]].Coordinates
+		uv2 := obj.TexCoords[triangle.TexCoords[2]].Coordinates
+		du1, dv1 := uv1[0]-uv0[0], uv1[1]-uv0[1]
+		du2, dv2 := uv2[0]-uv0[0], uv2[1]-uv0[1]
+		det := du1*dv2 - du2*dv1
+		faceTangent, _ := utils.InitVector(3)
+		faceBitangent, _ := utils.InitVector(3)
+		if det != 0 {
+			r := 1 / det
+			faceTangent = utils.SumVector(&edge1, &edge2, dv2*r, -dv1*r)
+			faceBitangent = utils.SumVector(&edge2, &edge1, du1*r, -du2*r)
+		}
+
+		triangleTangents := make([]int, 3)
+		for c := 0; c < 3; c++ {
+			key := cornerKey{vertex: triangle.Vertices[c], normal: triangle.Normals[c], texCoord: triangle.TexCoords[c]}
+			idx, found := tangentIndices[key]
+			if !found {
+				idx = len(tangents)
+				tangentIndices[key] = idx
+				tangent, _ := utils.InitVector(3) // a valid size
+				bitangent, _ := utils.InitVector(3)
+				tangents = append(tangents, tangent)
+				bitangents = append(bitangents, bitangent)
+				normals = append(normals, triangle.Normals[c])
+			}
+			weight := obj.cornerAngle(triangle, c)
+			tangents[idx] = utils.SumVector(&tangents[idx], &faceTangent, 1, weight)
+			bitangents[idx] = utils.SumVector(&bitangents[idx], &faceBitangent, 1, weight)
+			triangleTangents[c] = idx
+		}
+		obj.Triangles[i].Tangents = triangleTangents
+	}
+
+	obj.Tangents = make([]utils.Vector, len(tangents))
+	for i := range tangents {
+		normal := obj.Normals[normals[i]]
+		tangent := utils.OrtogonalizeVector(&tangents[i], &normal)
+		if utils.VectorNorm(&tangent) == 0 {
+			tangent = arbitraryTangent(normal)
+		}
+		tangent = utils.NormalizeVector(&tangent)
+		cross, _ := utils.VectorCrossProduct(&normal, &tangent) // the normals are 3D after CheckIntegrity
+		handedness := 1.0
+		if utils.DotProduct(&cross, &bitangents[i]) < 0 {
+			handedness = -1
+		}
+		obj.Tangents[i] = utils.Vector{Coordinates: append(tangent.Coordinates, handedness)}
+	}
+}
+
+// arbitraryTangent is a function to get any vector ortogonal to a normal.
+//
+// Parameters:
+// 	normal - the normal.
+//
+// Returns:
+//  the tangent.
+//
+func arbitraryTangent(normal utils.Vector) utils.Vector {
+	axis := utils.Vector{Coordinates: []float64{1, 0, 0}}
+	if math.Abs(normal.Coordinates[0]) > 0.9 {
+		axis = utils.Vector{Coordinates: []float64{0, 1, 0}}
+	}
+	return utils.OrtogonalizeVector(&axis, &normal)
+}
+
+// getTangentFrame is a function to get the interpolated tangent and bitangent at a point of a triangle.
+//
+// Parameters:
+// 	triangleIdx       - the index of the triangle.
+//  baricentricCoords - the baricentric coords of the point.
+//  normal            - the interpolated normal.
+//
+// Returns:
+//  the tangent.
+//  the bitangent.
+//  false if the triangle has no tangents.
+//
+func (obj *Object) getTangentFrame(triangleIdx int, baricentricCoords []float64, normal utils.Vector) (utils.Vector, utils.Vector, bool) {
+	triangle := obj.Triangles[triangleIdx]
+	if len(triangle.Tangents) != 3 {
+		return utils.Vector{}, utils.Vector{}, false
+	}
+	tangent, _ := utils.InitVector(3) // a valid size
+	handedness := 0.0
+	for i := 0; i < 3; i++ {
+		t := obj.Tangents[triangle.Tangents[i]]
+		for j := 0; j < 3; j++ {
+			tangent.Coordinates[j] += t.Coordinates[j] * baricentricCoords[i]
+		}
+		handedness += t.Coordinates[3] * baricentricCoords[i]
+	}
+	tangent = utils.OrtogonalizeVector(&tangent, &normal)
+	if utils.VectorNorm(&tangent) == 0 {
+		tangent = arbitraryTangent(normal)
+	}
+	tangent = utils.NormalizeVector(&tangent)
+	bitangent, _ := utils.VectorCrossProduct(&normal, &tangent) // the normals are 3D after CheckIntegrity
+	if handedness < 0 {
+		bitangent = utils.CMultVector(&bitangent, -1)
+	}
+	return tangent, bitangent, true
+}
+
+// GetShadingNormal is a function to get the normal used for shading at a point of a triangle.
+// The interpolated normal is perturbed by the tangent space NormalMap and then by the BumpMap.
+//
+// Parameters:
+// 	triangleIdx       - the index of the triangle.
+//  baricentricCoords - the baricentric coords of the point.
+//
+// Returns:
+//  the normalized normal.
+//
+func (obj *Object) GetShadingNormal(triangleIdx int, baricentricCoords []float64) utils.Vector {
+	normal := obj.GetNormalByBaricentricCoords(triangleIdx, baricentricCoords)
+	hasNormalMap := obj.NormalMap != nil && obj.NormalMap.Loaded()
+	hasBumpMap := obj.BumpMap != nil && obj.BumpMap.Loaded()
+	if !hasNormalMap && !hasBumpMap {
+		return normal
+	}
+	uv, found := obj.GetTexCoordByBaricentricCoords(triangleIdx, baricentricCoords)
+	if !found {
+		return normal
+	}
+	tangent, bitangent, found := obj.getTangentFrame(triangleIdx, baricentricCoords, normal)
+	if !found {
+		return normal
+	}
+
+	if hasNormalMap {
+		rgb := obj.NormalMap.Sample(uv[0], uv[1])
+		mapped, _ := utils.InitVector(3) // a valid size
+		for j := 0; j < 3; j++ {
+			mapped.Coordinates[j] = (2*rgb[0]-1)*tangent.Coordinates[j] + (2*rgb[1]-1)*bitangent.Coordinates[j] + (2*rgb[2]-1)*normal.Coordinates[j]
+		}
+		if utils.VectorNorm(&mapped) > 0 {
+			normal = utils.NormalizeVector(&mapped)
+			tangent, bitangent, _ = obj.getTangentFrame(triangleIdx, baricentricCoords, normal)
+		}
+	}
+
+	if hasBumpMap {
+		// height differences to the neighbour texels
+		width, height := obj.BumpMap.Size()
+		h := obj.BumpMap.Sample(uv[0], uv[1])[0]
+		dhdu := obj.BumpMap.Sample(uv[0]+1/float64(width), uv[1])[0] - h
+		dhdv := obj.BumpMap.Sample(uv[0], uv[1]+1/float64(height))[0] - h
+		scale := obj.BumpScale
+		if scale == 0 {
+			scale = 1
+		}
+		bumped := utils.SumVector(&normal, &tangent, 1, -scale*dhdu)
+		bumped = utils.SumVector(&bumped, &bitangent, 1, -scale*dhdv)
+		if utils.VectorNorm(&bumped) > 0 {
+			normal = utils.NormalizeVector(&bumped)
+		}
+	}
+	return normal
+}
@@ -0,0 +1,99 @@
+package general
+
+import (
+	"github.com/lucas625/Projeto-CG/src/texture"
+)
+
+// Material is a class for the surface attributes of an Object, used to override them.
+//
+// Members:
+//  Color              - RGB for the color, the one of the Object is kept when empty.
+//  SpecularDecay      - constant for how fast the specular component decays.
+//  SpecularReflection - the coeficient of specular reflection.
+//  TransReflection    - the coeficient for transmission.
+//  AmbientReflection  - the ambient reflection.
+//  DiffuseReflection  - diffuse reflection coeficient.
+//  RoughNess          - how much reflections rays get distorted.
+//  RefractiveIndex    - index of refraction used by transmission.
+//  Emission           - RGB emitted (optional).
+//  EmissionStrength   - intensity multiplying the Emission (1 when 0).
+//  Texture            - image multiplying the Color (optional).
+//  Procedural         - procedural texture multiplying the Color (optional).
+//
+type Material struct {
+	Color              []float64
+	SpecularDecay      float64
+	SpecularReflection float64
+	TransReflection    float64
+	AmbientReflection  float64
+	DiffuseReflection  float64
+	RoughNess          float64
+	RefractiveIndex    float64
+	Emission           []float64
+	EmissionStrength   float64
+	Texture            *texture.Image
+	Procedural         *texture.Procedural
+}
+
+// SetMaterial is a function to replace the surface attributes of the Object by a Material.
+// The Texture is shared with the Material while the Procedural is copied, since its
+// object space origin depends on the Object. A Material without a Color keeps the one of the Object.
+//
+// Parameters:
+//  mat - the material.
+//
+// Returns:
+//  none
+//
+func (obj *Object) SetMaterial(mat *Material) {
+	if len(mat.Color) != 0 {
+		obj.Color = mat.Color
+	}
+	obj.SpecularDecay = mat.SpecularDecay
+	obj.SpecularReflection = mat.SpecularReflection
+	obj.TransReflection = mat.TransReflection
+	obj.AmbientReflection = mat.AmbientReflection
+	obj.DiffuseReflection = mat.DiffuseReflection
+	obj.RoughNess = mat.RoughNess
+	obj.RefractiveIndex = mat.RefractiveIndex
+	obj.Emission = mat.Emission
+	obj.EmissionStrength = mat.EmissionStrength
+	obj.Texture = mat.Texture
+	obj.Procedural = nil
+	if mat.Procedural != nil {
+		procedural := *mat.Procedural
+		obj.Procedural = &procedural
+	}
+}
+
+// WithMaterial is a function to get a copy of the Object using another Material.
+// The copy shares the geometry (vertices, triangles, normals...) with the Object.
+//
+// Parameters:
+//  mat - the material.
+//
+// Returns:
+//  the copy.
+//  an error if the material is invalid or a texture can not be loaded.
+//
+func (obj *Object) WithMaterial(mat *Material) (*Object, error) {
+	objAux := *obj
+	objAux.SetMaterial(mat)
+	if err := objAux.CheckIntegrity(); err != nil {
+		return nil, err
+	}
+	// the images are shared by the materials, loading them once
+	if objAux.Texture != nil && !objAux.Texture.Loaded() {
+		if err := objAux.Texture.Load(); err != nil {
+			return nil, err
+		}
+	}
+	if objAux.Procedural != nil {
+		// the object space is the one of the vertices, instances move it with the object
+		center := obj.GetLocalCenter()
+		if err := objAux.Procedural.Init(center.Coordinates); err != nil {
+			return nil, err
+		}
+	}
+	return &objAux, nil
+}
@@ -0,0 +1,39 @@
+package general
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+func TestSetMaterial(t *testing.T) {
+	tests := []struct {
+		name  string
+		mat   Material
+		color []float64
+	}{
+		{"with color", Material{Color: []float64{1, 0, 0}, DiffuseReflection: 0.5}, []float64{1, 0, 0}},
+		// a material without a color keeps the one of the object
+		{"without color", Material{DiffuseReflection: 0.5}, []float64{0.2, 0.4, 0.6}},
+	}
+	for _, test := range tests {
+		obj := roof(NormalFlat, 0)
+		obj.Color = []float64{0.2, 0.4, 0.6}
+		obj.Normals = []utils.Vector{{Coordinates: []float64{0, 0, 1}}}
+		obj.Triangles[0].Normals = []int{0, 0, 0}
+		obj.Triangles[1].Normals = []int{0, 0, 0}
+		withMat, err := obj.WithMaterial(&test.mat)
+		if err != nil {
+			t.Fatalf("%s: WithMaterial: %v", test.name, err)
+		}
+		for i := range test.color {
+			if withMat.Color[i] != test.color[i] {
+				t.Errorf("%s: got color %v, want %v", test.name, withMat.Color, test.color)
+				break
+			}
+		}
+		if withMat.DiffuseReflection != 0.5 {
+			t.Errorf("%s: got DiffuseReflection %g, want 0.5", test.name, withMat.DiffuseReflection)
+		}
+	}
+}
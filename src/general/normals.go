@@ -0,0 +1,236 @@
+package general
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Normal generation modes.
+//
+// NormalFlat        - one normal per triangle.
+// NormalSmoothArea  - vertex normals averaging the faces weighted by their area.
+// NormalSmoothAngle - vertex normals averaging the faces weighted by the angle of the corner.
+//
+const (
+	NormalFlat        = "flat"
+	NormalSmoothArea  = "area"
+	NormalSmoothAngle = "angle"
+)
+
+// HasValidNormals is a function to check if every triangle points to existing normals.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  true if all normal indices are in range.
+//
+func (obj *Object) HasValidNormals() bool {
+	for _, triangle := range obj.Triangles {
+		if len(triangle.Normals) != 3 {
+			return false
+		}
+		for _, idx := range triangle.Normals {
+			if idx < 0 || idx >= len(obj.Normals) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EnsureNormals is a function to generate the normals of an object only when they are missing.
+// The corners with a valid normal keep it (see GenerateMissingNormals).
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  none
+//
+func (obj *Object) EnsureNormals() {
+	if !obj.HasValidNormals() {
+		obj.GenerateMissingNormals()
+	}
+}
+
+// GenerateMissingNormals is a function to generate the normals of the triangle corners without one,
+// keeping the normals of the other corners. The generated normals use the NormalMode.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  none
+//
+func (obj *Object) GenerateMissingNormals() {
+	generated := *obj
+	generated.Triangles = make([]entity.Triangle, len(obj.Triangles))
+	copy(generated.Triangles, obj.Triangles)
+	generated.GenerateNormals()
+	// index of each generated normal on the object
+	added := make(map[int]int)
+	count := len(obj.Normals)
+	for i, triangle := range obj.Triangles {
+		normals := []int{-1, -1, -1}
+		if len(triangle.Normals) == 3 {
+			copy(normals, triangle.Normals)
+		}
+		for c := 0; c < 3; c++ {
+			if normals[c] >= 0 && normals[c] < count {
+				continue
+			}
+			generatedIdx := generated.Triangles[i].Normals[c]
+			idx, found := added[generatedIdx]
+			if !found {
+				idx = len(obj.Normals)
+				added[generatedIdx] = idx
+				obj.Normals = append(obj.Normals, generated.Normals[generatedIdx])
+			}
+			normals[c] = idx
+		}
+		obj.Triangles[i].Normals = normals
+	}
+}
+
+// faceNormals is a function to get the non normalized normal (cross product of the edges) of every triangle.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  the list of face normals, their length is twice the triangle area.
+//
+func (obj *Object) faceNormals() []utils.Vector {
+	normals := make([]utils.Vector, len(obj.Triangles))
+	for i, triangle := range obj.Triangles {
+		p0 := obj.Vertices.Points[triangle.Vertices[0]]
+		p1 := obj.Vertices.Points[triangle.Vertices[1]]
+		p2 := obj.Vertices.Points[triangle.Vertices[2]]
+		// the points are 3D after CheckIntegrity, ignoring the errors
+		edge1, _ := entity.ExtractVector(&p0, &p1)
+		edge2, _ := entity.ExtractVector(&p0, &p2)
+		normals[i], _ = utils.VectorCrossProduct(&edge1, &edge2)
+	}
+	return normals
+}
+
+// cornerAngle is a function to get the angle of a triangle at one of its corners.
+//
+// Parameters:
+// 	triangle - the triangle.
+//  corner   - the corner (0, 1 or 2).
+//
+// Returns:
+//  the angle in radians.
+//
+func (obj *Object) cornerAngle(triangle entity.Triangle, corner int) float64 {
+	p := obj.Vertices.Points[triangle.Vertices[corner]]
+	a := obj.Vertices.Points[triangle.Vertices[(corner+1)%3]]
+	b := obj.Vertices.Points[triangle.Vertices[(corner+2)%3]]
+	// the points are 3D after CheckIntegrity, ignoring the errors
+	va, _ := entity.ExtractVector(&p, &a)
+	vb, _ := entity.ExtractVector(&p, &b)
+	den := utils.VectorNorm(&va) * utils.VectorNorm(&vb)
+	if den == 0 {
+		return 0
+	}
+	return math.Acos(math.Max(-1, math.Min(1, utils.DotProduct(&va, &vb)/den)))
+}
+
+// normalizeOrDefault is a function to normalize a vector, returning a default one for null vectors.
+//
+// Parameters:
+// 	vect - the vector.
+//
+// Returns:
+//  the normalized vector.
+//
+func normalizeOrDefault(vect utils.Vector) utils.Vector {
+	if utils.VectorNorm(&vect) == 0 {
+		return utils.Vector{Coordinates: []float64{0, 0, 1}}
+	}
+	return utils.NormalizeVector(&vect)
+}
+
+// GenerateNormals is a function to replace the normals of the object using its NormalMode.
+// Flat mode is used when NormalMode is empty. On smooth modes two faces are only averaged
+// when the angle between them is at most CreaseAngle degrees (0 means no limit).
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  none
+//
+func (obj *Object) GenerateNormals() {
+	faceNormals := obj.faceNormals()
+	unitNormals := make([]utils.Vector, len(faceNormals))
+	for i := range faceNormals {
+		unitNormals[i] = normalizeOrDefault(faceNormals[i])
+	}
+
+	normals := make([]utils.Vector, 0, len(obj.Triangles))
+	if obj.NormalMode == "" || obj.NormalMode == NormalFlat {
+		for i := range obj.Triangles {
+			normals = append(normals, unitNormals[i])
+			obj.Triangles[i].Normals = []int{i, i, i}
+		}
+		obj.Normals = normals
+		return
+	}
+
+	// faces around each vertex
+	type corner struct {
+		triangle int
+		weight   float64
+	}
+	vertexFaces := make([][]corner, len(obj.Vertices.Points))
+	for i, triangle := range obj.Triangles {
+		for c := 0; c < 3; c++ {
+			weight := utils.VectorNorm(&faceNormals[i]) / 2
+			if obj.NormalMode == NormalSmoothAngle {
+				weight = obj.cornerAngle(triangle, c)
+			}
+			vertexFaces[triangle.Vertices[c]] = append(vertexFaces[triangle.Vertices[c]], corner{triangle: i, weight: weight})
+		}
+	}
+
+	cosCrease := -1.0
+	if obj.CreaseAngle > 0 {
+		cosCrease = math.Cos(obj.CreaseAngle * math.Pi / 180)
+	}
+	type normalKey struct {
+		vertex  int
+		x, y, z float64
+	}
+	normalIndices := make(map[normalKey]int)
+	for i, triangle := range obj.Triangles {
+		triangleNormals := make([]int, 3)
+		for c := 0; c < 3; c++ {
+			vertex := triangle.Vertices[c]
+			sum, _ := utils.InitVector(3) // a valid size
+			for _, other := range vertexFaces[vertex] {
+				if utils.DotProduct(&unitNormals[i], &unitNormals[other.triangle]) >= cosCrease {
+					sum = utils.SumVector(&sum, &unitNormals[other.triangle], 1, other.weight)
+				}
+			}
+			if utils.VectorNorm(&sum) == 0 {
+				sum = unitNormals[i]
+			}
+			sum = normalizeOrDefault(sum)
+			key := normalKey{vertex: vertex, x: sum.Coordinates[0], y: sum.Coordinates[1], z: sum.Coordinates[2]}
+			idx, found := normalIndices[key]
+			if !found {
+				idx = len(normals)
+				normalIndices[key] = idx
+				normals = append(normals, sum)
+			}
+			triangleNormals[c] = idx
+		}
+		obj.Triangles[i].Normals = triangleNormals
+	}
+	obj.Normals = normals
+}
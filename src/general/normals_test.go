@@ -0,0 +1,137 @@
+package general
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// roof is a function to create two triangles meeting at 90 degrees on the ridge from (0, 0, 0) to (0, 1, 0).
+// The face normals are (-1, 0, 1) and (1, 0, 1), normalized.
+func roof(mode string, crease float64) *Object {
+	points := []entity.Point{
+		{Coordinates: []float64{0, 0, 0}},
+		{Coordinates: []float64{0, 1, 0}},
+		{Coordinates: []float64{-1, 0, -1}},
+		{Coordinates: []float64{1, 0, -1}},
+	}
+	return &Object{
+		Vertices: entity.InitVertices(points),
+		Triangles: []entity.Triangle{
+			entity.InitTriangle([]int{0, 1, 2}, []int{-1, -1, -1}),
+			entity.InitTriangle([]int{0, 3, 1}, []int{-1, -1, -1}),
+		},
+		NormalMode:  mode,
+		CreaseAngle: crease,
+	}
+}
+
+// checkNormal is a function to compare the normal of a triangle corner with the expected one.
+func checkNormal(t *testing.T, obj *Object, triangle, corner int, want []float64) {
+	t.Helper()
+	idx := obj.Triangles[triangle].Normals[corner]
+	if idx < 0 || idx >= len(obj.Normals) {
+		t.Fatalf("triangle %d corner %d: normal index %d out of %d", triangle, corner, idx, len(obj.Normals))
+	}
+	got := obj.Normals[idx].Coordinates
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("triangle %d corner %d: got normal %v, want %v", triangle, corner, got, want)
+			return
+		}
+	}
+}
+
+func TestGenerateNormalsFlat(t *testing.T) {
+	obj := roof(NormalFlat, 0)
+	obj.GenerateNormals()
+	if len(obj.Normals) != 2 {
+		t.Fatalf("got %d normals, want one per triangle", len(obj.Normals))
+	}
+	for c := 0; c < 3; c++ {
+		checkNormal(t, obj, 0, c, []float64{-math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+		checkNormal(t, obj, 1, c, []float64{math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+	}
+}
+
+func TestGenerateNormalsSmooth(t *testing.T) {
+	for _, mode := range []string{NormalSmoothArea, NormalSmoothAngle} {
+		t.Run(mode, func(t *testing.T) {
+			obj := roof(mode, 0)
+			obj.GenerateNormals()
+			// the ridge averages both faces, the other corners only touch one
+			checkNormal(t, obj, 0, 0, []float64{0, 0, 1})
+			checkNormal(t, obj, 0, 1, []float64{0, 0, 1})
+			checkNormal(t, obj, 1, 0, []float64{0, 0, 1})
+			checkNormal(t, obj, 0, 2, []float64{-math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+			checkNormal(t, obj, 1, 1, []float64{math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+			if obj.Triangles[0].Normals[0] != obj.Triangles[1].Normals[0] {
+				t.Errorf("the shared ridge corner has two normals")
+			}
+		})
+	}
+}
+
+func TestGenerateNormalsCreaseAngle(t *testing.T) {
+	tests := []struct {
+		crease float64
+		ridge  []float64
+	}{
+		{45, []float64{-math.Sqrt2 / 2, 0, math.Sqrt2 / 2}},
+		{100, []float64{0, 0, 1}},
+	}
+	for _, test := range tests {
+		obj := roof(NormalSmoothArea, test.crease)
+		obj.GenerateNormals()
+		checkNormal(t, obj, 0, 0, test.ridge)
+	}
+}
+
+func TestGenerateNormalsDegenerate(t *testing.T) {
+	points := []entity.Point{
+		{Coordinates: []float64{0, 0, 0}},
+		{Coordinates: []float64{1, 0, 0}},
+		{Coordinates: []float64{2, 0, 0}},
+	}
+	obj := &Object{
+		Vertices:  entity.InitVertices(points),
+		Triangles: []entity.Triangle{entity.InitTriangle([]int{0, 1, 2}, []int{-1, -1, -1})},
+	}
+	obj.GenerateNormals()
+	checkNormal(t, obj, 0, 0, []float64{0, 0, 1})
+}
+
+func TestEnsureNormalsKeepsValidCorners(t *testing.T) {
+	obj := roof(NormalFlat, 0)
+	custom := utils.Vector{Coordinates: []float64{0, 1, 0}}
+	obj.Normals = []utils.Vector{custom}
+	obj.Triangles[0].Normals = []int{0, 0, 0}
+	// the second triangle has a missing corner and one out of range
+	obj.Triangles[1].Normals = []int{0, -1, 7}
+	obj.EnsureNormals()
+	if !obj.HasValidNormals() {
+		t.Fatalf("normals still invalid: %+v", obj.Triangles)
+	}
+	for c := 0; c < 3; c++ {
+		checkNormal(t, obj, 0, c, custom.Coordinates)
+	}
+	checkNormal(t, obj, 1, 0, custom.Coordinates)
+	checkNormal(t, obj, 1, 1, []float64{math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+	checkNormal(t, obj, 1, 2, []float64{math.Sqrt2 / 2, 0, math.Sqrt2 / 2})
+	if len(obj.Normals) != 2 {
+		t.Errorf("got %d normals, want the kept one and one generated", len(obj.Normals))
+	}
+}
+
+func TestEnsureNormalsKeepsValidObjects(t *testing.T) {
+	obj := roof(NormalFlat, 0)
+	obj.Normals = []utils.Vector{{Coordinates: []float64{0, 1, 0}}}
+	obj.Triangles[0].Normals = []int{0, 0, 0}
+	obj.Triangles[1].Normals = []int{0, 0, 0}
+	obj.EnsureNormals()
+	if len(obj.Normals) != 1 {
+		t.Errorf("got %d normals, want the given one", len(obj.Normals))
+	}
+}
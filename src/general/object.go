@@ -2,26 +2,32 @@ package general
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path"
 	"path/filepath"
 
 	"github.com/lucas625/Projeto-CG/src/camera"
 	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/texture"
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
 // Objects is a class for all objects.
 //
 // Members:
-// Label   - the label.
-// ObjList - a list of objects.
+// Label     - the label.
+// ObjList   - a list of objects.
+// Meshes    - objects only rendered through Instances.
+// Instances - placements of the Meshes.
 //
 type Objects struct {
-	Label   string
-	ObjList []Object
+	Label     string
+	ObjList   []Object
+	Meshes    []Object
+	Instances []Instance
 }
 
 // WriteJSONObjects is a function to write all Object data as json.
@@ -30,23 +36,27 @@ type Objects struct {
 //  outPath - path to the output folder.
 //
 // Returns:
-//  none
+//  an error if the objects could not be written.
 //
-func (objs *Objects) WriteJSONObjects(outPath string) {
+func (objs *Objects) WriteJSONObjects(outPath string) error {
 	// creating the json
 	file, err := json.MarshalIndent(*objs, "", "	")
-	utils.ShowError(err, "Unable to convert object to json.")
+	if err != nil {
+		return err
+	}
 	// getting the right path
 	filePath, err := filepath.Abs(path.Join(outPath, objs.Label+".json"))
-	utils.ShowError(err, "Unable to get objects's absolute path.")
+	if err != nil {
+		return err
+	}
 	// creating the folder if it doesn't exists.
 	if !utils.PathExists(filePath) {
-		err = os.MkdirAll(outPath, 0700)
-		utils.ShowError(err, "Unable to create dirs.")
+		if err = os.MkdirAll(outPath, 0700); err != nil {
+			return err
+		}
 	}
 	// writing
-	err = ioutil.WriteFile(filePath, file, 0700)
-	utils.ShowError(err, "Unable to write objects.")
+	return ioutil.WriteFile(filePath, file, 0700)
 }
 
 // LoadJSONObjects is a function to read all Objects data as json.
@@ -56,22 +66,53 @@ func (objs *Objects) WriteJSONObjects(outPath string) {
 //
 // Returns:
 //  the objects.
+//  a *utils.FileError if the file could not be read, parsed or validated.
 //
-func LoadJSONObjects(inPath string) *Objects {
-	// opening the file
-	objsFile, err := os.Open(inPath)
-	utils.ShowError(err, "Unable to open objects.")
-	// converting to cam
-	byteCamera, err := ioutil.ReadAll(objsFile)
-	utils.ShowError(err, "Unable to convert objects file to bytes.")
+func LoadJSONObjects(inPath string) (*Objects, error) {
+	byteObjects, err := ioutil.ReadFile(inPath)
+	if err != nil {
+		return nil, err
+	}
 	var objsAux Objects
-	err = json.Unmarshal(byteCamera, &objsAux)
-	utils.ShowError(err, "Failed to unmarshal objects.")
-	for _, obj := range objsAux.ObjList {
-		obj.CheckIntegrity()
-		obj.NormalizeNormals()
+	if err = json.Unmarshal(byteObjects, &objsAux); err != nil {
+		return nil, utils.WrapFile("parse", inPath, err)
+	}
+	if err = objsAux.prepare(); err != nil {
+		return nil, utils.WrapFile("load", inPath, err)
+	}
+	return &objsAux, nil
+}
+
+// prepare is a function to check the objects and instances and load their textures.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError with the path to the invalid object or instance.
+//
+func (objs *Objects) prepare() error {
+	lists := []struct {
+		field string
+		objs  []Object
+	}{{"ObjList", objs.ObjList}, {"Meshes", objs.Meshes}}
+	for _, list := range lists {
+		for i := range list.objs {
+			if err := list.objs[i].Prepare(); err != nil {
+				return utils.WrapField(fmt.Sprintf("%s[%d]", list.field, i), err)
+			}
+		}
 	}
-	return &objsAux
+	for i := range objs.Instances {
+		field := fmt.Sprintf("Instances[%d]", i)
+		if _, _, err := objs.Instances[i].GetTransform(); err != nil {
+			return utils.WrapField(field, err)
+		}
+		if _, found := objs.FindMesh(objs.Instances[i].Mesh); !found {
+			return utils.WrapField(field+".Mesh", fmt.Errorf("mesh %q not found", objs.Instances[i].Mesh))
+		}
+	}
+	return nil
 }
 
 // InitObjects is a function to initialize a objects.
@@ -101,6 +142,20 @@ func InitObjects(label string, objlist []Object) *Objects {
 //  AmbientReflection  - RGB for the ambient reflection.
 //  DiffuseReflection  - Diffuse reflection coeficient.
 //  RoughNess          - How much reflections rays get distorted.
+//  NormalMode         - how missing normals are generated (flat, area or angle).
+//  CreaseAngle        - max angle in degrees between faces smoothed together.
+//  RefractiveIndex    - index of refraction used by transmission.
+//  Emission           - RGB emitted by the object (optional).
+//  EmissionStrength   - intensity multiplying the Emission (1 when 0).
+//  TexCoords          - list of texture coordinates (u, v).
+//  Texture            - image multiplying the Color (optional).
+//  Tangents           - list of tangents (x, y, z, handedness) used by NormalMap and BumpMap.
+//  NormalMap          - tangent space normal map (optional).
+//  BumpMap            - grayscale height map (optional).
+//  BumpScale          - strength of the BumpMap (1 when 0).
+//  Procedural         - procedural texture multiplying the Color (optional).
+//  Primitive          - analytic surface rendered instead of the Triangles (optional).
+//  Transform          - placement of the vertices or primitive in the world (optional).
 //
 type Object struct {
 	Name               string
@@ -113,7 +168,21 @@ type Object struct {
 	TransReflection    float64
 	AmbientReflection  float64
 	DiffuseReflection  float64
-	RoughNess float64
+	RoughNess          float64
+	NormalMode         string
+	CreaseAngle        float64
+	RefractiveIndex    float64
+	Emission           []float64
+	EmissionStrength   float64
+	TexCoords          []utils.Vector
+	Texture            *texture.Image
+	Tangents           []utils.Vector
+	NormalMap          *texture.Image
+	BumpMap            *texture.Image
+	BumpScale          float64
+	Procedural         *texture.Procedural
+	Primitive          *Primitive
+	Transform          *Transform
 }
 
 // CheckIntegrity is a function to check the attributes of an object.
@@ -122,30 +191,98 @@ type Object struct {
 // 	none
 //
 // Returns:
-//  none
+//  a *utils.FieldError with the path to the first invalid attribute.
 //
-func (obj *Object) CheckIntegrity() {
-	for _, vertice := range obj.Vertices.Points {
-		if len(vertice.Coordinates) != 3 {
-			utils.ShowError(errors.New("Invalid object"), "Vertices length not equal 3.")
+func (obj *Object) CheckIntegrity() error {
+	for i, vertice := range obj.Vertices.Points {
+		if err := utils.CheckSize(len(vertice.Coordinates), 3); err != nil {
+			return utils.WrapField(fmt.Sprintf("Vertices.Points[%d]", i), err)
 		}
 	}
-	for _, triangle := range obj.Triangles {
-		if len(triangle.Vertices) != 3 {
-			utils.ShowError(errors.New("Invalid object"), "Triangle with number of vertices not equal 3.")
+	for i, triangle := range obj.Triangles {
+		field := fmt.Sprintf("Triangles[%d]", i)
+		if err := utils.CheckSize(len(triangle.Vertices), 3); err != nil {
+			return utils.WrapField(field+".Vertices", err)
+		}
+		if err := checkIndices(triangle.Vertices, len(obj.Vertices.Points)); err != nil {
+			return utils.WrapField(field+".Vertices", err)
 		}
-		if len(triangle.Normals) != 3 {
-			utils.ShowError(errors.New("Invalid object"), "Triangle with number of normals not equal 3.")
+		if err := utils.CheckSize(len(triangle.Normals), 3); err != nil {
+			return utils.WrapField(field+".Normals", err)
+		}
+		if len(triangle.TexCoords) != 0 {
+			if err := utils.CheckSize(len(triangle.TexCoords), 3); err != nil {
+				return utils.WrapField(field+".TexCoords", err)
+			}
+			if err := checkIndices(triangle.TexCoords, len(obj.TexCoords)); err != nil {
+				return utils.WrapField(field+".TexCoords", err)
+			}
 		}
 	}
-	for _, normal := range obj.Normals {
-		if len(normal.Coordinates) != 3 {
-			utils.ShowError(errors.New("Invalid object"), "Normal length not equal 3.")
+	for i, uv := range obj.TexCoords {
+		if err := utils.CheckSize(len(uv.Coordinates), 2); err != nil {
+			return utils.WrapField(fmt.Sprintf("TexCoords[%d]", i), err)
 		}
 	}
-	if len(obj.Color) != 3 {
-		utils.ShowError(errors.New("Invalid object"), "Color length not equal 3.")
+	for i, normal := range obj.Normals {
+		if err := utils.CheckSize(len(normal.Coordinates), 3); err != nil {
+			return utils.WrapField(fmt.Sprintf("Normals[%d]", i), err)
+		}
+	}
+	if err := utils.CheckSize(len(obj.Color), 3); err != nil {
+		return utils.WrapField("Color", err)
+	}
+	if len(obj.Emission) != 0 {
+		if err := utils.CheckSize(len(obj.Emission), 3); err != nil {
+			return utils.WrapField("Emission", err)
+		}
 	}
+	if obj.Primitive != nil {
+		if err := obj.Primitive.CheckIntegrity(); err != nil {
+			return utils.WrapField("Primitive", err)
+		}
+	}
+	if obj.Transform != nil {
+		if _, _, err := obj.Transform.GetMatrices(); err != nil {
+			return utils.WrapField("Transform", err)
+		}
+	}
+	return nil
+}
+
+// checkIndices is a function to check that indices point to elements of a list.
+//
+// Parameters:
+// 	indices - the indices.
+//  count   - the length of the list.
+//
+// Returns:
+//  an error with the first index out of range.
+//
+func checkIndices(indices []int, count int) error {
+	for _, idx := range indices {
+		if idx < 0 || idx >= count {
+			return fmt.Errorf("index %d outside of [0, %d)", idx, count)
+		}
+	}
+	return nil
+}
+
+// Prepare is a function to check an object loaded from a file, complete its normals and load its textures.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  a *utils.FieldError if an attribute is invalid, or the error of a texture.
+//
+func (obj *Object) Prepare() error {
+	if err := obj.CheckIntegrity(); err != nil {
+		return err
+	}
+	obj.EnsureNormals()
+	obj.NormalizeNormals()
+	return obj.LoadTextures()
 }
 
 // GetNormalByBaricentricCoords is a function to calculate a vertice normal using baricientric coordinates.
@@ -162,7 +299,7 @@ func (obj *Object) GetNormalByBaricentricCoords(triangleIdx int, baricentricCoor
 	for i := 0; i < 3; i++ {
 		normals[i] = utils.CMultVector(&obj.Normals[obj.Triangles[triangleIdx].Normals[i]], baricentricCoords[i])
 	}
-	resultingNormal := utils.InitVector(3)
+	resultingNormal, _ := utils.InitVector(3) // a valid size
 	for i := 0; i < 3; i++ {
 		resultingNormal = utils.SumVector(&resultingNormal, &normals[i], 1, 1)
 	}
@@ -170,6 +307,78 @@ func (obj *Object) GetNormalByBaricentricCoords(triangleIdx int, baricentricCoor
 	return resultingNormal
 }
 
+// GetTexCoordByBaricentricCoords is a function to interpolate the texture coordinates of a triangle.
+//
+// Parameters:
+// 	triangleIdx       - the index of the triangle.
+//  baricentricCoords - the baricentric coords of the point.
+//
+// Returns:
+//  the (u, v) coordinates.
+//  false if the triangle has no texture coordinates.
+//
+func (obj *Object) GetTexCoordByBaricentricCoords(triangleIdx int, baricentricCoords []float64) ([]float64, bool) {
+	triangle := obj.Triangles[triangleIdx]
+	if len(triangle.TexCoords) != 3 {
+		return nil, false
+	}
+	uv := make([]float64, 2)
+	for i := 0; i < 3; i++ {
+		coords := obj.TexCoords[triangle.TexCoords[i]].Coordinates
+		uv[0] += coords[0] * baricentricCoords[i]
+		uv[1] += coords[1] * baricentricCoords[i]
+	}
+	return uv, true
+}
+
+// GetEmission is a function to get the radiance emitted by both sides of the surface of the object.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  the Emission multiplied by the EmissionStrength, nil when the object does not emit.
+//
+func (obj *Object) GetEmission() []float64 {
+	if len(obj.Emission) != 3 || obj.Emission[0]+obj.Emission[1]+obj.Emission[2] <= 0 {
+		return nil
+	}
+	strength := obj.EmissionStrength
+	if strength == 0 {
+		strength = 1
+	}
+	return []float64{obj.Emission[0] * strength, obj.Emission[1] * strength, obj.Emission[2] * strength}
+}
+
+// GetAlbedo is a function to get the color of the object at a point of its surface.
+// The Color is multiplied by the Texture when the point has texture coordinates
+// and by the Procedural texture evaluated at the position.
+//
+// Parameters:
+// 	uv    - the texture coordinates of the point, nil when there are none.
+//  pos   - the position of the point in world space.
+//  local - the position of the point before the Transform of the object and its instance.
+//
+// Returns:
+//  the RGB.
+//
+func (obj *Object) GetAlbedo(uv []float64, pos, local entity.Point) []float64 {
+	albedo := append([]float64{}, obj.Color...)
+	if obj.Texture != nil && obj.Texture.Loaded() && uv != nil {
+		texColor := obj.Texture.Sample(uv[0], uv[1])
+		for i := 0; i < 3; i++ {
+			albedo[i] *= texColor[i]
+		}
+	}
+	if obj.Procedural != nil {
+		procColor := obj.Procedural.Evaluate(pos.Coordinates, local.Coordinates)
+		for i := 0; i < 3; i++ {
+			albedo[i] *= procColor[i]
+		}
+	}
+	return albedo
+}
+
 // NormalizeNormals is a function to normalize all triangle normals.
 //
 // Parameters:
@@ -192,18 +401,39 @@ func (obj *Object) NormalizeNormals() {
 // 	none
 //
 // Returns:
-//  the pos.
+//  the pos, in world space.
 //
 func (obj *Object) GetCenter() entity.Point {
-	objectBB := obj.GetBoundingBox()
-	pos := entity.InitPoint(3)
+	pos := obj.GetLocalCenter()
+	if obj.Transform != nil {
+		if matrix, err := obj.Transform.GetMatrix(); err == nil {
+			pos = entity.TransformPoint(&matrix, &pos)
+		}
+	}
+	return pos
+}
+
+// GetLocalCenter is a function to get the center of an Object before its Transform.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+//  the pos, in the space of the vertices or primitive.
+//
+func (obj *Object) GetLocalCenter() entity.Point {
+	if obj.Primitive != nil {
+		return obj.Primitive.GetCenter()
+	}
+	objectBB := obj.GetLocalBoundingBox()
+	pos, _ := entity.InitPoint(3) // a valid size
 	for j := 0; j < 3; j++ {
 		pos.Coordinates[j] = (objectBB[j] + objectBB[j+3]) / 2
 	}
 	return pos
 }
 
-// GetBoundingBox is a function to get the bounding box of an Object.
+// GetBoundingBox is a function to get the bounding box of an Object in world space.
 //
 // Parameters:
 //  none
@@ -212,6 +442,34 @@ func (obj *Object) GetCenter() entity.Point {
 //  [minX, minY, minZ, maxX, maxY, maxZ]
 //
 func (obj *Object) GetBoundingBox() []float64 {
+	bb := obj.GetLocalBoundingBox()
+	if obj.Transform == nil {
+		return bb
+	}
+	for _, value := range bb {
+		if math.IsInf(value, 0) {
+			return bb
+		}
+	}
+	matrix, err := obj.Transform.GetMatrix()
+	if err != nil {
+		return bb
+	}
+	return TransformBoundingBox(&matrix, bb)
+}
+
+// GetLocalBoundingBox is a function to get the bounding box of an Object before its Transform.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ]
+//
+func (obj *Object) GetLocalBoundingBox() []float64 {
+	if obj.Primitive != nil {
+		return obj.Primitive.GetBoundingBox()
+	}
 	vertices := obj.Vertices
 	bb := make([]float64, 6)
 	for i := 0; i < 3; i++ {
@@ -238,19 +496,23 @@ func (obj *Object) GetBoundingBox() []float64 {
 //
 // Returns:
 //  the camera.
+//  an error if the position is not 3D.
 //
-func (obj *Object) FindCamera(ptCamera *entity.Point) *camera.Camera {
+func (obj *Object) FindCamera(ptCamera *entity.Point) (*camera.Camera, error) {
 	bb := obj.GetBoundingBox()
 	vList := make([]float64, 3)
 	for i := 0; i < 3; i++ {
 		vList[i] = bb[i+3] - bb[i]
 	}
 	ptTarget := entity.Point{Coordinates: vList}
-	cam := camera.InitCameraWithPoints(ptCamera, &ptTarget)
-	return &cam
+	cam, err := camera.InitCameraWithPoints(ptCamera, &ptTarget)
+	if err != nil {
+		return nil, err
+	}
+	return &cam, nil
 }
 
-// InitObject is a function to initialize an Object.
+// InitObject is a function to initialize an Object, generating flat normals when they are missing.
 //
 // Parameters:
 //  name               - the name of the object.
@@ -267,9 +529,13 @@ func (obj *Object) FindCamera(ptCamera *entity.Point) *camera.Camera {
 //
 // Returns:
 //  the object.
+//  a *utils.FieldError if an attribute is invalid.
 //
-func InitObject(name string, vertices entity.Vertices, triangles []entity.Triangle, normals []utils.Vector, color []float64, specularDecay, ambientReflection, diffuseReflection, specularReflection, transReflection, roughNess float64) Object {
+func InitObject(name string, vertices entity.Vertices, triangles []entity.Triangle, normals []utils.Vector, color []float64, specularDecay, ambientReflection, diffuseReflection, specularReflection, transReflection, roughNess float64) (Object, error) {
 	obj := Object{Name: name, Vertices: vertices, Triangles: triangles, Normals: normals, Color: color, SpecularDecay: specularDecay, AmbientReflection: ambientReflection, DiffuseReflection: diffuseReflection, SpecularReflection: specularReflection, TransReflection: transReflection, RoughNess: roughNess}
-	obj.CheckIntegrity()
-	return obj
+	if err := obj.CheckIntegrity(); err != nil {
+		return obj, err
+	}
+	obj.EnsureNormals()
+	return obj, nil
 }
@@ -0,0 +1,141 @@
+package general
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Analytic primitive types.
+const (
+	PrimitiveSphere = "sphere"
+	PrimitivePlane  = "plane"
+	PrimitiveDisk   = "disk"
+	PrimitiveQuad   = "quad"
+)
+
+// Primitive is a class for the description of an analytic surface, used instead of triangles.
+//
+// Members:
+//  Type   - sphere, plane, disk or quad.
+//  Center - center of the sphere and disk, or a point of the plane.
+//  Radius - radius of the sphere and disk.
+//  Normal - normal of the plane and disk.
+//  Corner - first corner of the quad.
+//  Edges  - the two edges of the quad leaving the Corner, the normal is their cross product.
+//
+type Primitive struct {
+	Type   string
+	Center []float64
+	Radius float64
+	Normal []float64
+	Corner []float64
+	Edges  [][]float64
+}
+
+// CheckIntegrity is a function to check the attributes used by the type of the primitive.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError with the invalid attribute.
+//
+func (prim *Primitive) CheckIntegrity() error {
+	switch prim.Type {
+	case PrimitiveSphere, PrimitiveDisk:
+		if err := utils.CheckSize(len(prim.Center), 3); err != nil {
+			return utils.WrapField("Center", err)
+		}
+		if prim.Radius <= 0 {
+			return utils.WrapField("Radius", fmt.Errorf("invalid radius %g", prim.Radius))
+		}
+		if prim.Type == PrimitiveDisk {
+			if err := utils.CheckSize(len(prim.Normal), 3); err != nil {
+				return utils.WrapField("Normal", err)
+			}
+		}
+	case PrimitivePlane:
+		if err := utils.CheckSize(len(prim.Center), 3); err != nil {
+			return utils.WrapField("Center", err)
+		}
+		if err := utils.CheckSize(len(prim.Normal), 3); err != nil {
+			return utils.WrapField("Normal", err)
+		}
+	case PrimitiveQuad:
+		if err := utils.CheckSize(len(prim.Corner), 3); err != nil {
+			return utils.WrapField("Corner", err)
+		}
+		if err := utils.CheckSize(len(prim.Edges), 2); err != nil {
+			return utils.WrapField("Edges", err)
+		}
+		for i, edge := range prim.Edges {
+			if err := utils.CheckSize(len(edge), 3); err != nil {
+				return utils.WrapField(fmt.Sprintf("Edges[%d]", i), err)
+			}
+		}
+	default:
+		return utils.WrapField("Type", fmt.Errorf("invalid primitive type %q", prim.Type))
+	}
+	return nil
+}
+
+// GetCenter is a function to get the center of the primitive.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the center, the Center point for planes.
+//
+func (prim *Primitive) GetCenter() entity.Point {
+	pos, _ := entity.InitPoint(3) // a valid size
+	for j := 0; j < 3; j++ {
+		if prim.Type == PrimitiveQuad {
+			pos.Coordinates[j] = prim.Corner[j] + (prim.Edges[0][j]+prim.Edges[1][j])/2
+		} else {
+			pos.Coordinates[j] = prim.Center[j]
+		}
+	}
+	return pos
+}
+
+// GetBoundingBox is a function to get the bounding box of the primitive.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ], infinite for planes.
+//
+func (prim *Primitive) GetBoundingBox() []float64 {
+	bb := make([]float64, 6)
+	switch prim.Type {
+	case PrimitiveSphere:
+		for j := 0; j < 3; j++ {
+			bb[j] = prim.Center[j] - prim.Radius
+			bb[j+3] = prim.Center[j] + prim.Radius
+		}
+	case PrimitiveDisk:
+		norm := math.Sqrt(prim.Normal[0]*prim.Normal[0] + prim.Normal[1]*prim.Normal[1] + prim.Normal[2]*prim.Normal[2])
+		for j := 0; j < 3; j++ {
+			n := prim.Normal[j] / norm
+			extent := prim.Radius * math.Sqrt(math.Max(0, 1-n*n))
+			bb[j] = prim.Center[j] - extent
+			bb[j+3] = prim.Center[j] + extent
+		}
+	case PrimitiveQuad:
+		for j := 0; j < 3; j++ {
+			bb[j] = prim.Corner[j] + math.Min(0, prim.Edges[0][j]) + math.Min(0, prim.Edges[1][j])
+			bb[j+3] = prim.Corner[j] + math.Max(0, prim.Edges[0][j]) + math.Max(0, prim.Edges[1][j])
+		}
+	default:
+		for j := 0; j < 3; j++ {
+			bb[j] = math.Inf(-1)
+			bb[j+3] = math.Inf(1)
+		}
+	}
+	return bb
+}
@@ -0,0 +1,178 @@
+package general
+
+import (
+	"fmt"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Transform is a class for the placement of an object in the world.
+// The matrix is Matrix * Translate * Rotate * Axis/Angle * Quaternion * Shear * Scale,
+// so points are scaled first and translated last; every field is optional.
+//
+// Members:
+//  Matrix     - 4x4 matrix (line by line) applied after the other fields.
+//  Translate  - offset (x, y, z).
+//  Rotate     - Euler angles in degrees, applied around x, then y, then z.
+//  Axis       - axis of a rotation by Angle.
+//  Angle      - angle in degrees around Axis.
+//  Quaternion - rotation as (w, x, y, z).
+//  Shear      - shear factors (xy, xz, yx, yz, zx, zy).
+//  Scale      - factor of each axis.
+//
+type Transform struct {
+	Matrix     [][]float64
+	Translate  []float64
+	Rotate     []float64
+	Axis       []float64
+	Angle      float64
+	Quaternion []float64
+	Shear      []float64
+	Scale      []float64
+}
+
+// GetMatrix is a function to get the object to world matrix of the Transform.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the matrix.
+//  a *utils.FieldError if a field is invalid.
+//
+func (tr *Transform) GetMatrix() (utils.Matrix, error) {
+	steps := make([]utils.Matrix, 0, 7)
+	if len(tr.Matrix) != 0 {
+		matrix, _ := utils.InitMatrix(4, 4) // 4x4 is always valid
+		if err := utils.CheckSize(len(tr.Matrix), 4); err != nil {
+			return matrix, utils.WrapField("Matrix", err)
+		}
+		for i, line := range tr.Matrix {
+			if err := utils.CheckSize(len(line), 4); err != nil {
+				return matrix, utils.WrapField(fmt.Sprintf("Matrix[%d]", i), err)
+			}
+			copy(matrix.Values[i], line)
+		}
+		steps = append(steps, matrix)
+	}
+	if len(tr.Translate) != 0 {
+		if err := utils.CheckSize(len(tr.Translate), 3); err != nil {
+			return utils.Matrix{}, utils.WrapField("Translate", err)
+		}
+		matrix, err := utils.InitTranslationMatrix(3, tr.Translate)
+		if err != nil {
+			return matrix, utils.WrapField("Translate", err)
+		}
+		steps = append(steps, matrix)
+	}
+	if len(tr.Rotate) != 0 {
+		if err := utils.CheckSize(len(tr.Rotate), 3); err != nil {
+			return utils.Matrix{}, utils.WrapField("Rotate", err)
+		}
+		matrix, err := utils.InitEulerRotationMatrix(tr.Rotate)
+		if err != nil {
+			return matrix, utils.WrapField("Rotate", err)
+		}
+		steps = append(steps, matrix)
+	}
+	if len(tr.Axis) != 0 {
+		if err := utils.CheckSize(len(tr.Axis), 3); err != nil {
+			return utils.Matrix{}, utils.WrapField("Axis", err)
+		}
+		matrix, err := utils.InitRotationMatrix(tr.Axis, tr.Angle)
+		if err != nil {
+			return matrix, utils.WrapField("Axis", err)
+		}
+		steps = append(steps, matrix)
+	}
+	if len(tr.Quaternion) != 0 {
+		q := tr.Quaternion
+		if err := utils.CheckSize(len(q), 4); err != nil {
+			return utils.Matrix{}, utils.WrapField("Quaternion", err)
+		}
+		matrix, err := utils.QuaternionToMatrix(utils.Quaternion{W: q[0], X: q[1], Y: q[2], Z: q[3]})
+		if err != nil {
+			return matrix, utils.WrapField("Quaternion", err)
+		}
+		steps = append(steps, matrix)
+	}
+	if len(tr.Shear) != 0 {
+		s := tr.Shear
+		if err := utils.CheckSize(len(s), 6); err != nil {
+			return utils.Matrix{}, utils.WrapField("Shear", err)
+		}
+		steps = append(steps, utils.InitShearMatrix(s[0], s[1], s[2], s[3], s[4], s[5]))
+	}
+	if len(tr.Scale) != 0 {
+		if err := utils.CheckSize(len(tr.Scale), 3); err != nil {
+			return utils.Matrix{}, utils.WrapField("Scale", err)
+		}
+		matrix, err := utils.InitScaleMatrix(3, tr.Scale)
+		if err != nil {
+			return matrix, utils.WrapField("Scale", err)
+		}
+		steps = append(steps, matrix)
+	}
+
+	matrix, _ := utils.IDMatrix(4)
+	for i := range steps {
+		matrix, _ = utils.MultMatrix(&matrix, &steps[i]) // the steps are 4x4
+	}
+	return matrix, nil
+}
+
+// GetMatrices is a function to get the matrices moving points between the object and the world.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the object to world matrix.
+//  the world to object matrix.
+//  an error if a field is invalid or the matrix is not invertible.
+//
+func (tr *Transform) GetMatrices() (utils.Matrix, utils.Matrix, error) {
+	toWorld, err := tr.GetMatrix()
+	if err != nil {
+		return toWorld, toWorld, err
+	}
+	toObject, err := utils.InvertMatrix(&toWorld)
+	if err != nil {
+		return toWorld, toWorld, err
+	}
+	return toWorld, toObject, nil
+}
+
+// TransformBoundingBox is a function to get the box around a transformed box.
+//
+// Parameters:
+//  matrix - the homogeneous matrix.
+//  bb     - the box [minX, minY, minZ, maxX, maxY, maxZ].
+//
+// Returns:
+//  the transformed box.
+//
+func TransformBoundingBox(matrix *utils.Matrix, bb []float64) []float64 {
+	result := make([]float64, 6)
+	for corner := 0; corner < 8; corner++ {
+		pos, _ := entity.InitPoint(3) // a valid size
+		for j := 0; j < 3; j++ {
+			if corner&(1<<uint(j)) == 0 {
+				pos.Coordinates[j] = bb[j]
+			} else {
+				pos.Coordinates[j] = bb[j+3]
+			}
+		}
+		pos = entity.TransformPoint(matrix, &pos)
+		for j := 0; j < 3; j++ {
+			if corner == 0 || pos.Coordinates[j] < result[j] {
+				result[j] = pos.Coordinates[j]
+			}
+			if corner == 0 || pos.Coordinates[j] > result[j+3] {
+				result[j+3] = pos.Coordinates[j]
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,317 @@
+package obj
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/texture"
+)
+
+// Material is a class for a material of a .mtl file.
+//
+// Members:
+//  Name           - the name given by newmtl.
+//  Ka             - RGB ambient reflectivity.
+//  Kd             - RGB diffuse reflectivity.
+//  Ks             - RGB specular reflectivity.
+//  Ke             - RGB emission.
+//  Ns             - specular exponent.
+//  Ni             - index of refraction.
+//  D              - dissolve (1 is opaque), also read from Tr as 1 - Tr.
+//  Illum          - the illumination model.
+//  Maps           - path of each texture statement (map_Kd, map_Ks, map_Bump, bump, norm, disp...).
+//  BumpMultiplier - the -bm option of the bump map.
+//
+type Material struct {
+	Name           string
+	Ka             []float64
+	Kd             []float64
+	Ks             []float64
+	Ke             []float64
+	Ns             float64
+	Ni             float64
+	D              float64
+	Illum          int
+	Maps           map[string]string
+	BumpMultiplier float64
+}
+
+// InitMaterial is a function to initialize a Material with the .mtl default values.
+//
+// Parameters:
+//  name - the name of the material.
+//
+// Returns:
+//  the material.
+//
+func InitMaterial(name string) *Material {
+	return &Material{
+		Name:           name,
+		Ka:             []float64{0, 0, 0},
+		Kd:             []float64{0, 0, 0},
+		Ks:             []float64{0, 0, 0},
+		Ke:             []float64{0, 0, 0},
+		Ns:             0,
+		Ni:             1,
+		D:              1,
+		Illum:          2,
+		Maps:           make(map[string]string),
+		BumpMultiplier: 1,
+	}
+}
+
+// maxComponent is a function to get the greatest component of a RGB.
+//
+// Parameters:
+//  rgb - the color.
+//
+// Returns:
+//  the greatest component.
+//
+func maxComponent(rgb []float64) float64 {
+	return math.Max(rgb[0], math.Max(rgb[1], rgb[2]))
+}
+
+// Apply is a function to set the surface attributes of an Object from the Material.
+//
+// The conversion is:
+//  Color              = Kd / max(Kd), so the diffuse albedo Color * DiffuseReflection is Kd * d.
+//  AmbientReflection  = max(Ka).
+//  DiffuseReflection  = max(Kd) * d.
+//  SpecularReflection = max(Ks) * d, or 0 when illum is 0 or 1 (no highlights).
+//  TransReflection    = 1 - d.
+//  SpecularDecay      = Ns.
+//  RoughNess          = sqrt(2 / (Ns + 2)), so glossy materials get sharper reflections.
+//  RefractiveIndex    = Ni.
+//  Emission           = Ke.
+//  Texture            = map_Kd, with Kd replaced by white when it is black.
+//  NormalMap          = norm.
+//  BumpMap            = map_Bump or bump, with BumpScale = -bm.
+// When DiffuseReflection + SpecularReflection + TransReflection is greater than 1, the diffuse
+// and specular coeficients are scaled down so the material does not create energy.
+//
+// Parameters:
+//  obj - the object.
+//
+// Returns:
+//  an error when a texture can not be loaded.
+//
+func (mat *Material) Apply(obj *general.Object) error {
+	kd := mat.Kd
+	if path, found := mat.Maps["map_Kd"]; found {
+		obj.Texture = &texture.Image{Path: path, Wrap: texture.WrapRepeat}
+		if maxComponent(kd) == 0 {
+			kd = []float64{1, 1, 1}
+		}
+	}
+	// the strength of Kd goes to DiffuseReflection, only its hue to the Color
+	obj.Color = append([]float64{}, kd...)
+	if diffuse := maxComponent(kd); diffuse > 0 {
+		for i := range obj.Color {
+			obj.Color[i] /= diffuse
+		}
+	}
+	if path, found := mat.Maps["norm"]; found {
+		obj.NormalMap = &texture.Image{Path: path, Wrap: texture.WrapRepeat}
+	}
+	for _, key := range []string{"map_Bump", "bump"} {
+		if path, found := mat.Maps[key]; found {
+			obj.BumpMap = &texture.Image{Path: path, Wrap: texture.WrapRepeat}
+			obj.BumpScale = mat.BumpMultiplier
+		}
+	}
+
+	obj.AmbientReflection = maxComponent(mat.Ka)
+	obj.DiffuseReflection = maxComponent(kd) * mat.D
+	obj.SpecularReflection = maxComponent(mat.Ks) * mat.D
+	if mat.Illum < 2 {
+		obj.SpecularReflection = 0
+	}
+	obj.TransReflection = 1 - mat.D
+	total := obj.DiffuseReflection + obj.SpecularReflection + obj.TransReflection
+	if total > 1 {
+		scale := (1 - obj.TransReflection) / (obj.DiffuseReflection + obj.SpecularReflection)
+		obj.DiffuseReflection *= scale
+		obj.SpecularReflection *= scale
+	}
+	obj.SpecularDecay = mat.Ns
+	obj.RoughNess = math.Sqrt(2 / (mat.Ns + 2))
+	obj.RefractiveIndex = mat.Ni
+	obj.Emission = append([]float64{}, mat.Ke...)
+	// reading the images and generating the tangents of the maps
+	return obj.LoadTextures()
+}
+
+// readRGB is a function to read a color statement of a .mtl file.
+// A single value is used for the 3 components.
+//
+// Parameters:
+//  data   - the .mtl data, used on errors.
+//  fields - the values of the statement.
+//  line   - the line number.
+//
+// Returns:
+//  the color.
+//  an error.
+//
+func readRGB(data *objData, fields []string, line int) ([]float64, error) {
+	values, err := data.readFloats(fields, 1, 3, line)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 1 {
+		return []float64{values[0], values[0], values[0]}, nil
+	}
+	if len(values) != 3 {
+		return nil, data.parseError(line, "expected 1 or 3 color components, got %d", len(values))
+	}
+	return values, nil
+}
+
+// readMap is a function to read a texture statement of a .mtl file.
+//
+// Parameters:
+//  data   - the .mtl data, used on errors.
+//  mat    - the current material.
+//  key    - the statement.
+//  fields - the options and file of the statement.
+//  line   - the line number.
+//
+// Returns:
+//  an error.
+//
+func readMap(data *objData, mat *Material, key string, fields []string, line int) error {
+	if len(fields) == 0 {
+		return data.parseError(line, "%s without a file", key)
+	}
+	for i := 0; i+1 < len(fields)-1; i++ {
+		if fields[i] == "-bm" {
+			bm, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				return data.parseError(line, "invalid bump multiplier %q", fields[i+1])
+			}
+			mat.BumpMultiplier = bm
+		}
+	}
+	// the file is the last value, relative to the .mtl folder
+	file := fields[len(fields)-1]
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(data.path), file)
+	}
+	mat.Maps[key] = file
+	return nil
+}
+
+// readMtlLine is a function to read a logical line of a .mtl file.
+//
+// Parameters:
+//  data      - the .mtl data, used on errors.
+//  materials - the materials read so far.
+//  current   - the current material.
+//  text      - the line without comments.
+//  line      - the line number.
+//
+// Returns:
+//  the current material.
+//  an error.
+//
+func readMtlLine(data *objData, materials map[string]*Material, current *Material, text string, line int) (*Material, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return current, nil
+	}
+	key := fields[0]
+	if key == "newmtl" {
+		if len(fields) < 2 {
+			return nil, data.parseError(line, "newmtl without a name")
+		}
+		mat := InitMaterial(strings.Join(fields[1:], " "))
+		materials[mat.Name] = mat
+		return mat, nil
+	}
+	if current == nil {
+		return nil, data.parseError(line, "%s before newmtl", key)
+	}
+	var err error
+	switch {
+	case key == "Ka":
+		current.Ka, err = readRGB(data, fields[1:], line)
+	case key == "Kd":
+		current.Kd, err = readRGB(data, fields[1:], line)
+	case key == "Ks":
+		current.Ks, err = readRGB(data, fields[1:], line)
+	case key == "Ke":
+		current.Ke, err = readRGB(data, fields[1:], line)
+	case key == "Ns" || key == "Ni" || key == "d" || key == "Tr":
+		var values []float64
+		values, err = data.readFloats(fields[1:], 1, 1, line)
+		if err != nil {
+			break
+		}
+		switch key {
+		case "Ns":
+			current.Ns = values[0]
+		case "Ni":
+			current.Ni = values[0]
+		case "d":
+			current.D = values[0]
+		case "Tr":
+			current.D = 1 - values[0]
+		}
+	case key == "illum":
+		if len(fields) != 2 {
+			return nil, data.parseError(line, "illum expects 1 value")
+		}
+		current.Illum, err = strconv.Atoi(fields[1])
+		if err != nil {
+			err = data.parseError(line, "invalid illumination model %q", fields[1])
+		}
+	case strings.HasPrefix(key, "map_") || key == "bump" || key == "norm" || key == "disp" || key == "decal" || key == "refl":
+		err = readMap(data, current, key, fields[1:], line)
+	}
+	// other statements (Tf, sharpness, Pr, Pm...) are ignored
+	return current, err
+}
+
+// ReadMtl is a function to read all materials of a .mtl file.
+//
+// Parameters:
+//  mtlPath - path to the .mtl file.
+//
+// Returns:
+//  the materials by name.
+//  an error, a *ParseError when the file is malformed.
+//
+func ReadMtl(mtlPath string) (map[string]*Material, error) {
+	file, err := os.Open(mtlPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data := &objData{path: mtlPath}
+	materials := make(map[string]*Material)
+	var current *Material
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := scanner.Text()
+		if idx := strings.IndexByte(text, '#'); idx >= 0 {
+			text = text[:idx]
+		}
+		current, err = readMtlLine(data, materials, current, text, lineNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Path: mtlPath, Line: lineNumber, Msg: err.Error()}
+	}
+	return materials, nil
+}
@@ -0,0 +1,185 @@
+package obj
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/general"
+)
+
+// writeFile is a function to write a file in a folder.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tempDir is a function to create a temporary folder, removed by the caller.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mtl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// checkFloats is a function to compare two lists of floats.
+func checkFloats(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func TestReadMtl(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "test.mtl", `# materials
+newmtl red plastic
+Ka 0.1 0.1 0.1
+Kd 0.8 0 0
+Ks 0.5
+Ns 32
+Ni 1.5
+Tr 0.25
+illum 1
+map_Kd textures/red.png
+map_Bump -bm 0.5 bump.png
+
+newmtl glass
+d 0.1
+`)
+	materials, err := ReadMtl(path)
+	if err != nil {
+		t.Fatalf("ReadMtl: %v", err)
+	}
+	if len(materials) != 2 {
+		t.Fatalf("got %d materials, want 2", len(materials))
+	}
+	red := materials["red plastic"]
+	checkFloats(t, "Ka", red.Ka, []float64{0.1, 0.1, 0.1})
+	checkFloats(t, "Kd", red.Kd, []float64{0.8, 0, 0})
+	checkFloats(t, "Ks", red.Ks, []float64{0.5, 0.5, 0.5})
+	checkFloats(t, "scalars", []float64{red.Ns, red.Ni, red.D, float64(red.Illum), red.BumpMultiplier}, []float64{32, 1.5, 0.75, 1, 0.5})
+	if red.Maps["map_Kd"] != filepath.Join(dir, "textures", "red.png") || red.Maps["map_Bump"] != filepath.Join(dir, "bump.png") {
+		t.Errorf("maps not relative to the .mtl folder: %v", red.Maps)
+	}
+	glass := materials["glass"]
+	checkFloats(t, "defaults", []float64{glass.Ni, glass.D, float64(glass.Illum)}, []float64{1, 0.1, 2})
+}
+
+func TestReadMtlErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		msg     string
+	}{
+		{"before newmtl", "Kd 1 1 1\n", "before newmtl"},
+		{"two components", "newmtl a\nKd 1 1\n", "1 or 3"},
+		{"bad illum", "newmtl a\nillum x\n", "illumination model"},
+		{"map without file", "newmtl a\nmap_Kd\n", "without a file"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+			path := writeFile(t, dir, "test.mtl", test.content)
+			_, err := ReadMtl(path)
+			if _, ok := err.(*ParseError); !ok || !strings.Contains(err.Error(), test.msg) {
+				t.Errorf("got %v, want a *ParseError with %q", err, test.msg)
+			}
+		})
+	}
+}
+
+func TestMaterialApply(t *testing.T) {
+	tests := []struct {
+		name                        string
+		kd, ks                      []float64
+		d                           float64
+		illum                       int
+		color                       []float64
+		diffuse, specular, transmit float64
+		clamped                     bool
+	}{
+		{"diffuse", []float64{0.5, 0.25, 0}, []float64{0, 0, 0}, 1, 2, []float64{1, 0.5, 0}, 0.5, 0, 0, false},
+		{"dissolved", []float64{0.5, 0.25, 0}, []float64{0.2, 0.2, 0.2}, 0.5, 2, []float64{1, 0.5, 0}, 0.25, 0.1, 0.5, false},
+		{"no highlights", []float64{0.5, 0.5, 0.5}, []float64{1, 1, 1}, 1, 1, []float64{1, 1, 1}, 0.5, 0, 0, false},
+		// 1 + 1 is scaled down to 1
+		{"energy clamp", []float64{1, 1, 1}, []float64{1, 1, 1}, 1, 2, []float64{1, 1, 1}, 0.5, 0.5, 0, true},
+		{"clamp keeps the transmission", []float64{1, 1, 1}, []float64{1, 1, 1}, 0.5, 2, []float64{1, 1, 1}, 0.25, 0.25, 0.5, true},
+		{"black", []float64{0, 0, 0}, []float64{0, 0, 0}, 1, 2, []float64{0, 0, 0}, 0, 0, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mat := InitMaterial(test.name)
+			mat.Kd, mat.Ks, mat.D, mat.Illum = test.kd, test.ks, test.d, test.illum
+			mat.Ns = 98
+			obj := general.Object{}
+			if err := mat.Apply(&obj); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			checkFloats(t, "Color", obj.Color, test.color)
+			checkFloats(t, "coefficients", []float64{obj.DiffuseReflection, obj.SpecularReflection, obj.TransReflection}, []float64{test.diffuse, test.specular, test.transmit})
+			checkFloats(t, "Ns", []float64{obj.SpecularDecay, obj.RoughNess}, []float64{98, math.Sqrt(0.02)})
+			if !test.clamped {
+				// the diffuse albedo is Kd * d, Kd is not applied twice
+				albedo := make([]float64, 3)
+				for i := range albedo {
+					albedo[i] = obj.Color[i] * obj.DiffuseReflection
+				}
+				want := make([]float64, 3)
+				for i := range want {
+					want[i] = test.kd[i] * test.d
+				}
+				checkFloats(t, "albedo", albedo, want)
+			}
+		})
+	}
+}
+
+func TestMaterialApplyTextureOnBlackKd(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	file, err := os.Create(filepath.Join(dir, "red.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	mat := InitMaterial("textured")
+	mat.D = 0.5
+	mat.Maps["map_Kd"] = filepath.Join(dir, "red.png")
+	obj := general.Object{}
+	if err := mat.Apply(&obj); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if obj.Texture == nil || !obj.Texture.Loaded() {
+		t.Fatalf("texture not loaded")
+	}
+	checkFloats(t, "Color", obj.Color, []float64{1, 1, 1})
+	checkFloats(t, "DiffuseReflection", []float64{obj.DiffuseReflection}, []float64{0.5})
+}
@@ -2,7 +2,8 @@ package obj
 
 import (
 	"bufio"
-	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -13,137 +14,514 @@ import (
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
-// readTrianle is a function to read a face of an .obj file.
+// ParseError is a class for errors found while reading an .obj file.
+//
+// Members:
+//  Path - path to the file.
+//  Line - the line number where the error was found.
+//  Msg  - description of the error.
+//
+type ParseError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+// Error is a function to format the ParseError.
 //
 // Parameters:
-// 	line   - a single .obj line.
+//  none
 //
 // Returns:
-//  the triangle as json bytes.
+//  the error message.
 //
-func readTriangle(line string) *[]byte {
-	inp := strings.Split(line[2:], " ")
-	vertices := make([]int, 3)
-	normals := make([]int, 3)
-	for i, c := range inp {
-		splitedIndices := strings.Split(c, "/")
-		var err error
-		vertices[i], err = strconv.Atoi(splitedIndices[0])
-		utils.ShowError(err, "Unable to convert to vertex index to int.")
-		normals[i], err = strconv.Atoi(splitedIndices[len(splitedIndices)-1])
-		utils.ShowError(err, "Unable to convert to normal index to int.")
-		vertices[i] = vertices[i] - 1
-		normals[i] = normals[i] - 1
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", err.Path, err.Line, err.Msg)
+}
+
+// faceVertex is a class for a single corner of a face.
+//
+// Members:
+//  vertex   - index of the position.
+//  texture  - index of the texture coordinate (-1 if absent).
+//  normal   - index of the normal (-1 if absent).
+//
+type faceVertex struct {
+	vertex  int
+	texture int
+	normal  int
+}
+
+// face is a class for a polygon read from an .obj file.
+//
+// Members:
+//  corners - the corners of the polygon.
+//  line    - the line number of the face.
+//
+type face struct {
+	corners []faceVertex
+	line    int
+}
+
+// objData is a class for the raw data of an .obj file.
+//
+// Members:
+//  path      - path to the file.
+//  points    - list of vertex positions.
+//  normals   - list of vertex normals.
+//  texCoords - list of texture coordinates.
+//  faces     - list of faces.
+//  sections  - the faces split by object, group and material.
+//  mtllibs   - paths to the material libraries.
+//  object    - the current object name.
+//  group     - the current group name.
+//  material  - the current material name.
+//
+type objData struct {
+	path      string
+	points    []entity.Point
+	normals   []utils.Vector
+	texCoords []utils.Vector
+	faces     []face
+	sections  []section
+	mtllibs   []string
+	object    string
+	group     string
+	material  string
+}
+
+// section is a class for a range of faces sharing the same object, group and material.
+//
+// Members:
+//  object   - the name given by the last o statement.
+//  group    - the name given by the last g statement.
+//  material - the name given by the last usemtl statement.
+//  start    - index of the first face.
+//  end      - index after the last face.
+//
+type section struct {
+	object   string
+	group    string
+	material string
+	start    int
+	end      int
+}
+
+// displayName is a function to get the name of the Object created from a section.
+//
+// Parameters:
+//  fileName - name of the .obj file, used when the section has no object nor group.
+//
+// Returns:
+//  the name.
+//
+func (sec *section) displayName(fileName string) string {
+	parts := make([]string, 0, 3)
+	if sec.object != "" {
+		parts = append(parts, sec.object)
+	}
+	if sec.group != "" && sec.group != sec.object {
+		parts = append(parts, sec.group)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fileName)
 	}
-	triangle := entity.InitTriangle(vertices, normals)
-	triangleAsBytes, err := json.Marshal(triangle)
-	utils.ShowError(err, "Unable to marshal triangle.")
-	return &triangleAsBytes
+	if sec.material != "" {
+		parts = append(parts, sec.material)
+	}
+	return strings.Join(parts, "_")
 }
 
-// readNormal is a function to read a vertex normal of an .obj file.
+// parseError is a function to create a ParseError for the current file.
 //
 // Parameters:
-// 	line   - a single .obj line.
+//  line   - the line number.
+//  format - the message format.
+//  args   - the message arguments.
 //
 // Returns:
-//  the vector as json bytes.
+//  the error.
 //
-func readNormal(line string) *[]byte {
-	inp := strings.Split(line[3:], " ")
-	vect := utils.InitVector(3)
-	for i, c := range inp {
+func (data *objData) parseError(line int, format string, args ...interface{}) error {
+	return &ParseError{Path: data.path, Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// readFloats is a function to read the float values of a statement.
+//
+// Parameters:
+//  fields - the values of the statement.
+//  min    - minimum number of values.
+//  max    - maximum number of values.
+//  line   - the line number.
+//
+// Returns:
+//  the values.
+//  an error.
+//
+func (data *objData) readFloats(fields []string, min, max, line int) ([]float64, error) {
+	if len(fields) < min || len(fields) > max {
+		return nil, data.parseError(line, "expected between %d and %d values, got %d", min, max, len(fields))
+	}
+	values := make([]float64, len(fields))
+	for i, c := range fields {
 		var err error
-		vect.Coordinates[i], err = strconv.ParseFloat(c, 64)
-		utils.ShowError(err, "Unable to convert coordinate to float.")
+		values[i], err = strconv.ParseFloat(c, 64)
+		if err != nil {
+			return nil, data.parseError(line, "invalid number %q", c)
+		}
 	}
-	vectAsBytes, err := json.Marshal(vect)
-	utils.ShowError(err, "Unable to marshal vector.")
-	return &vectAsBytes
+	return values, nil
 }
 
 // readPoint is a function to read a vertex of an .obj file.
 //
 // Parameters:
-// 	line   - a single .obj line.
+//  fields - the values of the statement.
+//  line   - the line number.
+//
+// Returns:
+//  an error.
+//
+func (data *objData) readPoint(fields []string, line int) error {
+	// the optional w coordinate is ignored
+	values, err := data.readFloats(fields, 3, 4, line)
+	if err != nil {
+		return err
+	}
+	data.points = append(data.points, entity.Point{Coordinates: values[:3]})
+	return nil
+}
+
+// readNormal is a function to read a vertex normal of an .obj file.
+//
+// Parameters:
+//  fields - the values of the statement.
+//  line   - the line number.
+//
+// Returns:
+//  an error.
+//
+func (data *objData) readNormal(fields []string, line int) error {
+	values, err := data.readFloats(fields, 3, 3, line)
+	if err != nil {
+		return err
+	}
+	data.normals = append(data.normals, utils.Vector{Coordinates: values})
+	return nil
+}
+
+// readTexCoord is a function to read a texture coordinate of an .obj file.
+//
+// Parameters:
+//  fields - the values of the statement.
+//  line   - the line number.
 //
 // Returns:
-//  the vertex as a point in json bytes.
+//  an error.
+//
+func (data *objData) readTexCoord(fields []string, line int) error {
+	values, err := data.readFloats(fields, 1, 3, line)
+	if err != nil {
+		return err
+	}
+	// only u and v are kept, v defaults to 0
+	uv, _ := utils.InitVector(2) // a valid size
+	copy(uv.Coordinates, values)
+	data.texCoords = append(data.texCoords, uv)
+	return nil
+}
+
+// resolveIndex is a function to convert an .obj index (1-based or negative relative) to a 0-based index.
+//
+// Parameters:
+//  value - the index as written on the file.
+//  count - number of elements read so far.
+//  kind  - name of the element for error messages.
+//  line  - the line number.
 //
-func readPoint(line string) *[]byte {
-	inp := strings.Split(line[2:], " ")
-	pt := entity.InitPoint(3)
-	for i, c := range inp {
+// Returns:
+//  the 0-based index.
+//  an error.
+//
+func (data *objData) resolveIndex(value string, count int, kind string, line int) (int, error) {
+	idx, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, data.parseError(line, "invalid %s index %q", kind, value)
+	}
+	if idx > 0 {
+		idx--
+	} else if idx < 0 {
+		idx = count + idx
+	} else {
+		return 0, data.parseError(line, "%s index can not be 0", kind)
+	}
+	if idx < 0 || idx >= count {
+		return 0, data.parseError(line, "%s index %s out of range (%d defined)", kind, value, count)
+	}
+	return idx, nil
+}
+
+// readFace is a function to read a face of an .obj file.
+// Accepts the v, v/vt, v//vn and v/vt/vn forms.
+//
+// Parameters:
+//  fields - the corners of the face.
+//  line   - the line number.
+//
+// Returns:
+//  an error.
+//
+func (data *objData) readFace(fields []string, line int) error {
+	if len(fields) < 3 {
+		return data.parseError(line, "face with %d vertices, at least 3 are needed", len(fields))
+	}
+	corners := make([]faceVertex, len(fields))
+	for i, c := range fields {
+		splitedIndices := strings.Split(c, "/")
+		if len(splitedIndices) > 3 {
+			return data.parseError(line, "invalid face vertex %q", c)
+		}
+		corner := faceVertex{texture: -1, normal: -1}
 		var err error
-		pt.Coordinates[i], err = strconv.ParseFloat(c, 64)
-		utils.ShowError(err, "Unable to convert coordinate to float Obj.")
+		corner.vertex, err = data.resolveIndex(splitedIndices[0], len(data.points), "vertex", line)
+		if err != nil {
+			return err
+		}
+		if len(splitedIndices) > 1 && splitedIndices[1] != "" {
+			corner.texture, err = data.resolveIndex(splitedIndices[1], len(data.texCoords), "texture", line)
+			if err != nil {
+				return err
+			}
+		}
+		if len(splitedIndices) > 2 && splitedIndices[2] != "" {
+			corner.normal, err = data.resolveIndex(splitedIndices[2], len(data.normals), "normal", line)
+			if err != nil {
+				return err
+			}
+		}
+		corners[i] = corner
+	}
+	last := len(data.sections) - 1
+	if last < 0 || data.sections[last].object != data.object || data.sections[last].group != data.group || data.sections[last].material != data.material {
+		data.sections = append(data.sections, section{object: data.object, group: data.group, material: data.material, start: len(data.faces)})
+		last++
 	}
-	ptAsBytes, err := json.Marshal(pt)
-	utils.ShowError(err, "Unable to marshal point.")
-	return &ptAsBytes
+	data.faces = append(data.faces, face{corners: corners, line: line})
+	data.sections[last].end = len(data.faces)
+	return nil
 }
 
-// readLine is a function to read a line of an .obj file.
+// readLine is a function to read a logical line of an .obj file.
 //
 // Parameters:
-// 	line   - a single .obj line.
+//  text - the line without comments.
+//  line - the line number.
 //
 // Returns:
-//  hash   - a int that identifies the type of the return.
-//  result - a Triangle, Vertex or Vector.
+//  an error.
 //
-func readLine(line string) (int, *[]byte) {
-	switch line[0] {
-	case 'v':
-		if line[1] == 'n' {
-			return 2, readNormal(line)
-		} else if line[1] == 't' {
-			return 3, nil
+func (data *objData) readLine(text string, line int) error {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil
+	}
+	switch fields[0] {
+	case "v":
+		return data.readPoint(fields[1:], line)
+	case "vn":
+		return data.readNormal(fields[1:], line)
+	case "vt":
+		return data.readTexCoord(fields[1:], line)
+	case "f":
+		return data.readFace(fields[1:], line)
+	case "o":
+		data.object = strings.Join(fields[1:], " ")
+	case "g":
+		data.group = strings.Join(fields[1:], " ")
+	case "usemtl":
+		data.material = strings.Join(fields[1:], " ")
+	case "mtllib":
+		// libraries are relative to the .obj folder
+		for _, lib := range fields[1:] {
+			if !filepath.IsAbs(lib) {
+				lib = filepath.Join(filepath.Dir(data.path), lib)
+			}
+			data.mtllibs = append(data.mtllibs, lib)
 		}
-		return 0, readPoint(line) // vertex case
-	case 'f':
-		return 1, readTriangle(line)
 	}
-	return -1, nil
+	// unsupported statements (s, l, p...) are ignored
+	return nil
 }
 
 // readLines is a function to read all lines of an .obj file.
 //
 // Parameters:
-// 	scanner   - pointer to a Scanner with an .obj file.
+//  reader - the .obj content.
+//  path   - path to the file, used on error messages.
 //
 // Returns:
-//  vertices  - pointer to Vertices object with all vertices.
-//  triangles - pointer to list of triangles.
-//  normals   - pointer to list of normal vectors.
+//  the raw data of the file.
+//  an error.
 //
-func readLines(scanner *bufio.Scanner) (entity.Vertices, []entity.Triangle, []utils.Vector) {
-	var pointList []entity.Point
-	var triangles []entity.Triangle
-	var normals []utils.Vector
+func readLines(reader io.Reader, path string) (*objData, error) {
+	data := &objData{path: path}
+	scanner := bufio.NewScanner(reader)
+	logical := ""
+	start := 0
+	lineNumber := 0
 	for scanner.Scan() {
-		hash, result := readLine(scanner.Text())
-		switch hash {
-		case 0:
-			var pt entity.Point
-			err := json.Unmarshal(*result, &pt)
-			utils.ShowError(err, "Unable to Unmarshal Point.")
-			pointList = append(pointList, pt)
-			break
-		case 1:
-			var tri entity.Triangle
-			err := json.Unmarshal(*result, &tri)
-			utils.ShowError(err, "Unable to Unmarshal Triangle.")
-			triangles = append(triangles, tri)
-		case 2:
-			var vect utils.Vector
-			err := json.Unmarshal(*result, &vect)
-			utils.ShowError(err, "Unable to Unmarshal Vector.")
-			normals = append(normals, vect)
+		lineNumber++
+		text := scanner.Text()
+		if idx := strings.IndexByte(text, '#'); idx >= 0 {
+			text = text[:idx]
 		}
+		if logical == "" {
+			start = lineNumber
+		}
+		// joining lines ended by a backslash
+		trimmed := strings.TrimRight(text, " \t\r")
+		if strings.HasSuffix(trimmed, "\\") {
+			logical += trimmed[:len(trimmed)-1] + " "
+			continue
+		}
+		logical += text
+		if err := data.readLine(logical, start); err != nil {
+			return nil, err
+		}
+		logical = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Path: path, Line: lineNumber, Msg: err.Error()}
+	}
+	if logical != "" {
+		if err := data.readLine(logical, start); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// remap is a function to get the new index of an element, adding it to the list of used elements.
+//
+// Parameters:
+//  indices - map from the file index to the new index.
+//  used    - list of file indices in the new order.
+//  idx     - the file index (negative values are kept).
+//
+// Returns:
+//  the new index.
+//  the updated list of used indices.
+//
+func remap(indices map[int]int, used []int, idx int) (int, []int) {
+	if idx < 0 {
+		return idx, used
 	}
-	vertices := entity.InitVertices(pointList)
-	return vertices, triangles, normals
+	newIdx, found := indices[idx]
+	if !found {
+		newIdx = len(used)
+		indices[idx] = newIdx
+		used = append(used, idx)
+	}
+	return newIdx, used
+}
+
+// loadMaterials is a function to read all material libraries of the file.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the materials by name.
+//  an error.
+//
+func (data *objData) loadMaterials() (map[string]*Material, error) {
+	materials := make(map[string]*Material)
+	for _, lib := range data.mtllibs {
+		libMaterials, err := ReadMtl(lib)
+		if err != nil {
+			return nil, err
+		}
+		for name, mat := range libMaterials {
+			materials[name] = mat
+		}
+	}
+	return materials, nil
+}
+
+// buildObject is a function to triangulate a list of faces into an Object.
+// Only the vertices, normals and texture coordinates used by the faces are kept, with their indices remapped.
+//
+// Parameters:
+//  name     - the name of the object.
+//  faces    - the faces of the object.
+//  material - the material of the object (may be nil).
+//  options  - the reader options.
+//
+// Returns:
+//  the object.
+//  an error when a texture of the material can not be loaded.
+//
+func (data *objData) buildObject(name string, faces []face, material *Material, options Options) (general.Object, error) {
+	pointIndices := make(map[int]int)
+	normalIndices := make(map[int]int)
+	texCoordIndices := make(map[int]int)
+	var usedPoints, usedNormals, usedTexCoords []int
+
+	triangles := make([]entity.Triangle, 0, len(faces))
+	for _, f := range faces {
+		for _, tri := range triangulate(data.points, f.corners) {
+			vertices := make([]int, 3)
+			normals := make([]int, 3)
+			texCoords := make([]int, 3)
+			for i, c := range tri {
+				vertices[i], usedPoints = remap(pointIndices, usedPoints, f.corners[c].vertex)
+				normals[i], usedNormals = remap(normalIndices, usedNormals, f.corners[c].normal)
+				texCoords[i], usedTexCoords = remap(texCoordIndices, usedTexCoords, f.corners[c].texture)
+			}
+			triangle := entity.InitTriangle(vertices, normals)
+			// texture coordinates are only kept when every corner has one
+			if texCoords[0] >= 0 && texCoords[1] >= 0 && texCoords[2] >= 0 {
+				triangle.TexCoords = texCoords
+			}
+			triangles = append(triangles, triangle)
+		}
+	}
+	points := make([]entity.Point, len(usedPoints))
+	for i, idx := range usedPoints {
+		points[i] = data.points[idx]
+	}
+	normals := make([]utils.Vector, len(usedNormals))
+	for i, idx := range usedNormals {
+		normals[i] = data.normals[idx]
+	}
+	texCoords := make([]utils.Vector, len(usedTexCoords))
+	for i, idx := range usedTexCoords {
+		texCoords[i] = data.texCoords[idx]
+	}
+	vertices := entity.InitVertices(points)
+
+	object := general.Object{
+		Name:          name,
+		Vertices:      vertices,
+		Triangles:     triangles,
+		Normals:       normals,
+		TexCoords:     texCoords,
+		Color:         make([]float64, 3),
+		SpecularDecay: 100.0,
+		NormalMode:    options.NormalMode,
+		CreaseAngle:   options.CreaseAngle,
+	}
+	if err := object.CheckIntegrity(); err != nil {
+		return object, utils.WrapFile("load", data.path, err)
+	}
+	object.EnsureNormals()
+	object.NormalizeNormals()
+	if material != nil {
+		if err := material.Apply(&object); err != nil {
+			return object, err
+		}
+	}
+	return object, nil
 }
 
 // getName is a function to get the name of an .obj file.
@@ -155,51 +533,130 @@ func readLines(scanner *bufio.Scanner) (entity.Vertices, []entity.Triangle, []ut
 //  name - the name of the object.
 //
 func getName(objPath string) string {
-	paths := strings.Split(objPath, "/")
-	nameWithExt := paths[len(paths)-1]
-	nameWESplitted := strings.Split(nameWithExt, ".")
-	name := nameWESplitted[0]
-	return name
+	nameWithExt := filepath.Base(objPath)
+	return strings.TrimSuffix(nameWithExt, filepath.Ext(nameWithExt))
 }
 
-// ReadObj is a function to read a .obj file.
+// readFile is a function to read the raw data of an .obj file.
 //
 // Parameters:
-// 	objPath   - path to the .obj file.
+// 	objPath - path to the .obj file.
 //
 // Returns:
-//  cam       - the cam to the object.
-//  triangles - list of triangles.
-//  vertices  - Vertices object with all points.
-//  normals   - list of normal vectors.
+//  the raw data.
+//  an error.
 //
-func ReadObj(objPath string) *general.Object {
-
+func readFile(objPath string) (*objData, error) {
 	// getting abs path
 	absPath, err := filepath.Abs(objPath)
-	utils.ShowError(err, "Unable to find absolute path for: "+objPath+".")
+	if err != nil {
+		return nil, err
+	}
 
 	// getting the file
 	file, err := os.Open(absPath)
-	utils.ShowError(err, "Unable to find "+absPath+".")
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	vertices, triangles, normals := readLines(scanner)
-	name := getName(objPath)
+	return readLines(file, objPath)
+}
 
-	color := make([]float64, 3)
-	specularDecay := 100.0
-	ambientReflection := 0.0
-	diffuseReflection := 0.0
-	specularReflection := 0.0
-	transReflection := 0.0
-	roughNess := 0.0
-	object := general.InitObject(name, vertices, triangles, normals, color, specularDecay, ambientReflection, diffuseReflection, specularReflection, transReflection, roughNess)
-
-	err = scanner.Err()
-	utils.ShowError(err, "Error on reading file: "+absPath+".")
-	object.NormalizeNormals()
-	object.CheckIntegrity()
-	return &object
+// Options is a class for the options of the .obj reader.
+//
+// Members:
+//  NormalMode  - how normals are generated for the corners without one (general.NormalFlat, NormalSmoothArea or NormalSmoothAngle).
+//  CreaseAngle - max angle in degrees between faces smoothed together (0 means no limit).
+//
+type Options struct {
+	NormalMode  string
+	CreaseAngle float64
+}
+
+// ReadObj is a function to read a .obj file generating flat normals for the corners without one.
+//
+// Parameters:
+// 	objPath - path to the .obj file.
+//
+// Returns:
+//  the object.
+//  an error, a *ParseError when the file is malformed.
+//
+func ReadObj(objPath string) (*general.Object, error) {
+	return ReadObjWithOptions(objPath, Options{NormalMode: general.NormalFlat})
+}
+
+// ReadObjWithOptions is a function to read a .obj file as a single Object.
+// Faces are triangulated, normals are generated for the corners without one and
+// the materials of the mtllib statements are applied (see Material.Apply).
+//
+// Parameters:
+// 	objPath - path to the .obj file.
+//  options - the reader options.
+//
+// Returns:
+//  the object.
+//  an error, a *ParseError when the file is malformed.
+//
+func ReadObjWithOptions(objPath string, options Options) (*general.Object, error) {
+	data, err := readFile(objPath)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := data.loadMaterials()
+	if err != nil {
+		return nil, err
+	}
+	// a single object takes the first material used by the file
+	var material *Material
+	for _, sec := range data.sections {
+		if mat, found := materials[sec.material]; found {
+			material = mat
+			break
+		}
+	}
+	object, err := data.buildObject(getName(objPath), data.faces, material, options)
+	if err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// ReadObjGroups is a function to read a .obj file as one Object for each o, g or usemtl section.
+//
+// Parameters:
+// 	objPath - path to the .obj file.
+//  options - the reader options.
+//
+// Returns:
+//  the objects, labeled with the name of the file.
+//  an error, a *ParseError when the file is malformed.
+//
+func ReadObjGroups(objPath string, options Options) (*general.Objects, error) {
+	data, err := readFile(objPath)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := data.loadMaterials()
+	if err != nil {
+		return nil, err
+	}
+	name := getName(objPath)
+	names := make(map[string]int)
+	objList := make([]general.Object, 0, len(data.sections))
+	for _, sec := range data.sections {
+		sectionName := sec.displayName(name)
+		// keeping names unique
+		names[sectionName]++
+		if names[sectionName] > 1 {
+			sectionName = fmt.Sprintf("%s_%d", sectionName, names[sectionName])
+		}
+		object, err := data.buildObject(sectionName, data.faces[sec.start:sec.end], materials[sec.material], options)
+		if err != nil {
+			return nil, err
+		}
+		objList = append(objList, object)
+	}
+	return general.InitObjects(name, objList), nil
 }
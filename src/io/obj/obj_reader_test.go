@@ -0,0 +1,209 @@
+package obj
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/general"
+)
+
+// parse is a function to read the raw data of an .obj content, failing the test on errors.
+func parse(t *testing.T, content string) *objData {
+	t.Helper()
+	data, err := readLines(strings.NewReader(content), "test.obj")
+	if err != nil {
+		t.Fatalf("readLines: %v", err)
+	}
+	return data
+}
+
+// triangleArea is a function to get the area of a triangle of corners of a face.
+func triangleArea(data *objData, f face, tri [3]int) float64 {
+	a := data.points[f.corners[tri[0]].vertex].Coordinates
+	b := data.points[f.corners[tri[1]].vertex].Coordinates
+	c := data.points[f.corners[tri[2]].vertex].Coordinates
+	u := []float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+	v := []float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+	x := u[1]*v[2] - u[2]*v[1]
+	y := u[2]*v[0] - u[0]*v[2]
+	z := u[0]*v[1] - u[1]*v[0]
+	return math.Sqrt(x*x+y*y+z*z) / 2
+}
+
+func TestReadFaceIndexForms(t *testing.T) {
+	data := parse(t, `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vn 0 0 1
+f 1 2 3
+f 1/1 2/2 3/3
+f 1//1 2//1 3//1
+f 1/1/1 2/2/1 3/3/1
+f -4/-3/-1 -3/-2/-1 -2/-1/-1
+`)
+	want := [][]faceVertex{
+		{{0, -1, -1}, {1, -1, -1}, {2, -1, -1}},
+		{{0, 0, -1}, {1, 1, -1}, {2, 2, -1}},
+		{{0, -1, 0}, {1, -1, 0}, {2, -1, 0}},
+		{{0, 0, 0}, {1, 1, 0}, {2, 2, 0}},
+		{{0, 0, 0}, {1, 1, 0}, {2, 2, 0}},
+	}
+	if len(data.faces) != len(want) {
+		t.Fatalf("got %d faces, want %d", len(data.faces), len(want))
+	}
+	for i, f := range data.faces {
+		for j, c := range f.corners {
+			if c != want[i][j] {
+				t.Errorf("face %d corner %d: got %+v, want %+v", i, j, c, want[i][j])
+			}
+		}
+	}
+}
+
+func TestReadLinesContinuationAndComments(t *testing.T) {
+	data := parse(t, "# a comment\nv 0 0 0 # trailing\nv 1 0 0\nv 0 1 0\r\nf 1 \\\n  2 \\\n  3\n")
+	if len(data.points) != 3 {
+		t.Fatalf("got %d points, want 3", len(data.points))
+	}
+	if len(data.faces) != 1 || len(data.faces[0].corners) != 3 {
+		t.Fatalf("got faces %+v, want a single triangle", data.faces)
+	}
+	if data.faces[0].line != 5 {
+		t.Errorf("face line %d, want 5 (the first line of the continuation)", data.faces[0].line)
+	}
+}
+
+func TestReadFaceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		msg     string
+	}{
+		{"zero index", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2\n", 4, "can not be 0"},
+		{"out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 4\n", 4, "out of range"},
+		{"negative out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf -4 1 2\n", 4, "out of range"},
+		{"missing normal", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1//1 2//1 3//1\n", 4, "normal index"},
+		{"too few corners", "v 0 0 0\nv 1 0 0\nf 1 2\n", 3, "at least 3"},
+		{"bad corner", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1/1/1/1 2 3\n", 4, "invalid face vertex"},
+		{"not a number", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf a 2 3\n", 4, "invalid vertex index"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readLines(strings.NewReader(test.content), "test.obj")
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("got %v, want a *ParseError", err)
+			}
+			if parseErr.Line != test.line || !strings.Contains(parseErr.Msg, test.msg) {
+				t.Errorf("got %v, want line %d with %q", parseErr, test.line, test.msg)
+			}
+		})
+	}
+}
+
+func TestTriangulateNGons(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		area    float64
+	}{
+		{"quad", "v 0 0 0\nv 2 0 0\nv 2 1 0\nv 0 1 0\nf 1 2 3 4\n", 2},
+		{"convex pentagon", "v 0 0 0\nv 2 0 0\nv 3 1 0\nv 1 2 0\nv -1 1 0\nf 1 2 3 4 5\n", 5},
+		// an L whose fan from the first corner would cover the notch
+		{"concave L", "v 0 0 0\nv 2 0 0\nv 2 1 0\nv 1 1 0\nv 1 2 0\nv 0 2 0\nf 3 4 5 6 1 2\n", 3},
+		{"concave L on the x plane", "v 0 0 0\nv 0 2 0\nv 0 2 1\nv 0 1 1\nv 0 1 2\nv 0 0 2\nf 3 4 5 6 1 2\n", 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := parse(t, test.content)
+			f := data.faces[0]
+			triangles := triangulate(data.points, f.corners)
+			if len(triangles) != len(f.corners)-2 {
+				t.Fatalf("got %d triangles, want %d", len(triangles), len(f.corners)-2)
+			}
+			area := 0.0
+			for _, tri := range triangles {
+				area += triangleArea(data, f, tri)
+			}
+			if math.Abs(area-test.area) > 1e-9 {
+				t.Errorf("triangles cover %g, want the polygon area %g", area, test.area)
+			}
+		})
+	}
+}
+
+func TestBuildObjectRemapsUsedIndices(t *testing.T) {
+	data := parse(t, `
+v 9 9 9
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+f 2/1 3/2 4/3 5/4
+`)
+	object, err := data.buildObject("quad", data.faces, nil, Options{NormalMode: general.NormalFlat})
+	if err != nil {
+		t.Fatalf("buildObject: %v", err)
+	}
+	if len(object.Vertices.Points) != 4 {
+		t.Errorf("got %d vertices, want the 4 used ones", len(object.Vertices.Points))
+	}
+	if len(object.Triangles) != 2 {
+		t.Fatalf("got %d triangles, want 2", len(object.Triangles))
+	}
+	for i, triangle := range object.Triangles {
+		if len(triangle.TexCoords) != 3 {
+			t.Errorf("triangle %d lost its texture coordinates", i)
+		}
+		for _, idx := range triangle.Vertices {
+			if object.Vertices.Points[idx].Coordinates[0] == 9 {
+				t.Errorf("triangle %d uses the unreferenced vertex", i)
+			}
+		}
+	}
+}
+
+func TestBuildObjectKeepsGivenNormals(t *testing.T) {
+	data := parse(t, `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vn 0 1 0
+f 1//1 2//1 3//1
+f 1 3 4
+`)
+	object, err := data.buildObject("mixed", data.faces, nil, Options{NormalMode: general.NormalFlat})
+	if err != nil {
+		t.Fatalf("buildObject: %v", err)
+	}
+	if !object.HasValidNormals() {
+		t.Fatalf("normals not generated: %+v", object.Triangles)
+	}
+	tests := []struct {
+		triangle int
+		want     []float64
+	}{{0, []float64{0, 1, 0}}, {1, []float64{0, 0, 1}}}
+	for _, test := range tests {
+		for c, idx := range object.Triangles[test.triangle].Normals {
+			got := object.Normals[idx].Coordinates
+			for i := range got {
+				if math.Abs(got[i]-test.want[i]) > 1e-9 {
+					t.Errorf("triangle %d corner %d: got normal %v, want %v", test.triangle, c, got, test.want)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package obj
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+)
+
+// fanTriangulate is a function to triangulate a convex polygon as a fan.
+//
+// Parameters:
+//  indices - indices of the polygon corners.
+//
+// Returns:
+//  the triangles as triples of corner indices.
+//
+func fanTriangulate(indices []int) [][3]int {
+	triangles := make([][3]int, 0, len(indices)-2)
+	for i := 1; i+1 < len(indices); i++ {
+		triangles = append(triangles, [3]int{indices[0], indices[i], indices[i+1]})
+	}
+	return triangles
+}
+
+// cross2D is a function to get the z of the cross product of (b - a) and (c - a).
+//
+// Parameters:
+//  ax, ay - the first point.
+//  bx, by - the second point.
+//  cx, cy - the third point.
+//
+// Returns:
+//  the signed double area of the triangle.
+//
+func cross2D(ax, ay, bx, by, cx, cy float64) float64 {
+	return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+}
+
+// triangulate is a function to triangulate a polygon by ear clipping.
+// The polygon is projected on the plane of its dominant axis, falling back to a fan when no ear is found.
+//
+// Parameters:
+//  points  - the positions of the file.
+//  corners - the corners of the polygon.
+//
+// Returns:
+//  the triangles as triples of corner indices.
+//
+func triangulate(points []entity.Point, corners []faceVertex) [][3]int {
+	n := len(corners)
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	if n == 3 {
+		return fanTriangulate(indices)
+	}
+
+	// polygon normal by Newell's method
+	normal := make([]float64, 3)
+	for i := 0; i < n; i++ {
+		a := points[corners[i].vertex].Coordinates
+		b := points[corners[(i+1)%n].vertex].Coordinates
+		normal[0] += (a[1] - b[1]) * (a[2] + b[2])
+		normal[1] += (a[2] - b[2]) * (a[0] + b[0])
+		normal[2] += (a[0] - b[0]) * (a[1] + b[1])
+	}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(normal[i]) > math.Abs(normal[axis]) {
+			axis = i
+		}
+	}
+	if normal[axis] == 0 {
+		return fanTriangulate(indices)
+	}
+	orientation := 1.0
+	if normal[axis] < 0 {
+		orientation = -1
+	}
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		coords := points[corners[i].vertex].Coordinates
+		xs[i] = coords[(axis+1)%3]
+		ys[i] = coords[(axis+2)%3]
+	}
+
+	triangles := make([][3]int, 0, n-2)
+	remaining := indices
+	for len(remaining) > 3 {
+		found := false
+		for i := range remaining {
+			prev := remaining[(i+len(remaining)-1)%len(remaining)]
+			curr := remaining[i]
+			next := remaining[(i+1)%len(remaining)]
+			if orientation*cross2D(xs[prev], ys[prev], xs[curr], ys[curr], xs[next], ys[next]) <= 0 {
+				continue // reflex or degenerate corner
+			}
+			ear := true
+			for _, other := range remaining {
+				if other == prev || other == curr || other == next {
+					continue
+				}
+				d1 := orientation * cross2D(xs[prev], ys[prev], xs[curr], ys[curr], xs[other], ys[other])
+				d2 := orientation * cross2D(xs[curr], ys[curr], xs[next], ys[next], xs[other], ys[other])
+				d3 := orientation * cross2D(xs[next], ys[next], xs[prev], ys[prev], xs[other], ys[other])
+				if d1 >= 0 && d2 >= 0 && d3 >= 0 {
+					ear = false
+					break
+				}
+			}
+			if ear {
+				triangles = append(triangles, [3]int{prev, curr, next})
+				remaining = append(remaining[:i:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return append(triangles, fanTriangulate(remaining)...)
+		}
+	}
+	return append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+}
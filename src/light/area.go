@@ -0,0 +1,251 @@
+package light
+
+import (
+	"errors"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// AreaLight is a class for lights emitting from both sides of the surface of an object.
+//
+// Members:
+//  Emitter     - the color and the intensities, the emitted radiance is Color * LightIntensity.
+//  LightObject - Object for the light.
+//  shape       - the shape of the object, built by Prepare.
+//  area        - the area of the shape.
+//
+type AreaLight struct {
+	Emitter
+	LightObject general.Object
+	shape       shape.Shape
+	area        float64
+}
+
+// InitAreaLight is a function to initialize an AreaLight.
+//
+// Parameters:
+//  ambientIntensity - the ambient intensity of the light.
+//  lightIntensity   - the instensity of the light.
+//  object           - the object tha defines the light.
+//  color            - the rgb of the light.
+//
+// Returns:
+// 	the AreaLight, not prepared.
+//
+func InitAreaLight(ambientIntensity, lightIntensity float64, object general.Object, color []float64) *AreaLight {
+	return &AreaLight{
+		Emitter:     Emitter{Type: TypeArea, AmbientIntensity: ambientIntensity, LightIntensity: lightIntensity, Color: color},
+		LightObject: object,
+	}
+}
+
+// InitEmissiveLight is a function to initialize the AreaLight of an emissive object, sharing the shape
+// rendered with the other objects. The light is prepared and must not be prepared again.
+//
+// Parameters:
+//  shp - the shape of the object.
+//  obj - the object, its emission is the radiance of the light.
+//
+// Returns:
+// 	the AreaLight.
+//  false if the object does not emit or its area is not finite, so it can not be sampled.
+//
+func InitEmissiveLight(shp shape.Shape, obj *general.Object) (*AreaLight, bool) {
+	emission := obj.GetEmission()
+	area := shp.Area()
+	if emission == nil || !(area > 0) || math.IsInf(area, 0) {
+		return nil, false
+	}
+	return &AreaLight{
+		Emitter:     Emitter{Type: TypeArea, LightIntensity: 1, Color: emission},
+		LightObject: *obj,
+		shape:       shp,
+		area:        area,
+	}, true
+}
+
+// InitEmissiveLights is a function to initialize the AreaLights of the emissive objects of a list of shapes.
+//
+// Parameters:
+//  objs   - the objects.
+//  shapes - the shapes built by shape.FromObjects, the objects followed by the instances.
+//
+// Returns:
+//  the lights, in the order of the shapes.
+//  the lights by their shape.
+//
+func InitEmissiveLights(objs *general.Objects, shapes shape.List) ([]Light, map[shape.Shape]Light) {
+	lights := make([]Light, 0)
+	emitters := make(map[shape.Shape]Light)
+	for i, shp := range shapes.Shapes {
+		var obj *general.Object
+		if i < len(objs.ObjList) {
+			obj = &objs.ObjList[i]
+		} else if inst, ok := shp.(*shape.Instance); ok {
+			obj = inst.Object
+		} else {
+			continue
+		}
+		if lgt, ok := InitEmissiveLight(shp, obj); ok {
+			lights = append(lights, lgt)
+			emitters[shp] = lgt
+		}
+	}
+	return lights, emitters
+}
+
+// Prepare is a function to check the light and build the shape of its object.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color or the object is invalid, or the object has no area.
+//
+func (lgt *AreaLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	// lights are not shaded, their objects only need the geometry
+	if len(lgt.LightObject.Color) == 0 {
+		lgt.LightObject.Color = []float64{0, 0, 0}
+	}
+	if err := lgt.LightObject.CheckIntegrity(); err != nil {
+		return utils.WrapField("LightObject", err)
+	}
+	lgt.LightObject.EnsureNormals()
+	lgt.shape = shape.FromObject(&lgt.LightObject)
+	lgt.area = lgt.shape.Area()
+	if !(lgt.area > 0) || math.IsInf(lgt.area, 0) {
+		return utils.WrapField("LightObject", errors.New("a light must have a finite area greater than 0"))
+	}
+	return nil
+}
+
+// Sample is a function to choose a point uniformly distributed over the surface of the light.
+//
+// Parameters:
+//  point - the shaded point.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the sample.
+//  false if the sampled point is seen edge on.
+//
+func (lgt *AreaLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	pos, normal := lgt.shape.Sample(u1, u2)
+	toLight := pos.Sub(point)
+	dist := toLight.Length()
+	if dist == 0 {
+		return Sample{}, false
+	}
+	dir := toLight.Scale(1 / dist)
+	cosLight := math.Abs(normal.Dot(dir))
+	if cosLight == 0 {
+		return Sample{}, false
+	}
+	return Sample{Dir: dir, Dist: dist, Radiance: lgt.emission(), PDF: dist * dist / (cosLight * lgt.area)}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point.
+//  dir   - the direction.
+//
+// Returns:
+//  the solid angle density, 0 if the direction misses the light.
+//
+func (lgt *AreaLight) PDF(point, dir utils.Vec3) float64 {
+	dir = dir.Normalize()
+	hit, found := lgt.shape.Intersect(entity.Ray{Origin: point, Direction: dir}, 0, math.MaxFloat64)
+	if !found {
+		return 0
+	}
+	cosLight := math.Abs(lgt.shape.Normal(hit).Dot(dir))
+	if cosLight == 0 {
+		return 0
+	}
+	return hit.T * hit.T / (cosLight * lgt.area)
+}
+
+// Intersect is a function to intersect a ray with the object of the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  the t of the intersection.
+//  true if the ray hits the light.
+//
+func (lgt *AreaLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	hit, found := lgt.shape.Intersect(ray, tMin, tMax)
+	return hit.T, found
+}
+
+// Radiance is a function to get the radiance emitted by the light.
+//
+// Parameters:
+//  dir - the direction of the ray, ignored since both sides emit the same radiance.
+//
+// Returns:
+//  Color * LightIntensity.
+//
+func (lgt *AreaLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	return lgt.emission()
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *AreaLight) IsDelta() bool {
+	return false
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *AreaLight) IsInfinite() bool {
+	return false
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene, ignored.
+//
+// Returns:
+//  the radiance times the area of both sides times π.
+//
+func (lgt *AreaLight) Power(sceneRadius float64) float64 {
+	return 2 * math.Pi * lgt.area * lgt.emissionLuminance()
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the bounds of the shape.
+//
+func (lgt *AreaLight) Bounds() []float64 {
+	return lgt.shape.Bounds()
+}
@@ -0,0 +1,81 @@
+package light
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// quadObject is a function to create a tilted quadrilateral of two triangles of different areas, with generated normals.
+func quadObject() general.Object {
+	points := []entity.Point{
+		{Coordinates: []float64{-1, 2, -0.5}},
+		{Coordinates: []float64{1, 2.5, -0.5}},
+		{Coordinates: []float64{1, 2.5, 1}},
+		{Coordinates: []float64{-0.5, 2.125, 1}},
+	}
+	return general.Object{
+		Vertices: entity.InitVertices(points),
+		Triangles: []entity.Triangle{
+			entity.InitTriangle([]int{0, 1, 2}, []int{-1, -1, -1}),
+			entity.InitTriangle([]int{0, 2, 3}, []int{-1, -1, -1}),
+		},
+	}
+}
+
+// checkAreaLight is a function to check the density of an area light seen from a point under it.
+func checkAreaLight(t *testing.T, lgt *AreaLight) {
+	t.Helper()
+	point := utils.Vec3{0.2, 0, 0.1}
+
+	estimate := checkSamplePDF(t, lgt, point, 64)
+	if total := integrateSphere(800, func(dir utils.Vec3) float64 { return lgt.PDF(point, dir) }); !near(total, 1, 0.01) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	// the samples are on the object and estimate the luminance it sends to the point
+	sample, _ := lgt.Sample(point, 0.7, 0.4)
+	if tHit, hit := lgt.Intersect(entity.Ray{Origin: point, Direction: sample.Dir}, 0, math.MaxFloat64); !hit || !near(tHit, sample.Dist, 1e-6) {
+		t.Errorf("sample %+v: hit %v at %g", sample, hit, tHit)
+	}
+	want := integrateSphere(800, func(dir utils.Vec3) float64 {
+		if _, hit := lgt.Intersect(entity.Ray{Origin: point, Direction: dir}, 0, math.MaxFloat64); !hit {
+			return 0
+		}
+		radiance := lgt.Radiance(dir)
+		return luminance(radiance[:])
+	})
+	if !near(estimate, want, 0.01) {
+		t.Errorf("the samples estimate %g, want %g", estimate, want)
+	}
+}
+
+func TestAreaLightPDF(t *testing.T) {
+	lgt := InitAreaLight(0, 3, quadObject(), []float64{1, 0.5, 0.25})
+	prepare(t, lgt)
+	checkAreaLight(t, lgt)
+	if pdf := lgt.PDF(utils.Vec3{0.2, 0, 0.1}, utils.Vec3{0, -1, 0}); pdf != 0 {
+		t.Errorf("PDF away from the light gives %g, want 0", pdf)
+	}
+}
+
+func TestEmissiveLightPDF(t *testing.T) {
+	obj := quadObject()
+	obj.Color = []float64{1, 1, 1}
+	obj.Emission = []float64{1, 0.5, 0.25}
+	obj.EmissionStrength = 3
+	obj.EnsureNormals()
+	lgt, ok := InitEmissiveLight(shape.FromObject(&obj), &obj)
+	if !ok {
+		t.Fatal("the emissive object gives no light")
+	}
+	checkAreaLight(t, lgt)
+
+	obj.Emission = nil
+	if _, ok := InitEmissiveLight(shape.FromObject(&obj), &obj); ok {
+		t.Error("an object without emission gives a light")
+	}
+}
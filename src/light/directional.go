@@ -0,0 +1,193 @@
+package light
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// DirectionalLight is a class for lights far away from the scene, like the sun.
+//
+// Members:
+//  Emitter         - the color and the intensities, Color * LightIntensity is the irradiance of a surface facing the light.
+//  Direction       - the direction the light travels, from the light to the scene.
+//  AngularDiameter - apparent diameter of the light in degrees, 0 for parallel rays and sharp shadows.
+//  toLight         - the normalized direction from the scene to the light.
+//  cosMax          - cosine of half the AngularDiameter.
+//
+type DirectionalLight struct {
+	Emitter
+	Direction       []float64
+	AngularDiameter float64
+	toLight         utils.Vec3
+	cosMax          float64
+}
+
+// InitDirectionalLight is a function to initialize a DirectionalLight.
+//
+// Parameters:
+//  lightIntensity  - the irradiance of a surface facing the light.
+//  color           - the rgb of the light.
+//  direction       - the direction the light travels.
+//  angularDiameter - apparent diameter of the light in degrees.
+//
+// Returns:
+// 	the DirectionalLight, not prepared.
+//
+func InitDirectionalLight(lightIntensity float64, color, direction []float64, angularDiameter float64) *DirectionalLight {
+	return &DirectionalLight{
+		Emitter:         Emitter{Type: TypeDirectional, LightIntensity: lightIntensity, Color: color},
+		Direction:       direction,
+		AngularDiameter: angularDiameter,
+	}
+}
+
+// Prepare is a function to check the light and compute the cone of its disk.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color, the direction or the diameter is invalid.
+//
+func (lgt *DirectionalLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	direction, err := checkDirection("Direction", lgt.Direction)
+	if err != nil {
+		return err
+	}
+	if !(lgt.AngularDiameter >= 0 && lgt.AngularDiameter < 180) {
+		return utils.WrapField("AngularDiameter", fmt.Errorf("%g outside of [0, 180) degrees", lgt.AngularDiameter))
+	}
+	lgt.toLight = direction.Neg()
+	lgt.cosMax = math.Cos(lgt.AngularDiameter * math.Pi / 360)
+	return nil
+}
+
+// diskRadiance is a function to get the radiance of the disk of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the irradiance divided by the solid angle of the disk.
+//
+func (lgt *DirectionalLight) diskRadiance() utils.Vec3 {
+	return lgt.emission().Scale(conePDF(lgt.cosMax))
+}
+
+// Sample is a function to choose the direction of the light, or uniformly inside its disk.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the sample, at an infinite distance.
+//  true.
+//
+func (lgt *DirectionalLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	if lgt.IsDelta() {
+		return Sample{Dir: lgt.toLight, Dist: math.Inf(1), Radiance: lgt.emission(), PDF: 1}, true
+	}
+	dir := sampleCone(lgt.toLight, lgt.cosMax, u1, u2)
+	return Sample{Dir: dir, Dist: math.Inf(1), Radiance: lgt.diskRadiance(), PDF: conePDF(lgt.cosMax)}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  dir   - the direction.
+//
+// Returns:
+//  the solid angle density, 0 for parallel rays or if the direction misses the disk.
+//
+func (lgt *DirectionalLight) PDF(point, dir utils.Vec3) float64 {
+	if lgt.IsDelta() || dir.Normalize().Dot(lgt.toLight) < lgt.cosMax {
+		return 0
+	}
+	return conePDF(lgt.cosMax)
+}
+
+// Intersect is a function to intersect a ray with the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  0 and false, the light is only reached by escaping rays.
+//
+func (lgt *DirectionalLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	return 0, false
+}
+
+// Radiance is a function to get the radiance of a ray escaping the scene.
+//
+// Parameters:
+//  dir - the direction of the ray.
+//
+// Returns:
+//  the radiance of the disk if the direction is inside it, black otherwise or for parallel rays.
+//
+func (lgt *DirectionalLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	if lgt.IsDelta() || dir.Normalize().Dot(lgt.toLight) < lgt.cosMax {
+		return utils.Vec3{}
+	}
+	return lgt.diskRadiance()
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true without an angular diameter.
+//
+func (lgt *DirectionalLight) IsDelta() bool {
+	return lgt.AngularDiameter == 0
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true.
+//
+func (lgt *DirectionalLight) IsInfinite() bool {
+	return true
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene.
+//
+// Returns:
+//  the irradiance times the area of a disk of the scene.
+//
+func (lgt *DirectionalLight) Power(sceneRadius float64) float64 {
+	return math.Pi * sceneRadius * sceneRadius * lgt.emissionLuminance()
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the infinite bounds.
+//
+func (lgt *DirectionalLight) Bounds() []float64 {
+	return infiniteBounds()
+}
@@ -0,0 +1,29 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+func TestDirectionalLightPDF(t *testing.T) {
+	lgt := prepare(t, InitDirectionalLight(3, []float64{1, 1, 1}, []float64{1, -2, 0.5}, 20))
+	point := utils.Vec3{1, 2, 3}
+
+	estimate := checkSamplePDF(t, lgt, point, 32)
+	if total := integrateSphere(400, func(dir utils.Vec3) float64 { return lgt.PDF(point, dir) }); !near(total, 1, 0.01) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	// the disk gives the irradiance of the light
+	if !near(estimate, 3, 1e-9) {
+		t.Errorf("the samples estimate %g, want 3", estimate)
+	}
+}
+
+func TestDirectionalLightDelta(t *testing.T) {
+	lgt := prepare(t, InitDirectionalLight(3, []float64{1, 1, 1}, []float64{0, -2, 0}, 0))
+	checkDelta(t, lgt, utils.Vec3{1, 2, 3})
+	if sample, _ := lgt.Sample(utils.Vec3{}, 0.5, 0.5); sample.Dir != (utils.Vec3{0, 1, 0}) {
+		t.Errorf("got %+v, want the opposite of the direction", sample)
+	}
+}
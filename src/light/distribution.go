@@ -0,0 +1,248 @@
+package light
+
+import (
+	"sort"
+)
+
+// distribution1D is a class for a piecewise constant density over [0, 1], sampled by inverting its cdf.
+//
+// Members:
+//  values   - the value of each of the pieces, not negative.
+//  cdf      - the normalized cumulative sums, len(values) + 1 values from 0 to 1.
+//  integral - the integral of the function over [0, 1].
+//
+type distribution1D struct {
+	values   []float64
+	cdf      []float64
+	integral float64
+}
+
+// initDistribution1D is a function to initialize a distribution1D.
+//
+// Parameters:
+//  values - the value of each of the pieces, uniform when they are all 0.
+//
+// Returns:
+//  the distribution.
+//
+func initDistribution1D(values []float64) distribution1D {
+	n := len(values)
+	cdf := make([]float64, n+1)
+	for i, value := range values {
+		cdf[i+1] = cdf[i] + value/float64(n)
+	}
+	integral := cdf[n]
+	for i := 1; i <= n; i++ {
+		if integral == 0 {
+			cdf[i] = float64(i) / float64(n)
+		} else {
+			cdf[i] /= integral
+		}
+	}
+	return distribution1D{values: values, cdf: cdf, integral: integral}
+}
+
+// sample is a function to choose a point of [0, 1) following the density.
+//
+// Parameters:
+//  u - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the density at the point.
+//  the index of the piece.
+//
+func (dist *distribution1D) sample(u float64) (float64, float64, int) {
+	// the last piece whose cdf does not exceed u
+	offset := sort.Search(len(dist.cdf), func(i int) bool { return dist.cdf[i] > u }) - 1
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(dist.values)-1 {
+		offset = len(dist.values) - 1
+	}
+	du := u - dist.cdf[offset]
+	if width := dist.cdf[offset+1] - dist.cdf[offset]; width > 0 {
+		du /= width
+	}
+	return (float64(offset) + du) / float64(len(dist.values)), dist.density(offset), offset
+}
+
+// density is a function to get the density of a piece.
+//
+// Parameters:
+//  offset - the index of the piece.
+//
+// Returns:
+//  the density, 1 for the uniform distribution of a function that is always 0.
+//
+func (dist *distribution1D) density(offset int) float64 {
+	if dist.integral == 0 {
+		return 1
+	}
+	return dist.values[offset] / dist.integral
+}
+
+// distribution2D is a class for a piecewise constant density over [0, 1]², sampled by choosing a line
+// with the marginal density and then a column with the density of the line.
+//
+// Members:
+//  lines    - the densities of the columns of each line.
+//  marginal - the density of the lines, from their integrals.
+//
+type distribution2D struct {
+	lines    []distribution1D
+	marginal distribution1D
+}
+
+// initDistribution2D is a function to initialize a distribution2D.
+//
+// Parameters:
+//  values - the value of each piece, values[line][column].
+//
+// Returns:
+//  the distribution.
+//
+func initDistribution2D(values [][]float64) *distribution2D {
+	dist := &distribution2D{lines: make([]distribution1D, len(values))}
+	integrals := make([]float64, len(values))
+	for i, line := range values {
+		dist.lines[i] = initDistribution1D(line)
+		integrals[i] = dist.lines[i].integral
+	}
+	dist.marginal = initDistribution1D(integrals)
+	return dist
+}
+
+// sample is a function to choose a point of [0, 1)² following the density.
+//
+// Parameters:
+//  u1 - random number in [0, 1) choosing the column.
+//  u2 - random number in [0, 1) choosing the line.
+//
+// Returns:
+//  the column coordinate.
+//  the line coordinate.
+//  the density at the point.
+//
+func (dist *distribution2D) sample(u1, u2 float64) (float64, float64, float64) {
+	v, pdfLine, line := dist.marginal.sample(u2)
+	u, pdfColumn, _ := dist.lines[line].sample(u1)
+	return u, v, pdfLine * pdfColumn
+}
+
+// pdf is a function to get the density at a point.
+//
+// Parameters:
+//  u - the column coordinate in [0, 1].
+//  v - the line coordinate in [0, 1].
+//
+// Returns:
+//  the density.
+//
+func (dist *distribution2D) pdf(u, v float64) float64 {
+	line := clampIndex(int(v*float64(len(dist.lines))), len(dist.lines))
+	column := clampIndex(int(u*float64(len(dist.lines[line].values))), len(dist.lines[line].values))
+	return dist.marginal.density(line) * dist.lines[line].density(column)
+}
+
+// clampIndex is a function to bring an index inside [0, size).
+//
+// Parameters:
+//  idx  - the index.
+//  size - the number of elements.
+//
+// Returns:
+//  the clamped index.
+//
+func clampIndex(idx, size int) int {
+	if idx < 0 {
+		return 0
+	} else if idx >= size {
+		return size - 1
+	}
+	return idx
+}
+
+// aliasTable is a class for a discrete density sampled in constant time: every bucket keeps its own
+// element with a probability and gives the rest to an alias.
+//
+// Members:
+//  probability - the probability of keeping the element of each bucket.
+//  alias       - the element given by each bucket otherwise.
+//  pmf         - the normalized weights.
+//
+type aliasTable struct {
+	probability []float64
+	alias       []int
+	pmf         []float64
+}
+
+// initAliasTable is a function to initialize an aliasTable.
+//
+// Parameters:
+//  weights - the weight of each element, not negative, uniform when they are all 0.
+//
+// Returns:
+//  the table.
+//
+func initAliasTable(weights []float64) aliasTable {
+	n := len(weights)
+	table := aliasTable{probability: make([]float64, n), alias: make([]int, n), pmf: make([]float64, n)}
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	for i, weight := range weights {
+		if total == 0 {
+			table.pmf[i] = 1 / float64(n)
+		} else {
+			table.pmf[i] = weight / total
+		}
+	}
+	// pairing the buckets below the mean with the ones above it
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, p := range table.pmf {
+		scaled[i] = p * float64(n)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		table.probability[s] = scaled[s]
+		table.alias[s] = l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// the rounding errors leave buckets that are full
+	for _, i := range append(small, large...) {
+		table.probability[i] = 1
+		table.alias[i] = i
+	}
+	return table
+}
+
+// sample is a function to choose an element following the weights.
+//
+// Parameters:
+//  u - random number in [0, 1).
+//
+// Returns:
+//  the index of the element.
+//
+func (table *aliasTable) sample(u float64) int {
+	n := float64(len(table.pmf))
+	bucket := clampIndex(int(u*n), len(table.pmf))
+	if u*n-float64(bucket) < table.probability[bucket] {
+		return bucket
+	}
+	return table.alias[bucket]
+}
@@ -0,0 +1,329 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/texture"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// EnvironmentLight is a class for the light coming from every direction around the scene, read from
+// an equirectangular image: the columns go around the y axis, starting and ending behind -z, and the
+// lines go from +y at the top to -y at the bottom.
+//
+// Members:
+//  Emitter  - the color and the intensities, Color * LightIntensity multiplies the image.
+//  Image    - the Radiance .hdr or .pfm image (other formats are read as sRGB).
+//  Rotation - rotation of the image in degrees around the y axis.
+//  mapping  - the directions of the pixels, sampled following their luminance.
+//
+type EnvironmentLight struct {
+	Emitter
+	Image    *texture.Image
+	Rotation float64
+	mapping  equirectangular
+}
+
+// equirectangular is a class for the mapping of the directions to an image around the scene, with a density
+// following the values of its pixels.
+//
+// Members:
+//  cosRotation  - cosine of the rotation around the y axis.
+//  sinRotation  - sine of the rotation around the y axis.
+//  distribution - the density of the pixels.
+//
+type equirectangular struct {
+	cosRotation  float64
+	sinRotation  float64
+	distribution *distribution2D
+}
+
+// initEquirectangular is a function to initialize an equirectangular.
+//
+// Parameters:
+//  rotation - rotation in degrees around the y axis.
+//  width    - number of columns.
+//  height   - number of lines.
+//  value    - function giving the brightness of a pixel, not negative.
+//
+// Returns:
+//  the mapping.
+//
+func initEquirectangular(rotation float64, width, height int, value func(x, y int) float64) equirectangular {
+	// the pixels near the poles cover smaller solid angles
+	values := make([][]float64, height)
+	for y := range values {
+		values[y] = make([]float64, width)
+		sinTheta := math.Sin((float64(y) + 0.5) / float64(height) * math.Pi)
+		for x := range values[y] {
+			values[y][x] = value(x, y) * sinTheta
+		}
+	}
+	return equirectangular{
+		cosRotation:  math.Cos(rotation * math.Pi / 180),
+		sinRotation:  math.Sin(rotation * math.Pi / 180),
+		distribution: initDistribution2D(values),
+	}
+}
+
+// InitEnvironmentLight is a function to initialize an EnvironmentLight.
+//
+// Parameters:
+//  lightIntensity - the intensity multiplying the image.
+//  color          - the rgb multiplying the image.
+//  img            - the image, loaded by Prepare if needed.
+//  rotation       - rotation of the image in degrees around the y axis.
+//
+// Returns:
+// 	the EnvironmentLight, not prepared.
+//
+func InitEnvironmentLight(lightIntensity float64, color []float64, img *texture.Image, rotation float64) *EnvironmentLight {
+	return &EnvironmentLight{
+		Emitter:  Emitter{Type: TypeEnvironment, LightIntensity: lightIntensity, Color: color},
+		Image:    img,
+		Rotation: rotation,
+	}
+}
+
+// Prepare is a function to check the light, load its image and build the density of its pixels.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color, the image or the rotation is invalid.
+//
+func (lgt *EnvironmentLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	if lgt.Image == nil || lgt.Image.Path == "" {
+		return utils.WrapField("Image", errors.New("no image"))
+	}
+	if math.IsNaN(lgt.Rotation) || math.IsInf(lgt.Rotation, 0) {
+		return utils.WrapField("Rotation", fmt.Errorf("invalid rotation %g", lgt.Rotation))
+	}
+	if !lgt.Image.Loaded() {
+		if err := lgt.Image.Load(); err != nil {
+			return utils.WrapField("Image", err)
+		}
+	}
+	width, height := lgt.Image.Size()
+	lgt.mapping = initEquirectangular(lgt.Rotation, width, height, func(x, y int) float64 {
+		return math.Max(0, luminance(lgt.Image.Texel(x, y)))
+	})
+	return nil
+}
+
+// toImage is a function to get the image coordinates of a direction.
+//
+// Parameters:
+//  dir - the direction in the world.
+//
+// Returns:
+//  the column coordinate in [0, 1].
+//  the line coordinate in [0, 1], 0 at the top.
+//  sine of the angle between the direction and the y axis.
+//
+func (mapping *equirectangular) toImage(dir utils.Vec3) (float64, float64, float64) {
+	dir = dir.Normalize()
+	// undoing the rotation
+	x := mapping.cosRotation*dir[0] - mapping.sinRotation*dir[2]
+	z := mapping.sinRotation*dir[0] + mapping.cosRotation*dir[2]
+	theta := math.Acos(math.Max(-1, math.Min(1, dir[1])))
+	u := 0.5 + math.Atan2(x, -z)/(2*math.Pi)
+	return u, theta / math.Pi, math.Sin(theta)
+}
+
+// fromImage is a function to get the direction of image coordinates.
+//
+// Parameters:
+//  u - the column coordinate.
+//  v - the line coordinate, 0 at the top.
+//
+// Returns:
+//  the normalized direction in the world.
+//  sine of the angle between the direction and the y axis.
+//
+func (mapping *equirectangular) fromImage(u, v float64) (utils.Vec3, float64) {
+	theta := v * math.Pi
+	phi := (u - 0.5) * 2 * math.Pi
+	sinTheta := math.Sin(theta)
+	x := sinTheta * math.Sin(phi)
+	z := -sinTheta * math.Cos(phi)
+	return utils.Vec3{mapping.cosRotation*x + mapping.sinRotation*z, math.Cos(theta), -mapping.sinRotation*x + mapping.cosRotation*z}, sinTheta
+}
+
+// sample is a function to choose a direction with the density of the pixels.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the normalized direction.
+//  the solid angle density.
+//  false if the direction is a pole of the image or its density is 0.
+//
+func (mapping *equirectangular) sample(u1, u2 float64) (utils.Vec3, float64, bool) {
+	u, v, mapPdf := mapping.distribution.sample(u1, u2)
+	dir, sinTheta := mapping.fromImage(u, v)
+	if mapPdf == 0 || sinTheta == 0 {
+		return dir, 0, false
+	}
+	// the image covers 2π by π radians
+	return dir, mapPdf / (2 * math.Pi * math.Pi * sinTheta), true
+}
+
+// pdf is a function to get the density of sample choosing a direction.
+//
+// Parameters:
+//  dir - the direction.
+//
+// Returns:
+//  the solid angle density.
+//
+func (mapping *equirectangular) pdf(dir utils.Vec3) float64 {
+	u, v, sinTheta := mapping.toImage(dir)
+	if sinTheta == 0 {
+		return 0
+	}
+	return mapping.distribution.pdf(u, v) / (2 * math.Pi * math.Pi * sinTheta)
+}
+
+// integral is a function to get the integral over the sphere of the values of the pixels.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the integral, the mean of the values over the image times its 2π² area.
+//
+func (mapping *equirectangular) integral() float64 {
+	return 2 * math.Pi * math.Pi * mapping.distribution.marginal.integral
+}
+
+// Sample is a function to choose a direction with a density following the brightness of the image.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the sample, at an infinite distance.
+//  false if the chosen direction is a pole of the image.
+//
+func (lgt *EnvironmentLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	dir, pdf, ok := lgt.mapping.sample(u1, u2)
+	if !ok {
+		return Sample{}, false
+	}
+	return Sample{Dir: dir, Dist: math.Inf(1), Radiance: lgt.Radiance(dir), PDF: pdf}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  dir   - the direction.
+//
+// Returns:
+//  the solid angle density.
+//
+func (lgt *EnvironmentLight) PDF(point, dir utils.Vec3) float64 {
+	return lgt.mapping.pdf(dir)
+}
+
+// Intersect is a function to intersect a ray with the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  0 and false, the light is only reached by escaping rays.
+//
+func (lgt *EnvironmentLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	return 0, false
+}
+
+// Radiance is a function to get the radiance of a ray escaping the scene.
+//
+// Parameters:
+//  dir - the direction of the ray.
+//
+// Returns:
+//  the filtered pixel of the image multiplied by the emission.
+//
+func (lgt *EnvironmentLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	u, v, _ := lgt.mapping.toImage(dir)
+	// the v axis of the image points up
+	rgb := lgt.Image.Sample(u, 1-v)
+	emission := lgt.emission()
+	return utils.Vec3{rgb[0] * emission[0], rgb[1] * emission[1], rgb[2] * emission[2]}
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *EnvironmentLight) IsDelta() bool {
+	return false
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true.
+//
+func (lgt *EnvironmentLight) IsInfinite() bool {
+	return true
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene.
+//
+// Returns:
+//  the radiance integrated over the sphere times the area of a disk of the scene.
+//
+func (lgt *EnvironmentLight) Power(sceneRadius float64) float64 {
+	return math.Pi * sceneRadius * sceneRadius * lgt.mapping.integral() * lgt.emissionLuminance()
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the infinite bounds.
+//
+func (lgt *EnvironmentLight) Bounds() []float64 {
+	return infiniteBounds()
+}
+
+// luminance is a function to get the luminance of a linear RGB.
+//
+// Parameters:
+//  rgb - the color.
+//
+// Returns:
+//  the luminance.
+//
+func luminance(rgb []float64) float64 {
+	return 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+}
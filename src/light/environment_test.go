@@ -0,0 +1,105 @@
+package light
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/texture"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// writePFM is a function to write a grayscale portable float map in a temporary folder, removed by the caller.
+func writePFM(t *testing.T, width, height int, value func(x, y int) float32) (string, string) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "light")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "Pf\n%d %d\n-1\n", width, height)
+	// the lines are stored from the bottom
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			binary.Write(&buffer, binary.LittleEndian, value(x, y))
+		}
+	}
+	path := filepath.Join(dir, "env.pfm")
+	if err := ioutil.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir, path
+}
+
+// spotValue is a function to get the pixels of an image with a bright spot over stripes, not contrasted enough
+// for the filtering of the image to slow the convergence of the samples.
+func spotValue(x, y int) float32 {
+	if x >= 5 && x <= 6 && y >= 2 && y <= 3 {
+		return 4
+	}
+	return 0.5 + 0.1*float32(x%4)
+}
+
+func TestDistribution2D(t *testing.T) {
+	values := [][]float64{{1, 0, 3}, {0, 0, 0}, {2, 5, 1}}
+	dist := initDistribution2D(values)
+	total := 0.0
+	for _, line := range []float64{1. / 6, 3. / 6, 5. / 6} {
+		for _, column := range []float64{1. / 6, 3. / 6, 5. / 6} {
+			total += dist.pdf(column, line) / 9
+		}
+	}
+	if !near(total, 1, 1e-12) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	for i := 0; i < 16; i++ {
+		for j := 0; j < 16; j++ {
+			u, v, pdf := dist.sample((float64(i)+0.5)/16, (float64(j)+0.5)/16)
+			if pdf == 0 || !near(dist.pdf(u, v), pdf, 1e-12) {
+				t.Fatalf("sampled (%g, %g) with a density of %g, pdf gives %g", u, v, pdf, dist.pdf(u, v))
+			}
+		}
+	}
+}
+
+func TestEquirectangularMapping(t *testing.T) {
+	mapping := initEquirectangular(30, 4, 2, func(x, y int) float64 { return 1 })
+	for _, dir := range sphereDirections(8) {
+		u, v, _ := mapping.toImage(dir)
+		back, _ := mapping.fromImage(u, v)
+		if back.Sub(dir).Length() > 1e-9 {
+			t.Fatalf("%v goes to (%g, %g) and back to %v", dir, u, v, back)
+		}
+	}
+}
+
+func TestEnvironmentLightPDF(t *testing.T) {
+	dir, path := writePFM(t, 16, 8, spotValue)
+	defer os.RemoveAll(dir)
+	env := InitEnvironmentLight(2, []float64{1, 1, 1}, &texture.Image{Path: path}, 30)
+	lgt := prepare(t, env)
+	point := utils.Vec3{1, 2, 3}
+
+	estimate := checkSamplePDF(t, lgt, point, 128)
+	if total := integrateSphere(400, func(dir utils.Vec3) float64 { return lgt.PDF(point, dir) }); !near(total, 1, 0.01) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	want := integrateSphere(400, func(dir utils.Vec3) float64 {
+		radiance := lgt.Radiance(dir)
+		return luminance(radiance[:])
+	})
+	if !near(estimate, want, 0.03) {
+		t.Errorf("the samples estimate %g, want %g", estimate, want)
+	}
+	// the bright spot is sampled more often than its solid angle
+	spot, _ := env.mapping.fromImage(5.5/16, 2.5/8)
+	if pdf := lgt.PDF(point, spot); !(pdf > 1/(4*math.Pi)) {
+		t.Errorf("the spot has a density of %g", pdf)
+	}
+}
@@ -0,0 +1,402 @@
+package light
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// iesTypeC is the photometric type of the IES files measured around a vertical axis, the only one supported.
+const iesTypeC = 1
+
+// profileSteps are the numbers of vertical and horizontal angles used to integrate a profile.
+const (
+	profileVerticalSteps   = 180
+	profileHorizontalSteps = 72
+)
+
+// Profile is a class for the measured intensity of a luminaire in every direction, read from an
+// IES LM-63 file with type C photometry. The vertical angles go from the axis of the light (0) to
+// its opposite (180), the horizontal angles go around the axis from the reference direction.
+//
+// Members:
+//  Path       - path to the .ies file.
+//  vertical   - the vertical angles in degrees, increasing.
+//  horizontal - the horizontal angles in degrees, increasing.
+//  candela    - the intensity of every horizontal and vertical angle, divided by the largest one.
+//  maxCandela - the largest intensity of the file, in candelas.
+//
+type Profile struct {
+	Path       string
+	vertical   []float64
+	horizontal []float64
+	candela    [][]float64
+	maxCandela float64
+}
+
+// photometricFrame is a class for the orientation of a profile in the world.
+//
+// Members:
+//  axis      - the direction of the vertical angle 0.
+//  reference - the direction of the horizontal angle 0.
+//  ninety    - the direction of the horizontal angle 90.
+//
+type photometricFrame struct {
+	axis      utils.Vec3
+	reference utils.Vec3
+	ninety    utils.Vec3
+}
+
+// initPhotometricFrame is a function to initialize a photometricFrame.
+//
+// Parameters:
+//  axis - the normalized axis of the light.
+//
+// Returns:
+//  the frame, the horizontal angle 0 towards +x (+z when the axis is close to x).
+//
+func initPhotometricFrame(axis utils.Vec3) photometricFrame {
+	reference := utils.Vec3{1, 0, 0}
+	if math.Abs(axis[0]) > 0.9 {
+		reference = utils.Vec3{0, 0, 1}
+	}
+	reference = reference.Sub(axis.Scale(reference.Dot(axis))).Normalize()
+	return photometricFrame{axis: axis, reference: reference, ninety: axis.Cross(reference)}
+}
+
+// readIESNumbers is a function to read the numbers after the TILT line of an IES file.
+//
+// Parameters:
+//  reader - the file reader, after the TILT line.
+//
+// Returns:
+//  the numbers, separated by blanks or commas.
+//  an error if a value is not a number.
+//
+func readIESNumbers(reader *bufio.Reader) ([]float64, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.FieldsFunc(string(data), func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	numbers := make([]float64, len(fields))
+	for i, field := range fields {
+		if numbers[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return nil, fmt.Errorf("invalid ies value %q", field)
+		}
+	}
+	return numbers, nil
+}
+
+// checkAngles is a function to check that the angles of a profile increase inside a range.
+//
+// Parameters:
+//  name   - name of the angles for the errors.
+//  angles - the angles in degrees.
+//  max    - the largest angle accepted.
+//
+// Returns:
+//  an error.
+//
+func checkAngles(name string, angles []float64, max float64) error {
+	for i, angle := range angles {
+		if angle < 0 || angle > max {
+			return fmt.Errorf("%s angle %g outside of [0, %g]", name, angle, max)
+		}
+		if i > 0 && angle <= angles[i-1] {
+			return fmt.Errorf("%s angles not increasing at %g", name, angle)
+		}
+	}
+	return nil
+}
+
+// read is a function to parse an IES LM-63 file (1986, 1991, 1995 or 2002). The keywords are
+// skipped and the lamp tilt data is not used.
+//
+// Parameters:
+//  file - the opened file.
+//
+// Returns:
+//  an error if the file is not a type C IES file.
+//
+func (profile *Profile) read(file io.Reader) error {
+	reader := bufio.NewReader(file)
+	tilt := ""
+	for tilt == "" {
+		line, err := reader.ReadString('\n')
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "TILT=") {
+			tilt = strings.TrimPrefix(trimmed, "TILT=")
+		} else if err == io.EOF {
+			return errors.New("missing TILT line")
+		} else if err != nil {
+			return err
+		}
+	}
+	numbers, err := readIESNumbers(reader)
+	if err != nil {
+		return err
+	}
+	next := 0
+	take := func(n int) ([]float64, error) {
+		if n < 0 || next+n > len(numbers) {
+			return nil, errors.New("unexpected end of ies data")
+		}
+		values := numbers[next : next+n]
+		next += n
+		return values, nil
+	}
+	if tilt == "INCLUDE" {
+		// lamp to luminaire geometry, number of tilt angles, the angles and their factors
+		header, err := take(2)
+		if err != nil {
+			return err
+		}
+		if _, err = take(2 * int(header[1])); err != nil {
+			return err
+		}
+	}
+	// lamps, lumens per lamp, candela multiplier, vertical and horizontal angles, photometric type,
+	// units, width, length, height, ballast factor, ballast lamp factor and input watts
+	header, err := take(13)
+	if err != nil {
+		return err
+	}
+	multiplier, verticalCount, horizontalCount, photometricType, ballast := header[2], int(header[3]), int(header[4]), int(header[5]), header[10]
+	if photometricType != iesTypeC {
+		return fmt.Errorf("unsupported photometric type %d, only type C (1) is supported", photometricType)
+	}
+	if verticalCount < 2 || horizontalCount < 1 {
+		return fmt.Errorf("invalid number of angles (%d, %d)", verticalCount, horizontalCount)
+	}
+	vertical, err := take(verticalCount)
+	if err != nil {
+		return err
+	}
+	horizontal, err := take(horizontalCount)
+	if err != nil {
+		return err
+	}
+	if err = checkAngles("vertical", vertical, 180); err != nil {
+		return err
+	}
+	if err = checkAngles("horizontal", horizontal, 360); err != nil {
+		return err
+	}
+	candela := make([][]float64, horizontalCount)
+	maxCandela := 0.0
+	for h := range candela {
+		if candela[h], err = take(verticalCount); err != nil {
+			return err
+		}
+		for v, value := range candela[h] {
+			if value < 0 || math.IsNaN(value) {
+				return fmt.Errorf("invalid candela value %g", value)
+			}
+			candela[h][v] = value * multiplier * ballast
+			maxCandela = math.Max(maxCandela, candela[h][v])
+		}
+	}
+	if !(maxCandela > 0) || math.IsInf(maxCandela, 0) {
+		return fmt.Errorf("invalid largest intensity %g", maxCandela)
+	}
+	for h := range candela {
+		for v := range candela[h] {
+			candela[h][v] /= maxCandela
+		}
+	}
+	profile.vertical = vertical
+	profile.horizontal = horizontal
+	profile.candela = candela
+	profile.maxCandela = maxCandela
+	return nil
+}
+
+// Load is a function to read the intensities of the Profile from its Path.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  an error.
+//
+func (profile *Profile) Load() error {
+	file, err := os.Open(profile.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = profile.read(file); err != nil {
+		return fmt.Errorf("%s: %s", profile.Path, err)
+	}
+	return nil
+}
+
+// Loaded is a function to check if the intensities of the Profile were read.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true if the profile can be evaluated.
+//
+func (profile *Profile) Loaded() bool {
+	return profile.candela != nil
+}
+
+// MaxCandela is a function to get the largest intensity of the file.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the intensity in candelas, with the multipliers of the file.
+//
+func (profile *Profile) MaxCandela() float64 {
+	return profile.maxCandela
+}
+
+// interval is a function to find the two angles around an angle.
+//
+// Parameters:
+//  angles - the increasing angles.
+//  angle  - the angle, clamped to the range of the angles.
+//
+// Returns:
+//  the index of the first angle.
+//  the position between the first and the second angle, in [0, 1].
+//
+func interval(angles []float64, angle float64) (int, float64) {
+	if len(angles) == 1 || angle <= angles[0] {
+		return 0, 0
+	}
+	last := len(angles) - 1
+	if angle >= angles[last] {
+		return last - 1, 1
+	}
+	i := sort.SearchFloat64s(angles, angle)
+	if angles[i] == angle {
+		return i, 0
+	}
+	return i - 1, (angle - angles[i-1]) / (angles[i] - angles[i-1])
+}
+
+// intensity is a function to get the bilinear interpolated intensity at some angles.
+//
+// Parameters:
+//  vertical   - the vertical angle in [0, 180] degrees.
+//  horizontal - the horizontal angle in [0, 360) degrees.
+//
+// Returns:
+//  the intensity divided by the largest one, 0 outside the vertical angles of the file.
+//
+func (profile *Profile) intensity(vertical, horizontal float64) float64 {
+	if vertical < profile.vertical[0] || vertical > profile.vertical[len(profile.vertical)-1] {
+		return 0
+	}
+	// the files measure a part of the circle when the luminaire is symmetric
+	first, last := profile.horizontal[0], profile.horizontal[len(profile.horizontal)-1]
+	switch {
+	case len(profile.horizontal) == 1:
+		horizontal = first
+	case first == 0 && last == 90:
+		if horizontal > 180 {
+			horizontal = 360 - horizontal
+		}
+		if horizontal > 90 {
+			horizontal = 180 - horizontal
+		}
+	case first == 0 && last == 180:
+		if horizontal > 180 {
+			horizontal = 360 - horizontal
+		}
+	case first == 90 && last == 270:
+		if horizontal < 90 || horizontal > 270 {
+			horizontal = math.Mod(540-horizontal, 360)
+		}
+	}
+	v, tv := interval(profile.vertical, vertical)
+	h, th := interval(profile.horizontal, horizontal)
+	h2 := h
+	if len(profile.horizontal) > 1 {
+		h2 = h + 1
+	}
+	lerp := func(line []float64) float64 {
+		return line[v]*(1-tv) + line[v+1]*tv
+	}
+	return lerp(profile.candela[h])*(1-th) + lerp(profile.candela[h2])*th
+}
+
+// evaluate is a function to get the intensity emitted in a direction.
+//
+// Parameters:
+//  frame - the orientation of the profile.
+//  dir   - the normalized direction, leaving the light.
+//
+// Returns:
+//  the intensity divided by the largest one.
+//
+func (profile *Profile) evaluate(frame *photometricFrame, dir utils.Vec3) float64 {
+	vertical := math.Acos(math.Max(-1, math.Min(1, dir.Dot(frame.axis)))) * 180 / math.Pi
+	horizontal := math.Atan2(dir.Dot(frame.ninety), dir.Dot(frame.reference)) * 180 / math.Pi
+	if horizontal < 0 {
+		horizontal += 360
+	}
+	return profile.intensity(vertical, horizontal)
+}
+
+// integral is a function to integrate the intensity over the sphere.
+//
+// Parameters:
+//  weight - function multiplying the intensity, from the cosine of the vertical angle.
+//
+// Returns:
+//  the integral of the weighted intensity divided by the largest one.
+//
+func (profile *Profile) integral(weight func(cosTheta float64) float64) float64 {
+	dTheta := math.Pi / profileVerticalSteps
+	dPhi := 2 * math.Pi / profileHorizontalSteps
+	sum := 0.0
+	for i := 0; i < profileVerticalSteps; i++ {
+		theta := (float64(i) + 0.5) * dTheta
+		line := 0.0
+		for j := 0; j < profileHorizontalSteps; j++ {
+			phi := (float64(j) + 0.5) * dPhi
+			line += profile.intensity(theta*180/math.Pi, phi*180/math.Pi)
+		}
+		sum += line * weight(math.Cos(theta)) * math.Sin(theta)
+	}
+	return sum * dTheta * dPhi
+}
+
+// prepareProfile is a function to load a profile if needed.
+//
+// Parameters:
+//  profile - the profile, may be nil.
+//
+// Returns:
+//  a *utils.FieldError if the file can not be read.
+//
+func prepareProfile(profile *Profile) error {
+	if profile == nil {
+		return nil
+	}
+	if profile.Path == "" {
+		return utils.WrapField("Profile", errors.New("no path"))
+	}
+	if !profile.Loaded() {
+		if err := profile.Load(); err != nil {
+			return utils.WrapField("Profile", err)
+		}
+	}
+	return nil
+}
@@ -3,15 +3,28 @@ package light
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path"
 	"path/filepath"
 
-	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/shape"
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
 
+// Types of the lights, the Type of a light in json.
+const (
+	TypeArea        = "area"
+	TypePoint       = "point"
+	TypeSpot        = "spot"
+	TypeDirectional = "directional"
+	TypeEnvironment = "environment"
+	TypeSky         = "sky"
+)
+
 // Lights is a structure for holding all light data.
 //
 // Members:
@@ -21,6 +34,196 @@ type Lights struct {
 	LightList []Light
 }
 
+// Light is an interface for the emitters of a scene.
+// Directions always leave the shaded point, distances and pdfs are in world space.
+//
+// Methods:
+//  GetEmitter - the color and the intensities of the light.
+//  Prepare    - checks the light and caches what the sampling needs, must be called after any change.
+//  Sample     - a direction from a point to the light, false when the light does not reach the point.
+//  PDF        - the solid angle density of Sample choosing a direction, 0 for delta lights.
+//  Intersect  - the t of the closest intersection with t in [tMin, tMax), delta and infinite lights are never hit.
+//  Radiance   - the radiance arriving along a direction from a hit of Intersect or, for infinite lights, from a ray escaping the scene.
+//  IsDelta    - true for lights only reached by Sample, a single point or direction.
+//  IsInfinite - true for lights far away from every object, reached by rays escaping the scene.
+//  Power      - the luminance of the emitted power, used to choose the lights, the lights at infinity
+//               use the radius of the bounding sphere of the scene.
+//  Bounds     - the bounding box [minX, minY, minZ, maxX, maxY, maxZ], infinite for the lights at infinity.
+//
+type Light interface {
+	GetEmitter() *Emitter
+	Prepare() error
+	Sample(point utils.Vec3, u1, u2 float64) (Sample, bool)
+	PDF(point, dir utils.Vec3) float64
+	Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool)
+	Radiance(dir utils.Vec3) utils.Vec3
+	IsDelta() bool
+	IsInfinite() bool
+	Power(sceneRadius float64) float64
+	Bounds() []float64
+}
+
+// Sample is a class for a direction sampled towards a light.
+//
+// Members:
+//  Dir      - the normalized direction from the point to the light.
+//  Dist     - the distance to the sampled point of the light, infinite for lights at infinity.
+//  Radiance - the radiance arriving at the point, intensity divided by the squared distance for point lights.
+//  PDF      - the solid angle density of the direction, 1 for delta lights.
+//
+type Sample struct {
+	Dir      utils.Vec3
+	Dist     float64
+	Radiance utils.Vec3
+	PDF      float64
+}
+
+// Emitter is a class for the attributes every light has, inlined in the json of the lights.
+//
+// Members:
+//  Type             - TypeArea (or empty), TypePoint, TypeSpot, TypeDirectional, TypeEnvironment or TypeSky.
+//  AmbientIntensity - the ambient intensity.
+//  LightIntensity   - the light intensity.
+//  Color            - RGB of the light.
+//
+type Emitter struct {
+	Type             string
+	AmbientIntensity float64
+	LightIntensity   float64
+	Color            []float64
+}
+
+// GetEmitter is a function to get the common attributes of a light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the Emitter.
+//
+func (emitter *Emitter) GetEmitter() *Emitter {
+	return emitter
+}
+
+// emission is a function to get the color multiplied by the intensity.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the emitted RGB.
+//
+func (emitter *Emitter) emission() utils.Vec3 {
+	return utils.Vec3{emitter.Color[0], emitter.Color[1], emitter.Color[2]}.Scale(emitter.LightIntensity)
+}
+
+// emissionLuminance is a function to get the luminance of the color multiplied by the intensity.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the luminance.
+//
+func (emitter *Emitter) emissionLuminance() float64 {
+	emission := emitter.emission()
+	return luminance(emission[:])
+}
+
+// checkEmitter is a function to check the color of a light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color is not a RGB.
+//
+func (emitter *Emitter) checkEmitter() error {
+	return utils.WrapField("Color", utils.CheckSize(len(emitter.Color), 3))
+}
+
+// newLight is a function to create an empty light of a type.
+//
+// Parameters:
+//  lightType - the type, empty for an area light.
+//
+// Returns:
+//  the light.
+//  an error if the type is unknown.
+//
+func newLight(lightType string) (Light, error) {
+	switch lightType {
+	case "", TypeArea:
+		return &AreaLight{Emitter: Emitter{Type: lightType}}, nil
+	case TypePoint:
+		return &PointLight{Emitter: Emitter{Type: lightType}}, nil
+	case TypeSpot:
+		return &SpotLight{Emitter: Emitter{Type: lightType}}, nil
+	case TypeDirectional:
+		return &DirectionalLight{Emitter: Emitter{Type: lightType}}, nil
+	case TypeEnvironment:
+		return &EnvironmentLight{Emitter: Emitter{Type: lightType}}, nil
+	case TypeSky:
+		return &SkyLight{Emitter: Emitter{Type: lightType}}, nil
+	}
+	return nil, fmt.Errorf("unknown light type %q", lightType)
+}
+
+// UnmarshalJSON is a function to read the lights of the types given by their Type.
+//
+// Parameters:
+//  data - the json.
+//
+// Returns:
+//  an error if the json is invalid or a type is unknown.
+//
+func (lgts *Lights) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		LightList []json.RawMessage
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	lgts.LightList = make([]Light, len(raw.LightList))
+	for i, item := range raw.LightList {
+		var header struct {
+			Type string
+		}
+		if err := json.Unmarshal(item, &header); err != nil {
+			return utils.WrapField(fmt.Sprintf("LightList[%d]", i), err)
+		}
+		lgt, err := newLight(header.Type)
+		if err != nil {
+			return utils.WrapField(fmt.Sprintf("LightList[%d].Type", i), err)
+		}
+		if err = json.Unmarshal(item, lgt); err != nil {
+			return utils.WrapField(fmt.Sprintf("LightList[%d]", i), err)
+		}
+		lgts.LightList[i] = lgt
+	}
+	return nil
+}
+
+// Prepare is a function to prepare every light for rendering.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError with the path to the invalid field of a light.
+//
+func (lgts *Lights) Prepare() error {
+	for i, lgt := range lgts.LightList {
+		if lgt == nil {
+			return utils.WrapField(fmt.Sprintf("LightList[%d]", i), errors.New("empty light"))
+		}
+		if err := lgt.Prepare(); err != nil {
+			return utils.WrapField(fmt.Sprintf("LightList[%d]", i), err)
+		}
+	}
+	return nil
+}
+
 // LoadJSONLights is a function to read all Light data as json.
 //
 // Parameters:
@@ -28,25 +231,22 @@ type Lights struct {
 //
 // Returns:
 //  the light
+//  a *utils.FileError if the file could not be read, parsed or validated.
 //
-func LoadJSONLights(inPath string) *Lights {
-	// opening the file
-	lightFile, err := os.Open(inPath)
-	utils.ShowError(err, "Unable to open light.")
-	// converting to light
-	byteLight, err := ioutil.ReadAll(lightFile)
-	utils.ShowError(err, "Unable to convert light file to bytes.")
+func LoadJSONLights(inPath string) (*Lights, error) {
+	byteLight, err := ioutil.ReadFile(inPath)
+	if err != nil {
+		return nil, err
+	}
 	var lightAux Lights
-	err = json.Unmarshal(byteLight, &lightAux)
-	utils.ShowError(err, "Failed to unmarshal light.")
-	// Validating the camera
-	for _, lgt := range lightAux.LightList {
-		if len(lgt.Color) != 3 {
-			utils.ShowError(errors.New("invalid length of a light component"), "light components must have length equal to 3.")
-		}
+	if err = json.Unmarshal(byteLight, &lightAux); err != nil {
+		return nil, utils.WrapFile("parse", inPath, err)
 	}
-
-	return &lightAux
+	// Validating the lights
+	if err = lightAux.Prepare(); err != nil {
+		return nil, utils.WrapFile("load", inPath, err)
+	}
+	return &lightAux, nil
 }
 
 // WriteJSONLights is a function to write all Light data as json.
@@ -55,23 +255,27 @@ func LoadJSONLights(inPath string) *Lights {
 //  outPath - path to the output folder.
 //
 // Returns:
-//  none
+//  an error if the lights could not be written.
 //
-func (lgt *Lights) WriteJSONLights(outPath string) {
+func (lgt *Lights) WriteJSONLights(outPath string) error {
 	// creating the json
 	file, err := json.MarshalIndent(*lgt, "", "	")
-	utils.ShowError(err, "Unable to convert light to json.")
+	if err != nil {
+		return err
+	}
 	// getting the right path
 	filePath, err := filepath.Abs(path.Join(outPath, "light.json"))
-	utils.ShowError(err, "Unable to get light's absolute path.")
+	if err != nil {
+		return err
+	}
 	// creating the folder if it doesn't exists.
 	if !utils.PathExists(filePath) {
-		err = os.MkdirAll(outPath, 0700)
-		utils.ShowError(err, "Unable to create dirs.")
+		if err = os.MkdirAll(outPath, 0700); err != nil {
+			return err
+		}
 	}
 	// writing
-	err = ioutil.WriteFile(filePath, file, 0700)
-	utils.ShowError(err, "Unable to write light.")
+	return ioutil.WriteFile(filePath, file, 0700)
 }
 
 // InitLights is a function to initialize a Lights.
@@ -87,37 +291,96 @@ func InitLights(lightList []Light) Lights {
 	return lights
 }
 
-// Light is a structure for holding a light data.
+// checkVector is a function to check that a list is a 3D vector.
 //
-// Members:
-//  AmbientIntensity   - the ambient intensity.
-//  LightIntensity     - the light intensity.
-//  LightObject        - Object for the light.
-//  Color              - RGB of the light.
+// Parameters:
+//  field  - name of the field.
+//  values - the list.
 //
-type Light struct {
-	AmbientIntensity float64
-	LightIntensity   float64
-	Color            []float64
-	LightObject      general.Object
+// Returns:
+//  the Vec3.
+//  a *utils.FieldError if the list is not 3D.
+//
+func checkVector(field string, values []float64) (utils.Vec3, error) {
+	if err := utils.CheckSize(len(values), 3); err != nil {
+		return utils.Vec3{}, utils.WrapField(field, err)
+	}
+	return utils.Vec3{values[0], values[1], values[2]}, nil
 }
 
-// InitLight is a function to initialize a Light.
+// checkDirection is a function to check that a list is a non zero 3D vector.
 //
 // Parameters:
-//  ambientIntensity  - the ambient intensity of the light.
-//  lightIntensity    - the instensity of the light.
-//  object            - the object tha defines the light.
-//  color             - the rgb of the light.
+//  field  - name of the field.
+//  values - the list.
 //
 // Returns:
-// 	A Light.
+//  the normalized Vec3.
+//  a *utils.FieldError if the list is not 3D or has length 0.
 //
-func InitLight(ambientIntensity, lightIntensity float64, object general.Object, color []float64) Light {
-	lgt := Light{
-		AmbientIntensity: ambientIntensity,
-		LightIntensity:   lightIntensity,
-		LightObject:      object,
+func checkDirection(field string, values []float64) (utils.Vec3, error) {
+	dir, err := checkVector(field, values)
+	if err != nil {
+		return dir, err
 	}
-	return lgt
+	if dir.Length() == 0 {
+		return dir, utils.WrapField(field, errors.New("zero length direction"))
+	}
+	return dir.Normalize(), nil
+}
+
+// sampleCone is a function to get a direction uniformly distributed inside a cone.
+//
+// Parameters:
+//  axis   - the normalized axis of the cone.
+//  cosMax - cosine of the angle between the axis and the border.
+//  u1     - random number in [0, 1).
+//  u2     - random number in [0, 1).
+//
+// Returns:
+//  the normalized direction.
+//
+func sampleCone(axis utils.Vec3, cosMax, u1, u2 float64) utils.Vec3 {
+	cosTheta := 1 - u1*(1-cosMax)
+	sinTheta := math.Sqrt(math.Max(0, 1-cosTheta*cosTheta))
+	phi := 2 * math.Pi * u2
+	tangent, bitangent := shape.TangentFrame(axis)
+	return axis.Scale(cosTheta).AddScaled(tangent, sinTheta*math.Cos(phi)).AddScaled(bitangent, sinTheta*math.Sin(phi)).Normalize()
+}
+
+// infiniteBounds is a function to get the bounds of the lights at infinity.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the infinite bounding box.
+//
+func infiniteBounds() []float64 {
+	return []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1), math.Inf(1)}
+}
+
+// pointBounds is a function to get the bounds of a sphere.
+//
+// Parameters:
+//  center - the center.
+//  radius - the radius, 0 for a point.
+//
+// Returns:
+//  the bounding box.
+//
+func pointBounds(center utils.Vec3, radius float64) []float64 {
+	return []float64{center[0] - radius, center[1] - radius, center[2] - radius, center[0] + radius, center[1] + radius, center[2] + radius}
+}
+
+// conePDF is a function to get the solid angle density of the directions of sampleCone.
+//
+// Parameters:
+//  cosMax - cosine of the angle between the axis and the border.
+//
+// Returns:
+//  1 / the solid angle of the cone.
+//
+func conePDF(cosMax float64) float64 {
+	return 1 / (2 * math.Pi * (1 - cosMax))
 }
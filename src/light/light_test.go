@@ -0,0 +1,88 @@
+package light
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// sphereDirections is a function to get directions stratified over the sphere, each one covering the same
+// solid angle of 4π / (2 * n * n).
+func sphereDirections(n int) []utils.Vec3 {
+	dirs := make([]utils.Vec3, 0, 2*n*n)
+	for i := 0; i < n; i++ {
+		z := 1 - 2*(float64(i)+0.5)/float64(n)
+		r := math.Sqrt(1 - z*z)
+		for j := 0; j < 2*n; j++ {
+			phi := 2 * math.Pi * (float64(j) + 0.5) / float64(2*n)
+			dirs = append(dirs, utils.Vec3{r * math.Cos(phi), r * math.Sin(phi), z})
+		}
+	}
+	return dirs
+}
+
+// integrateSphere is a function to integrate a function of the directions over the sphere.
+func integrateSphere(n int, f func(dir utils.Vec3) float64) float64 {
+	dirs := sphereDirections(n)
+	sum := 0.0
+	for _, dir := range dirs {
+		sum += f(dir)
+	}
+	return sum * 4 * math.Pi / float64(len(dirs))
+}
+
+// prepare is a function to prepare a light, failing the test on an error.
+func prepare(t *testing.T, lgt Light) Light {
+	t.Helper()
+	if err := lgt.Prepare(); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	return lgt
+}
+
+// near is a function to compare two numbers with a relative tolerance.
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance*math.Max(1, math.Abs(want))
+}
+
+// checkSamplePDF is a function to check, over n * n stratified random numbers, that the density of the samples
+// of a light is the one given by its PDF, and to get the estimate of the luminance arriving at the point.
+func checkSamplePDF(t *testing.T, lgt Light, point utils.Vec3, n int) float64 {
+	t.Helper()
+	mismatches, estimate := 0, 0.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			sample, ok := lgt.Sample(point, (float64(i)+0.5)/float64(n), (float64(j)+0.5)/float64(n))
+			if !ok {
+				continue
+			}
+			if !near(sample.Dir.Length(), 1, 1e-9) || !(sample.PDF > 0) {
+				t.Fatalf("invalid sample %+v", sample)
+			}
+			if pdf := lgt.PDF(point, sample.Dir); !near(pdf, sample.PDF, 1e-6) {
+				mismatches++
+				if mismatches <= 3 {
+					t.Errorf("sample %+v: PDF gives %g", sample, pdf)
+				}
+			}
+			estimate += luminance(sample.Radiance[:]) / sample.PDF
+		}
+	}
+	return estimate / float64(n*n)
+}
+
+// checkDelta is a function to check the density of a light of a single point or direction.
+func checkDelta(t *testing.T, lgt Light, point utils.Vec3) {
+	t.Helper()
+	if !lgt.IsDelta() {
+		t.Fatalf("%T is not delta", lgt)
+	}
+	sample, ok := lgt.Sample(point, 0.3, 0.7)
+	if !ok || sample.PDF != 1 {
+		t.Errorf("got %+v, %v, want a sample with a density of 1", sample, ok)
+	}
+	if pdf := lgt.PDF(point, sample.Dir); pdf != 0 {
+		t.Errorf("PDF gives %g, want 0", pdf)
+	}
+}
@@ -0,0 +1,493 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// PointLight is a class for lights emitting from a point or a small sphere, equally in every direction
+// or following a measured profile.
+//
+// Members:
+//  Emitter   - the color and the intensities, Color * LightIntensity is the intensity (power per solid angle),
+//              in the brightest direction of the profile.
+//  Position  - the center of the light.
+//  Radius    - radius of the sphere of the light, 0 for a single point.
+//  Profile   - the intensity in every direction, uniform when nil.
+//  Direction - the axis of the profile (vertical angle 0), down when empty.
+//  position  - the center as a Vec3.
+//  sphere    - the sphere of the light, nil for a single point.
+//  frame     - the orientation of the profile.
+//
+type PointLight struct {
+	Emitter
+	Position  []float64
+	Radius    float64
+	Profile   *Profile
+	Direction []float64
+	position  utils.Vec3
+	sphere    *shape.Sphere
+	frame     photometricFrame
+}
+
+// InitPointLight is a function to initialize a PointLight.
+//
+// Parameters:
+//  lightIntensity - the intensity of the light.
+//  color          - the rgb of the light.
+//  position       - the center of the light.
+//  radius         - radius of the sphere of the light, 0 for a single point.
+//
+// Returns:
+// 	the PointLight, not prepared.
+//
+func InitPointLight(lightIntensity float64, color, position []float64, radius float64) *PointLight {
+	return &PointLight{
+		Emitter:  Emitter{Type: TypePoint, LightIntensity: lightIntensity, Color: color},
+		Position: position,
+		Radius:   radius,
+	}
+}
+
+// Prepare is a function to check the light, build its sphere and load its profile.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color, the position, the radius, the profile or its direction is invalid.
+//
+func (lgt *PointLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	position, err := checkVector("Position", lgt.Position)
+	if err != nil {
+		return err
+	}
+	if lgt.Radius < 0 || math.IsNaN(lgt.Radius) {
+		return utils.WrapField("Radius", fmt.Errorf("invalid radius %g", lgt.Radius))
+	}
+	axis := utils.Vec3{0, -1, 0}
+	if lgt.Direction != nil {
+		if axis, err = checkDirection("Direction", lgt.Direction); err != nil {
+			return err
+		}
+	}
+	if err = prepareProfile(lgt.Profile); err != nil {
+		return err
+	}
+	lgt.position = position
+	lgt.frame = initPhotometricFrame(axis)
+	lgt.sphere = nil
+	if lgt.Radius > 0 {
+		lgt.sphere = shape.InitSphere(position, lgt.Radius, nil)
+	}
+	return nil
+}
+
+// profile is a function to get the fraction of the intensity emitted in a direction.
+//
+// Parameters:
+//  dir - the normalized direction, leaving the light.
+//
+// Returns:
+//  the value of the profile, 1 without a profile.
+//
+func (lgt *PointLight) profile(dir utils.Vec3) float64 {
+	if lgt.Profile == nil {
+		return 1
+	}
+	return lgt.Profile.evaluate(&lgt.frame, dir)
+}
+
+// sphereRadiance is a function to get the radiance of the sphere of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the intensity divided by the projected area of the sphere, so it lights as the point would.
+//
+func (lgt *PointLight) sphereRadiance() utils.Vec3 {
+	return lgt.emission().Scale(1 / (math.Pi * lgt.Radius * lgt.Radius))
+}
+
+// Sample is a function to choose a direction to the point, or uniformly inside the cone of the sphere.
+//
+// Parameters:
+//  point - the shaded point.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the sample.
+//  false if the point is the position of the light or inside its sphere.
+//
+func (lgt *PointLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	toLight := lgt.position.Sub(point)
+	dist := toLight.Length()
+	if dist <= lgt.Radius || dist == 0 {
+		return Sample{}, false
+	}
+	axis := toLight.Scale(1 / dist)
+	if lgt.sphere == nil {
+		return Sample{Dir: axis, Dist: dist, Radiance: lgt.emission().Scale(lgt.profile(axis.Neg()) / (dist * dist)), PDF: 1}, true
+	}
+	sinMax := lgt.Radius / dist
+	cosMax := math.Sqrt(math.Max(0, 1-sinMax*sinMax))
+	dir := sampleCone(axis, cosMax, u1, u2)
+	hit, found := lgt.sphere.Intersect(entity.Ray{Origin: point, Direction: dir}, 0, math.MaxFloat64)
+	if !found {
+		// grazing directions may miss by rounding
+		hit.T = math.Sqrt(math.Max(0, dist*dist-lgt.Radius*lgt.Radius))
+	}
+	return Sample{Dir: dir, Dist: hit.T, Radiance: lgt.sphereRadiance().Scale(lgt.profile(axis.Neg())), PDF: conePDF(cosMax)}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point.
+//  dir   - the direction.
+//
+// Returns:
+//  the solid angle density, 0 for a single point or if the direction misses the sphere.
+//
+func (lgt *PointLight) PDF(point, dir utils.Vec3) float64 {
+	if lgt.sphere == nil {
+		return 0
+	}
+	toLight := lgt.position.Sub(point)
+	dist := toLight.Length()
+	if dist <= lgt.Radius {
+		return 0
+	}
+	sinMax := lgt.Radius / dist
+	cosMax := math.Sqrt(math.Max(0, 1-sinMax*sinMax))
+	if dir.Normalize().Dot(toLight.Scale(1/dist)) < cosMax {
+		return 0
+	}
+	return conePDF(cosMax)
+}
+
+// Intersect is a function to intersect a ray with the sphere of the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  the t of the intersection.
+//  true if the ray hits the sphere, always false for a single point.
+//
+func (lgt *PointLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	if lgt.sphere == nil {
+		return 0, false
+	}
+	hit, found := lgt.sphere.Intersect(ray, tMin, tMax)
+	return hit.T, found
+}
+
+// Radiance is a function to get the radiance emitted by the sphere of the light.
+//
+// Parameters:
+//  dir - the direction of the ray, giving the direction of the profile.
+//
+// Returns:
+//  the radiance, black for a single point.
+//
+func (lgt *PointLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	if lgt.sphere == nil {
+		return utils.Vec3{}
+	}
+	return lgt.sphereRadiance().Scale(lgt.profile(dir.Normalize().Neg()))
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true without a radius.
+//
+func (lgt *PointLight) IsDelta() bool {
+	return lgt.Radius == 0
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *PointLight) IsInfinite() bool {
+	return false
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene, ignored.
+//
+// Returns:
+//  the intensity times 4π, or the intensity of the profile integrated over the sphere.
+//
+func (lgt *PointLight) Power(sceneRadius float64) float64 {
+	if lgt.Profile != nil {
+		return lgt.Profile.integral(func(cosTheta float64) float64 { return 1 }) * lgt.emissionLuminance()
+	}
+	return 4 * math.Pi * lgt.emissionLuminance()
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the bounds of the sphere, a point without a radius.
+//
+func (lgt *PointLight) Bounds() []float64 {
+	return pointBounds(lgt.position, lgt.Radius)
+}
+
+// SpotLight is a class for point lights emitting inside a cone, optionally following a measured profile
+// around its axis.
+//
+// Members:
+//  Emitter    - the color and the intensities, Color * LightIntensity is the intensity on the axis,
+//               or in the brightest direction of the profile.
+//  Position   - the position of the light.
+//  Direction  - the axis of the cone, towards the lit objects.
+//  InnerAngle - angle in degrees between the axis and the end of the full intensity.
+//  OuterAngle - angle in degrees between the axis and the border of the cone, the intensity
+//               falls smoothly to 0 from InnerAngle to OuterAngle.
+//  Profile    - the intensity in every direction, its vertical angle 0 on the axis, multiplied by the
+//               falloff of the cone (nil for a uniform cone).
+//  position   - the position as a Vec3.
+//  direction  - the normalized axis.
+//  cosInner   - cosine of InnerAngle.
+//  cosOuter   - cosine of OuterAngle.
+//  frame      - the orientation of the profile.
+//
+type SpotLight struct {
+	Emitter
+	Position   []float64
+	Direction  []float64
+	InnerAngle float64
+	OuterAngle float64
+	Profile    *Profile
+	position   utils.Vec3
+	direction  utils.Vec3
+	cosInner   float64
+	cosOuter   float64
+	frame      photometricFrame
+}
+
+// InitSpotLight is a function to initialize a SpotLight.
+//
+// Parameters:
+//  lightIntensity - the intensity of the light on the axis.
+//  color          - the rgb of the light.
+//  position       - the position of the light.
+//  direction      - the axis of the cone.
+//  innerAngle     - angle in degrees of the full intensity.
+//  outerAngle     - angle in degrees of the border of the cone.
+//
+// Returns:
+// 	the SpotLight, not prepared.
+//
+func InitSpotLight(lightIntensity float64, color, position, direction []float64, innerAngle, outerAngle float64) *SpotLight {
+	return &SpotLight{
+		Emitter:    Emitter{Type: TypeSpot, LightIntensity: lightIntensity, Color: color},
+		Position:   position,
+		Direction:  direction,
+		InnerAngle: innerAngle,
+		OuterAngle: outerAngle,
+	}
+}
+
+// Prepare is a function to check the light, compute the cosines of its cone and load its profile.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color, the position, the direction, an angle or the profile is invalid.
+//
+func (lgt *SpotLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	position, err := checkVector("Position", lgt.Position)
+	if err != nil {
+		return err
+	}
+	direction, err := checkDirection("Direction", lgt.Direction)
+	if err != nil {
+		return err
+	}
+	if !(lgt.OuterAngle > 0 && lgt.OuterAngle < 180) {
+		return utils.WrapField("OuterAngle", fmt.Errorf("%g outside of (0, 180) degrees", lgt.OuterAngle))
+	}
+	if !(lgt.InnerAngle >= 0 && lgt.InnerAngle <= lgt.OuterAngle) {
+		return utils.WrapField("InnerAngle", errors.New("outside of [0, OuterAngle] degrees"))
+	}
+	if err = prepareProfile(lgt.Profile); err != nil {
+		return err
+	}
+	lgt.position = position
+	lgt.direction = direction
+	lgt.frame = initPhotometricFrame(direction)
+	lgt.cosInner = math.Cos(lgt.InnerAngle * math.Pi / 180)
+	lgt.cosOuter = math.Cos(lgt.OuterAngle * math.Pi / 180)
+	return nil
+}
+
+// falloff is a function to get the fraction of the intensity emitted in a direction.
+//
+// Parameters:
+//  cosTheta - cosine of the angle between the direction and the axis.
+//
+// Returns:
+//  1 inside InnerAngle, 0 outside OuterAngle and a smooth step between them.
+//
+func (lgt *SpotLight) falloff(cosTheta float64) float64 {
+	if cosTheta >= lgt.cosInner {
+		return 1
+	}
+	if cosTheta <= lgt.cosOuter {
+		return 0
+	}
+	t := (cosTheta - lgt.cosOuter) / (lgt.cosInner - lgt.cosOuter)
+	return t * t * (3 - 2*t)
+}
+
+// Sample is a function to get the direction to the light.
+//
+// Parameters:
+//  point - the shaded point.
+//  u1    - ignored.
+//  u2    - ignored.
+//
+// Returns:
+//  the sample.
+//  false if the point is outside the cone.
+//
+func (lgt *SpotLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	toLight := lgt.position.Sub(point)
+	dist := toLight.Length()
+	if dist == 0 {
+		return Sample{}, false
+	}
+	dir := toLight.Scale(1 / dist)
+	falloff := lgt.falloff(-dir.Dot(lgt.direction))
+	if lgt.Profile != nil {
+		falloff *= lgt.Profile.evaluate(&lgt.frame, dir.Neg())
+	}
+	if falloff == 0 {
+		return Sample{}, false
+	}
+	return Sample{Dir: dir, Dist: dist, Radiance: lgt.emission().Scale(falloff / (dist * dist)), PDF: 1}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point.
+//  dir   - the direction.
+//
+// Returns:
+//  0, the light is a single point.
+//
+func (lgt *SpotLight) PDF(point, dir utils.Vec3) float64 {
+	return 0
+}
+
+// Intersect is a function to intersect a ray with the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  0 and false, a point can not be hit.
+//
+func (lgt *SpotLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	return 0, false
+}
+
+// Radiance is a function to get the radiance of a ray hitting the light.
+//
+// Parameters:
+//  dir - the direction of the ray.
+//
+// Returns:
+//  black, a point can not be hit.
+//
+func (lgt *SpotLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	return utils.Vec3{}
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true.
+//
+func (lgt *SpotLight) IsDelta() bool {
+	return true
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *SpotLight) IsInfinite() bool {
+	return false
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene, ignored.
+//
+// Returns:
+//  the intensity times the solid angle of the cone, half of the falloff counted, or the intensity
+//  of the profile integrated inside the cone.
+//
+func (lgt *SpotLight) Power(sceneRadius float64) float64 {
+	if lgt.Profile != nil {
+		return lgt.Profile.integral(lgt.falloff) * lgt.emissionLuminance()
+	}
+	return 2 * math.Pi * (1 - (lgt.cosInner+lgt.cosOuter)/2) * lgt.emissionLuminance()
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the position.
+//
+func (lgt *SpotLight) Bounds() []float64 {
+	return pointBounds(lgt.position, 0)
+}
@@ -0,0 +1,68 @@
+package light
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+func TestPointLightPDF(t *testing.T) {
+	lgt := prepare(t, InitPointLight(2, []float64{1, 1, 1}, []float64{0, 2, 0}, 0.5))
+	point := utils.Vec3{0.3, 0, 0.1}
+
+	estimate := checkSamplePDF(t, lgt, point, 32)
+	if total := integrateSphere(400, func(dir utils.Vec3) float64 { return lgt.PDF(point, dir) }); !near(total, 1, 0.01) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	// the samples are on the sphere and estimate the luminance it sends to the point
+	sample, _ := lgt.Sample(point, 0.2, 0.9)
+	if tHit, hit := lgt.Intersect(entity.Ray{Origin: point, Direction: sample.Dir}, 0, math.MaxFloat64); !hit || !near(tHit, sample.Dist, 1e-6) {
+		t.Errorf("sample %+v: hit %v at %g", sample, hit, tHit)
+	}
+	want := integrateSphere(400, func(dir utils.Vec3) float64 {
+		if _, hit := lgt.Intersect(entity.Ray{Origin: point, Direction: dir}, 0, math.MaxFloat64); !hit {
+			return 0
+		}
+		radiance := lgt.Radiance(dir)
+		return luminance(radiance[:])
+	})
+	if !near(estimate, want, 0.01) {
+		t.Errorf("the samples estimate %g, want %g", estimate, want)
+	}
+
+	// inside the sphere nothing is sampled
+	if _, ok := lgt.Sample(utils.Vec3{0, 2.2, 0}, 0.5, 0.5); ok {
+		t.Error("sampled from inside the sphere")
+	}
+	if pdf := lgt.PDF(utils.Vec3{0, 2.2, 0}, utils.Vec3{0, 1, 0}); pdf != 0 {
+		t.Errorf("PDF inside the sphere gives %g, want 0", pdf)
+	}
+}
+
+func TestPointLightDelta(t *testing.T) {
+	lgt := prepare(t, InitPointLight(2, []float64{1, 1, 1}, []float64{0, 2, 0}, 0))
+	checkDelta(t, lgt, utils.Vec3{0, 0, 0})
+	// the intensity falls with the square of the distance
+	sample, _ := lgt.Sample(utils.Vec3{0, 0, 0}, 0.5, 0.5)
+	if sample.Dir != (utils.Vec3{0, 1, 0}) || sample.Dist != 2 || !near(sample.Radiance[0], 0.5, 1e-12) {
+		t.Errorf("got %+v", sample)
+	}
+}
+
+func TestSpotLightPDF(t *testing.T) {
+	lgt := prepare(t, InitSpotLight(1, []float64{1, 1, 1}, []float64{0, 2, 0}, []float64{0, -1, 0}, 20, 30))
+	checkDelta(t, lgt, utils.Vec3{0, 0, 0})
+
+	// 45 degrees from the axis, outside the cone
+	if sample, ok := lgt.Sample(utils.Vec3{2, 0, 0}, 0.5, 0.5); ok {
+		t.Errorf("got %+v outside the cone", sample)
+	}
+	// between the inner and the outer angles the intensity falls
+	inner, _ := lgt.Sample(utils.Vec3{0.2, 0, 0}, 0.5, 0.5)
+	border, ok := lgt.Sample(utils.Vec3{2 * math.Tan(25*math.Pi/180), 0, 0}, 0.5, 0.5)
+	if !ok || !(border.Radiance[0]*border.Dist*border.Dist < inner.Radiance[0]*inner.Dist*inner.Dist) {
+		t.Errorf("got %+v, %v at the border and %+v inside", border, ok, inner)
+	}
+}
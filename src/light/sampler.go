@@ -0,0 +1,405 @@
+package light
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// The strategies choosing the light sampled at a point.
+const (
+	SamplerUniform = "uniform"
+	SamplerPower   = "power"
+	SamplerBVH     = "bvh"
+)
+
+// minLightDistance2 is the smallest squared distance to a light node, so points near a point light do not
+// take all the probability.
+const minLightDistance2 = 1e-8
+
+// Sampler is an interface for the strategies choosing the light sampled for the direct light of a point.
+//
+// Methods:
+//  Sample - chooses a light, nil when no light can light the point, and its probability.
+//  PMF    - the probability of Sample choosing a light.
+//
+type Sampler interface {
+	Sample(point, normal utils.Vec3, u float64) (Light, float64)
+	PMF(point, normal utils.Vec3, lgt Light) float64
+}
+
+// InitSampler is a function to initialize a Sampler.
+//
+// Parameters:
+//  kind        - the strategy: SamplerUniform, SamplerPower or SamplerBVH, SamplerPower when empty.
+//  lights      - the prepared lights.
+//  sceneRadius - radius of the bounding sphere of the scene, giving the power of the lights at infinity.
+//
+// Returns:
+//  the Sampler.
+//  an error if the strategy is unknown.
+//
+func InitSampler(kind string, lights []Light, sceneRadius float64) (Sampler, error) {
+	switch kind {
+	case SamplerUniform:
+		return &UniformSampler{lights: lights}, nil
+	case "", SamplerPower:
+		return initPowerSampler(lights, sceneRadius), nil
+	case SamplerBVH:
+		return initBVHSampler(lights, sceneRadius), nil
+	}
+	return nil, fmt.Errorf("unknown light sampler %q", kind)
+}
+
+// UniformSampler is a class for choosing every light with the same probability.
+//
+// Members:
+//  lights - the lights.
+//
+type UniformSampler struct {
+	lights []Light
+}
+
+// Sample is a function to choose a light.
+//
+// Parameters:
+//  point  - the shaded point, ignored.
+//  normal - the normal at the point, ignored.
+//  u      - random number in [0, 1).
+//
+// Returns:
+//  the light, nil without lights.
+//  1 / number of lights.
+//
+func (sampler *UniformSampler) Sample(point, normal utils.Vec3, u float64) (Light, float64) {
+	if len(sampler.lights) == 0 {
+		return nil, 0
+	}
+	return sampler.lights[clampIndex(int(u*float64(len(sampler.lights))), len(sampler.lights))], 1 / float64(len(sampler.lights))
+}
+
+// PMF is a function to get the probability of Sample choosing a light.
+//
+// Parameters:
+//  point  - the shaded point, ignored.
+//  normal - the normal at the point, ignored.
+//  lgt    - the light.
+//
+// Returns:
+//  1 / number of lights.
+//
+func (sampler *UniformSampler) PMF(point, normal utils.Vec3, lgt Light) float64 {
+	if len(sampler.lights) == 0 {
+		return 0
+	}
+	return 1 / float64(len(sampler.lights))
+}
+
+// PowerSampler is a class for choosing the lights proportionally to their power.
+//
+// Members:
+//  lights  - the lights.
+//  table   - the alias table of the powers.
+//  indices - the index of every light.
+//
+type PowerSampler struct {
+	lights  []Light
+	table   aliasTable
+	indices map[Light]int
+}
+
+// initPowerSampler is a function to initialize a PowerSampler.
+//
+// Parameters:
+//  lights      - the prepared lights.
+//  sceneRadius - radius of the bounding sphere of the scene.
+//
+// Returns:
+//  the PowerSampler, uniform when no light emits.
+//
+func initPowerSampler(lights []Light, sceneRadius float64) *PowerSampler {
+	powers := make([]float64, len(lights))
+	indices := make(map[Light]int, len(lights))
+	for i, lgt := range lights {
+		powers[i] = math.Max(0, lgt.Power(sceneRadius))
+		indices[lgt] = i
+	}
+	return &PowerSampler{lights: lights, table: initAliasTable(powers), indices: indices}
+}
+
+// Sample is a function to choose a light.
+//
+// Parameters:
+//  point  - the shaded point, ignored.
+//  normal - the normal at the point, ignored.
+//  u      - random number in [0, 1).
+//
+// Returns:
+//  the light, nil without lights.
+//  its probability.
+//
+func (sampler *PowerSampler) Sample(point, normal utils.Vec3, u float64) (Light, float64) {
+	if len(sampler.lights) == 0 {
+		return nil, 0
+	}
+	idx := sampler.table.sample(u)
+	return sampler.lights[idx], sampler.table.pmf[idx]
+}
+
+// PMF is a function to get the probability of Sample choosing a light.
+//
+// Parameters:
+//  point  - the shaded point, ignored.
+//  normal - the normal at the point, ignored.
+//  lgt    - the light.
+//
+// Returns:
+//  the power of the light over the total power, 0 for unknown lights.
+//
+func (sampler *PowerSampler) PMF(point, normal utils.Vec3, lgt Light) float64 {
+	idx, ok := sampler.indices[lgt]
+	if !ok {
+		return 0
+	}
+	return sampler.table.pmf[idx]
+}
+
+// lightNode is a class for a node of the hierarchy of the lights of a BVHSampler.
+//
+// Members:
+//  bounds - box around all lights of the node.
+//  power  - the sum of the powers of the lights of the node.
+//  left   - first child, nil on leaves.
+//  right  - second child, nil on leaves.
+//  parent - the parent node, nil on the root.
+//  light  - the light of a leaf.
+//
+type lightNode struct {
+	bounds []float64
+	power  float64
+	left   *lightNode
+	right  *lightNode
+	parent *lightNode
+	light  Light
+}
+
+// BVHSampler is a class for choosing the lights through a hierarchy of their boxes, going down to the
+// children by their power over the squared distance and skipping the boxes behind the shaded surface.
+// The lights at infinity are chosen uniformly, each one as likely as the whole hierarchy.
+//
+// Members:
+//  root     - the root of the hierarchy of the bounded lights, nil without them.
+//  leaves   - the leaf of every bounded light.
+//  infinite - the lights at infinity.
+//
+type BVHSampler struct {
+	root     *lightNode
+	leaves   map[Light]*lightNode
+	infinite []Light
+}
+
+// initBVHSampler is a function to initialize a BVHSampler.
+//
+// Parameters:
+//  lights      - the prepared lights.
+//  sceneRadius - radius of the bounding sphere of the scene.
+//
+// Returns:
+//  the BVHSampler.
+//
+func initBVHSampler(lights []Light, sceneRadius float64) *BVHSampler {
+	sampler := &BVHSampler{leaves: make(map[Light]*lightNode)}
+	leaves := make([]*lightNode, 0, len(lights))
+	for _, lgt := range lights {
+		if lgt.IsInfinite() {
+			sampler.infinite = append(sampler.infinite, lgt)
+			continue
+		}
+		leaf := &lightNode{bounds: lgt.Bounds(), power: math.Max(0, lgt.Power(sceneRadius)), light: lgt}
+		sampler.leaves[lgt] = leaf
+		leaves = append(leaves, leaf)
+	}
+	if len(leaves) > 0 {
+		sampler.root = buildLightBVH(leaves)
+	}
+	return sampler
+}
+
+// buildLightBVH is a function to build a node splitting the lights at the median of the longest axis.
+//
+// Parameters:
+//  leaves - the leaves of the lights.
+//
+// Returns:
+//  the node.
+//
+func buildLightBVH(leaves []*lightNode) *lightNode {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	bounds := append([]float64{}, leaves[0].bounds...)
+	for _, leaf := range leaves[1:] {
+		for j := 0; j < 3; j++ {
+			bounds[j] = math.Min(bounds[j], leaf.bounds[j])
+			bounds[j+3] = math.Max(bounds[j+3], leaf.bounds[j+3])
+		}
+	}
+	axis := 0
+	for j := 1; j < 3; j++ {
+		if bounds[j+3]-bounds[j] > bounds[axis+3]-bounds[axis] {
+			axis = j
+		}
+	}
+	sort.Slice(leaves, func(a, b int) bool {
+		return leaves[a].bounds[axis]+leaves[a].bounds[axis+3] < leaves[b].bounds[axis]+leaves[b].bounds[axis+3]
+	})
+	half := len(leaves) / 2
+	node := &lightNode{bounds: bounds, left: buildLightBVH(leaves[:half]), right: buildLightBVH(leaves[half:])}
+	node.power = node.left.power + node.right.power
+	node.left.parent = node
+	node.right.parent = node
+	return node
+}
+
+// importance is a function to estimate the light a node sends to a point.
+//
+// Parameters:
+//  point  - the shaded point.
+//  normal - the normal at the point, not used when zero.
+//
+// Returns:
+//  the power over the squared distance to the center, 0 when the box is behind the surface.
+//
+func (node *lightNode) importance(point, normal utils.Vec3) float64 {
+	if node.power == 0 {
+		return 0
+	}
+	if normal != (utils.Vec3{}) {
+		facing := false
+		for corner := 0; corner < 8 && !facing; corner++ {
+			c := utils.Vec3{node.bounds[3*(corner&1)], node.bounds[1+3*(corner>>1&1)], node.bounds[2+3*(corner>>2&1)]}
+			facing = c.Sub(point).Dot(normal) > 0
+		}
+		if !facing {
+			return 0
+		}
+	}
+	center := utils.Vec3{
+		(node.bounds[0] + node.bounds[3]) / 2,
+		(node.bounds[1] + node.bounds[4]) / 2,
+		(node.bounds[2] + node.bounds[5]) / 2,
+	}
+	halfSize := utils.Vec3{node.bounds[3] - center[0], node.bounds[4] - center[1], node.bounds[5] - center[2]}
+	// the points inside the box are as close as its half diagonal
+	toCenter := center.Sub(point)
+	dist2 := math.Max(toCenter.Dot(toCenter), math.Max(halfSize.Dot(halfSize), minLightDistance2))
+	return node.power / dist2
+}
+
+// infiniteProbability is a function to get the probability of choosing a light at infinity.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the number of lights at infinity over that number plus 1 for the hierarchy.
+//
+func (sampler *BVHSampler) infiniteProbability() float64 {
+	if sampler.root == nil {
+		if len(sampler.infinite) == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(len(sampler.infinite)) / float64(len(sampler.infinite)+1)
+}
+
+// Sample is a function to choose a light.
+//
+// Parameters:
+//  point  - the shaded point.
+//  normal - the normal at the point, zero when unknown.
+//  u      - random number in [0, 1).
+//
+// Returns:
+//  the light, nil when no bounded light is in front of the point and no light at infinity was chosen.
+//  its probability.
+//
+func (sampler *BVHSampler) Sample(point, normal utils.Vec3, u float64) (Light, float64) {
+	pInfinite := sampler.infiniteProbability()
+	if u < pInfinite {
+		idx := clampIndex(int(u/pInfinite*float64(len(sampler.infinite))), len(sampler.infinite))
+		return sampler.infinite[idx], pInfinite / float64(len(sampler.infinite))
+	}
+	if sampler.root == nil {
+		return nil, 0
+	}
+	// reusing the random number at every level
+	u = math.Min((u-pInfinite)/(1-pInfinite), math.Nextafter(1, 0))
+	pmf := 1 - pInfinite
+	node := sampler.root
+	if node.importance(point, normal) == 0 {
+		return nil, 0
+	}
+	for node.light == nil {
+		left := node.left.importance(point, normal)
+		right := node.right.importance(point, normal)
+		if left+right == 0 {
+			return nil, 0
+		}
+		pLeft := left / (left + right)
+		if u < pLeft {
+			u /= pLeft
+			pmf *= pLeft
+			node = node.left
+		} else {
+			u = (u - pLeft) / (1 - pLeft)
+			pmf *= 1 - pLeft
+			node = node.right
+		}
+		u = math.Min(u, math.Nextafter(1, 0))
+	}
+	return node.light, pmf
+}
+
+// PMF is a function to get the probability of Sample choosing a light.
+//
+// Parameters:
+//  point  - the shaded point.
+//  normal - the normal at the point, zero when unknown.
+//  lgt    - the light.
+//
+// Returns:
+//  the product of the probabilities of the nodes above the light, 0 for unknown lights.
+//
+func (sampler *BVHSampler) PMF(point, normal utils.Vec3, lgt Light) float64 {
+	pInfinite := sampler.infiniteProbability()
+	if lgt.IsInfinite() {
+		for _, other := range sampler.infinite {
+			if other == lgt {
+				return pInfinite / float64(len(sampler.infinite))
+			}
+		}
+		return 0
+	}
+	node, ok := sampler.leaves[lgt]
+	if !ok || sampler.root.importance(point, normal) == 0 {
+		return 0
+	}
+	pmf := 1 - pInfinite
+	for ; node.parent != nil; node = node.parent {
+		left := node.parent.left.importance(point, normal)
+		right := node.parent.right.importance(point, normal)
+		if left+right == 0 {
+			return 0
+		}
+		if node == node.parent.left {
+			pmf *= left / (left + right)
+		} else {
+			pmf *= right / (left + right)
+		}
+	}
+	return pmf
+}
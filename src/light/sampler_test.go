@@ -0,0 +1,119 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// samplerLights is a function to create prepared point lights of different powers and a directional light.
+func samplerLights(t *testing.T) []Light {
+	t.Helper()
+	lights := []Light{
+		InitPointLight(1, []float64{1, 1, 1}, []float64{-3, 2, 0}, 0),
+		InitPointLight(4, []float64{1, 1, 1}, []float64{-1, 2, 0}, 0.2),
+		InitPointLight(2, []float64{1, 0, 0}, []float64{1, 2, 0}, 0),
+		InitPointLight(8, []float64{0, 1, 1}, []float64{3, -2, 0}, 0),
+		InitDirectionalLight(0.5, []float64{1, 1, 1}, []float64{0, -1, 0}, 0),
+	}
+	for _, lgt := range lights {
+		prepare(t, lgt)
+	}
+	return lights
+}
+
+// checkSampler is a function to check, over n stratified random numbers, that a sampler chooses the lights
+// with the probabilities given by its PMF, and to get those probabilities.
+func checkSampler(t *testing.T, sampler Sampler, lights []Light, point, normal utils.Vec3, n int) []float64 {
+	t.Helper()
+	pmfs := make([]float64, len(lights))
+	total := 0.0
+	for i, lgt := range lights {
+		pmfs[i] = sampler.PMF(point, normal, lgt)
+		total += pmfs[i]
+	}
+	if !near(total, 1, 1e-12) {
+		t.Errorf("the probabilities %v sum to %g, want 1", pmfs, total)
+	}
+	counts := make(map[Light]int)
+	for i := 0; i < n; i++ {
+		lgt, pmf := sampler.Sample(point, normal, (float64(i)+0.5)/float64(n))
+		if lgt == nil {
+			t.Fatalf("no light chosen with %v", pmfs)
+		}
+		if want := sampler.PMF(point, normal, lgt); !near(pmf, want, 1e-12) {
+			t.Fatalf("chose %T with a probability of %g, PMF gives %g", lgt, pmf, want)
+		}
+		counts[lgt]++
+	}
+	for i, lgt := range lights {
+		if got := float64(counts[lgt]) / float64(n); !near(got, pmfs[i], 0.01) {
+			t.Errorf("light %d chosen %g of the times, want %g", i, got, pmfs[i])
+		}
+	}
+	return pmfs
+}
+
+func TestUniformSampler(t *testing.T) {
+	lights := samplerLights(t)
+	sampler, _ := InitSampler(SamplerUniform, lights, 10)
+	for _, pmf := range checkSampler(t, sampler, lights, utils.Vec3{}, utils.Vec3{0, 1, 0}, 1000) {
+		if !near(pmf, 0.2, 1e-12) {
+			t.Errorf("got %g, want 0.2", pmf)
+		}
+	}
+}
+
+func TestPowerSampler(t *testing.T) {
+	lights := samplerLights(t)
+	sampler, _ := InitSampler("", lights, 10)
+	pmfs := checkSampler(t, sampler, lights, utils.Vec3{}, utils.Vec3{0, 1, 0}, 1000)
+	total := 0.0
+	for _, lgt := range lights {
+		total += lgt.Power(10)
+	}
+	for i, lgt := range lights {
+		if want := lgt.Power(10) / total; !near(pmfs[i], want, 1e-12) {
+			t.Errorf("light %d: got %g, want %g", i, pmfs[i], want)
+		}
+	}
+}
+
+func TestBVHSampler(t *testing.T) {
+	lights := samplerLights(t)
+	sampler, _ := InitSampler(SamplerBVH, lights, 10)
+	for _, normal := range []utils.Vec3{{}, {0, 1, 0}, {0, -1, 0}} {
+		pmfs := checkSampler(t, sampler, lights, utils.Vec3{0.5, 0, 0.5}, normal, 1000)
+		// the directional light is as likely as the hierarchy
+		if !near(pmfs[4], 0.5, 1e-12) {
+			t.Errorf("normal %v: the directional light has a probability of %g, want 0.5", normal, pmfs[4])
+		}
+		if normal[1] > 0 && pmfs[3] != 0 {
+			t.Errorf("the light behind the surface has a probability of %g", pmfs[3])
+		}
+	}
+	// the closest light of the same power is more likely
+	if pmfs := checkSampler(t, sampler, lights, utils.Vec3{-3, 1.5, 0}, utils.Vec3{}, 1000); !(pmfs[0] > pmfs[2]) {
+		t.Errorf("got %v, want the first light more likely than the third", pmfs)
+	}
+}
+
+func TestSamplerUnknownLight(t *testing.T) {
+	lights := samplerLights(t)
+	other := prepare(t, InitPointLight(1, []float64{1, 1, 1}, []float64{0, 1, 0}, 0))
+	for _, kind := range []string{SamplerPower, SamplerBVH} {
+		sampler, _ := InitSampler(kind, lights, 10)
+		if pmf := sampler.PMF(utils.Vec3{}, utils.Vec3{}, other); pmf != 0 {
+			t.Errorf("%s: got %g for an unknown light, want 0", kind, pmf)
+		}
+	}
+	for _, kind := range []string{SamplerUniform, SamplerPower, SamplerBVH} {
+		sampler, _ := InitSampler(kind, nil, 10)
+		if lgt, pmf := sampler.Sample(utils.Vec3{}, utils.Vec3{}, 0.5); lgt != nil || pmf != 0 {
+			t.Errorf("%s: got %T with %g without lights", kind, lgt, pmf)
+		}
+	}
+	if _, err := InitSampler("random", lights, 10); err == nil {
+		t.Error("got no error for an unknown sampler")
+	}
+}
@@ -0,0 +1,387 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Constants of the sky.
+const (
+	// SunAngularDiameter is the apparent diameter of the sun in degrees.
+	SunAngularDiameter = 0.53
+	// skyWidth and skyHeight are the size of the table of the sky used to sample it.
+	skyWidth  = 64
+	skyHeight = 32
+)
+
+// SkyLight is a class for the daylight sky of the Preetham model (A Practical Analytic Model for Daylight,
+// 1999), with the sun as a directional light of the same direction.
+// The azimuth starts at -z and turns towards +x, as the columns of an EnvironmentLight.
+//
+// Members:
+//  Emitter      - the color and the intensities, Color * LightIntensity multiplies the luminance of the sky,
+//                 given in kcd/m².
+//  Elevation    - angle in degrees between the horizon and the sun, in [0, 90].
+//  Azimuth      - angle in degrees around the y axis of the sun.
+//  Turbidity    - haziness of the air, from 2 (clear) to 10 (hazy).
+//  SunIntensity - irradiance of the sun on top of the atmosphere, 0 for a sky without the sun.
+//  sun          - the sun, colored by the light it loses through the atmosphere.
+//  toSun        - the normalized direction of the sun.
+//  zenith       - the luminance (Y) and chromaticity (x, y) at the zenith.
+//  perez        - the coefficients of the distribution of Y, x and y over the sky.
+//  perezSun     - the distribution of Y, x and y at the zenith, dividing the others.
+//  mapping      - the directions of the table of the sky, sampled following its luminance.
+//
+type SkyLight struct {
+	Emitter
+	Elevation    float64
+	Azimuth      float64
+	Turbidity    float64
+	SunIntensity float64
+	sun          *DirectionalLight
+	toSun        utils.Vec3
+	zenith       [3]float64
+	perez        [3][5]float64
+	perezSun     [3]float64
+	mapping      equirectangular
+}
+
+// InitSkyLight is a function to initialize a SkyLight.
+//
+// Parameters:
+//  lightIntensity - the intensity multiplying the sky.
+//  color          - the rgb multiplying the sky.
+//  elevation      - angle in degrees between the horizon and the sun.
+//  azimuth        - angle in degrees around the y axis of the sun.
+//  turbidity      - haziness of the air.
+//  sunIntensity   - irradiance of the sun on top of the atmosphere.
+//
+// Returns:
+// 	the SkyLight, not prepared.
+//
+func InitSkyLight(lightIntensity float64, color []float64, elevation, azimuth, turbidity, sunIntensity float64) *SkyLight {
+	return &SkyLight{
+		Emitter:      Emitter{Type: TypeSky, LightIntensity: lightIntensity, Color: color},
+		Elevation:    elevation,
+		Azimuth:      azimuth,
+		Turbidity:    turbidity,
+		SunIntensity: sunIntensity,
+	}
+}
+
+// SunDirection is a function to get the direction of the sun given its angles.
+//
+// Parameters:
+//  elevation - angle in degrees between the horizon and the sun.
+//  azimuth   - angle in degrees around the y axis, from -z towards +x.
+//
+// Returns:
+//  the normalized direction from the scene to the sun.
+//
+func SunDirection(elevation, azimuth float64) utils.Vec3 {
+	elevation *= math.Pi / 180
+	azimuth *= math.Pi / 180
+	cosElevation := math.Cos(elevation)
+	return utils.Vec3{cosElevation * math.Sin(azimuth), math.Sin(elevation), -cosElevation * math.Cos(azimuth)}
+}
+
+// Prepare is a function to check the light and compute the coefficients of the sky and the sun.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  a *utils.FieldError if the color, an angle, the turbidity or the intensity of the sun is invalid.
+//
+func (lgt *SkyLight) Prepare() error {
+	if err := lgt.checkEmitter(); err != nil {
+		return err
+	}
+	if !(lgt.Elevation >= 0 && lgt.Elevation <= 90) {
+		return utils.WrapField("Elevation", fmt.Errorf("%g outside of [0, 90] degrees", lgt.Elevation))
+	}
+	if math.IsNaN(lgt.Azimuth) || math.IsInf(lgt.Azimuth, 0) {
+		return utils.WrapField("Azimuth", fmt.Errorf("invalid azimuth %g", lgt.Azimuth))
+	}
+	if !(lgt.Turbidity >= 1.7 && lgt.Turbidity <= 10) {
+		return utils.WrapField("Turbidity", fmt.Errorf("%g outside of [1.7, 10]", lgt.Turbidity))
+	}
+	if lgt.SunIntensity < 0 || math.IsNaN(lgt.SunIntensity) {
+		return utils.WrapField("SunIntensity", errors.New("invalid intensity"))
+	}
+	lgt.toSun = SunDirection(lgt.Elevation, lgt.Azimuth)
+	thetaSun := (90 - lgt.Elevation) * math.Pi / 180
+	t := lgt.Turbidity
+
+	lgt.perez = [3][5]float64{
+		{0.1787*t - 1.4630, -0.3554*t + 0.4275, -0.0227*t + 5.3251, 0.1206*t - 2.5771, -0.0670*t + 0.3703},
+		{-0.0193*t - 0.2592, -0.0665*t + 0.0008, -0.0004*t + 0.2125, -0.0641*t - 0.8989, -0.0033*t + 0.0452},
+		{-0.0167*t - 0.2608, -0.0950*t + 0.0092, -0.0079*t + 0.2102, -0.0441*t - 1.6537, -0.0109*t + 0.0529},
+	}
+	chi := (4.0/9 - t/120) * (math.Pi - 2*thetaSun)
+	theta2 := thetaSun * thetaSun
+	theta3 := theta2 * thetaSun
+	lgt.zenith = [3]float64{
+		(4.0453*t-4.9710)*math.Tan(chi) - 0.2155*t + 2.4192,
+		t*t*(0.00166*theta3-0.00375*theta2+0.00209*thetaSun) +
+			t*(-0.02903*theta3+0.06377*theta2-0.03202*thetaSun+0.00394) +
+			(0.11693*theta3 - 0.21196*theta2 + 0.06052*thetaSun + 0.25886),
+		t*t*(0.00275*theta3-0.00610*theta2+0.00317*thetaSun) +
+			t*(-0.04214*theta3+0.08970*theta2-0.04153*thetaSun+0.00516) +
+			(0.15346*theta3 - 0.26756*theta2 + 0.06670*thetaSun + 0.26688),
+	}
+	for i := range lgt.perezSun {
+		lgt.perezSun[i] = perezFunction(lgt.perez[i], 1, math.Cos(thetaSun), thetaSun)
+	}
+
+	lgt.sun = nil
+	if lgt.SunIntensity > 0 {
+		direction := lgt.toSun.Neg()
+		lgt.sun = InitDirectionalLight(lgt.SunIntensity, sunTransmittance(thetaSun, t), direction[:], SunAngularDiameter)
+		if err := lgt.sun.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// the table of the sky, only used to choose the directions
+	mapping := equirectangular{cosRotation: 1}
+	table := make([][]float64, skyHeight)
+	for y := range table {
+		table[y] = make([]float64, skyWidth)
+		for x := range table[y] {
+			dir, _ := mapping.fromImage((float64(x)+0.5)/skyWidth, (float64(y)+0.5)/skyHeight)
+			rgb := lgt.skyRadiance(dir)
+			table[y][x] = math.Max(0, luminance(rgb[:]))
+		}
+	}
+	lgt.mapping = initEquirectangular(0, skyWidth, skyHeight, func(x, y int) float64 {
+		return table[y][x]
+	})
+	return nil
+}
+
+// perezFunction is a function to get the distribution of the Perez model.
+//
+// Parameters:
+//  coefficients - the coefficients A to E.
+//  cosTheta     - cosine of the angle between the direction and the zenith.
+//  cosGamma     - cosine of the angle between the direction and the sun.
+//  gamma        - the angle between the direction and the sun.
+//
+// Returns:
+//  the relative value.
+//
+func perezFunction(coefficients [5]float64, cosTheta, cosGamma, gamma float64) float64 {
+	a, b, c, d, e := coefficients[0], coefficients[1], coefficients[2], coefficients[3], coefficients[4]
+	return (1 + a*math.Exp(b/cosTheta)) * (1 + c*math.Exp(d*gamma) + e*cosGamma*cosGamma)
+}
+
+// skyRadiance is a function to get the radiance of the sky, without the sun.
+// The directions below the horizon get the sky mirrored by the horizon.
+//
+// Parameters:
+//  dir - the direction.
+//
+// Returns:
+//  the linear rgb.
+//
+func (lgt *SkyLight) skyRadiance(dir utils.Vec3) utils.Vec3 {
+	dir = dir.Normalize()
+	if dir[1] < 0 {
+		dir[1] = -dir[1]
+	}
+	cosTheta := math.Max(dir[1], 1e-3)
+	cosGamma := math.Max(-1, math.Min(1, dir.Dot(lgt.toSun)))
+	gamma := math.Acos(cosGamma)
+	var xyY [3]float64
+	for i := range xyY {
+		xyY[i] = lgt.zenith[i] * perezFunction(lgt.perez[i], cosTheta, cosGamma, gamma) / lgt.perezSun[i]
+	}
+	// the order of the coefficients is Y, x, y
+	bigY, x, y := xyY[0], xyY[1], xyY[2]
+	bigX := x / y * bigY
+	bigZ := (1 - x - y) / y * bigY
+	rgb := utils.Vec3{
+		3.2406*bigX - 1.5372*bigY - 0.4986*bigZ,
+		-0.9689*bigX + 1.8758*bigY + 0.0415*bigZ,
+		0.0557*bigX - 0.2040*bigY + 1.0570*bigZ,
+	}
+	emission := lgt.emission()
+	for i := range rgb {
+		rgb[i] = math.Max(0, rgb[i]) * emission[i]
+	}
+	return rgb
+}
+
+// sunTransmittance is a function to get the fraction of the light of the sun crossing the atmosphere,
+// from the Rayleigh and aerosol scattering of the Preetham model at the wavelengths of red, green and blue.
+//
+// Parameters:
+//  thetaSun  - angle between the sun and the zenith.
+//  turbidity - haziness of the air.
+//
+// Returns:
+//  the rgb transmittance.
+//
+func sunTransmittance(thetaSun, turbidity float64) []float64 {
+	// relative optical mass of the air
+	mass := 1 / (math.Cos(thetaSun) + 0.15*math.Pow(93.885-thetaSun*180/math.Pi, -1.253))
+	beta := 0.04608*turbidity - 0.04586
+	wavelengths := []float64{0.65, 0.57, 0.475}
+	rgb := make([]float64, 3)
+	for i, lambda := range wavelengths {
+		rayleigh := math.Exp(-0.008735 * math.Pow(lambda, -4.08) * mass)
+		aerosol := math.Exp(-beta * math.Pow(lambda, -1.3) * mass)
+		rgb[i] = rayleigh * aerosol
+	}
+	return rgb
+}
+
+// sunProbability is a function to get the probability of Sample choosing the sun.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  0.5, or 0 without the sun.
+//
+func (lgt *SkyLight) sunProbability() float64 {
+	if lgt.sun == nil {
+		return 0
+	}
+	return 0.5
+}
+
+// Sample is a function to choose a direction of the sun or of the sky, following its brightness.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the sample, at an infinite distance.
+//  false if the chosen direction has density 0.
+//
+func (lgt *SkyLight) Sample(point utils.Vec3, u1, u2 float64) (Sample, bool) {
+	var dir utils.Vec3
+	if pSun := lgt.sunProbability(); u1 < pSun {
+		sample, _ := lgt.sun.Sample(point, u1/pSun, u2)
+		dir = sample.Dir
+	} else {
+		var ok bool
+		dir, _, ok = lgt.mapping.sample((u1-pSun)/(1-pSun), u2)
+		if !ok {
+			return Sample{}, false
+		}
+	}
+	pdf := lgt.PDF(point, dir)
+	if pdf == 0 {
+		return Sample{}, false
+	}
+	return Sample{Dir: dir, Dist: math.Inf(1), Radiance: lgt.Radiance(dir), PDF: pdf}, true
+}
+
+// PDF is a function to get the density of Sample choosing a direction.
+//
+// Parameters:
+//  point - the shaded point, ignored.
+//  dir   - the direction.
+//
+// Returns:
+//  the solid angle density, combining the sun and the sky.
+//
+func (lgt *SkyLight) PDF(point, dir utils.Vec3) float64 {
+	pSun := lgt.sunProbability()
+	pdf := (1 - pSun) * lgt.mapping.pdf(dir)
+	if lgt.sun != nil {
+		pdf += pSun * lgt.sun.PDF(point, dir)
+	}
+	return pdf
+}
+
+// Intersect is a function to intersect a ray with the light.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  0 and false, the light is only reached by escaping rays.
+//
+func (lgt *SkyLight) Intersect(ray entity.Ray, tMin, tMax float64) (float64, bool) {
+	return 0, false
+}
+
+// Radiance is a function to get the radiance of a ray escaping the scene.
+//
+// Parameters:
+//  dir - the direction of the ray.
+//
+// Returns:
+//  the radiance of the sky plus the radiance of the sun if the direction is inside its disk.
+//
+func (lgt *SkyLight) Radiance(dir utils.Vec3) utils.Vec3 {
+	radiance := lgt.skyRadiance(dir)
+	if lgt.sun != nil {
+		radiance = radiance.Add(lgt.sun.Radiance(dir))
+	}
+	return radiance
+}
+
+// IsDelta is a function to tell if the light is a single point or direction.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  false.
+//
+func (lgt *SkyLight) IsDelta() bool {
+	return false
+}
+
+// IsInfinite is a function to tell if the light is hit by rays escaping the scene.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true.
+//
+func (lgt *SkyLight) IsInfinite() bool {
+	return true
+}
+
+// Power is a function to get the luminance of the power emitted by the light.
+//
+// Parameters:
+//  sceneRadius - radius of the bounding sphere of the scene.
+//
+// Returns:
+//  the radiance of the sky integrated over the sphere times the area of a disk of the scene, plus the power of the sun.
+//
+func (lgt *SkyLight) Power(sceneRadius float64) float64 {
+	power := math.Pi * sceneRadius * sceneRadius * lgt.mapping.integral()
+	if lgt.sun != nil {
+		power += lgt.sun.Power(sceneRadius)
+	}
+	return power
+}
+
+// Bounds is a function to get the bounding box of the light.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the infinite bounds.
+//
+func (lgt *SkyLight) Bounds() []float64 {
+	return infiniteBounds()
+}
@@ -0,0 +1,52 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// skyLuminance is a function to integrate the luminance of the sky over the sphere.
+func skyLuminance(lgt *SkyLight) float64 {
+	return integrateSphere(400, func(dir utils.Vec3) float64 {
+		radiance := lgt.skyRadiance(dir)
+		return luminance(radiance[:])
+	})
+}
+
+func TestSkyLightPDF(t *testing.T) {
+	lgt := InitSkyLight(1, []float64{1, 1, 1}, 35, 60, 3, 0)
+	prepare(t, lgt)
+	point := utils.Vec3{1, 2, 3}
+
+	estimate := checkSamplePDF(t, lgt, point, 128)
+	if total := integrateSphere(400, func(dir utils.Vec3) float64 { return lgt.PDF(point, dir) }); !near(total, 1, 0.01) {
+		t.Errorf("the density integrates to %g, want 1", total)
+	}
+	if want := skyLuminance(lgt); !near(estimate, want, 0.03) {
+		t.Errorf("the samples estimate %g, want %g", estimate, want)
+	}
+	// the sky around the sun is sampled more often than the sky opposite to it
+	toSun := SunDirection(35, 60)
+	if lgt.PDF(point, toSun) <= lgt.PDF(point, utils.Vec3{-toSun[0], toSun[1], -toSun[2]}) {
+		t.Error("the sky around the sun is not sampled more often")
+	}
+}
+
+func TestSkyLightSunPDF(t *testing.T) {
+	lgt := InitSkyLight(1, []float64{1, 1, 1}, 35, 60, 3, 100)
+	prepare(t, lgt)
+	point := utils.Vec3{1, 2, 3}
+
+	estimate := checkSamplePDF(t, lgt, point, 128)
+	// half of the samples go to the disk of the sun
+	sun, ok := lgt.Sample(point, 0.25, 0.5)
+	if !ok || sun.Dir.Dot(lgt.toSun) < lgt.sun.cosMax {
+		t.Errorf("got %+v, %v, want a direction of the sun", sun, ok)
+	}
+	// the disk of the sun is too small for integrateSphere, its irradiance is added to the sky
+	sunEmission := lgt.sun.emission()
+	if want := skyLuminance(lgt) + luminance(sunEmission[:]); !near(estimate, want, 0.03) {
+		t.Errorf("the samples estimate %g, want %g", estimate, want)
+	}
+}
@@ -0,0 +1,189 @@
+package scene
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Set is a function to override a parameter of a loaded scene.
+// The paths are the ones of the validation reports: a field of the scene (Settings.Width,
+// Cameras["front"].FieldOfView), of its objects (ObjList[2].Color) or of its lights
+// (LightList[0].LightIntensity).
+//
+// Parameters:
+//  path  - path to the parameter.
+//  value - the new value as json, strings may be given without quotes.
+//
+// Returns:
+//  a *utils.FieldError with the path if the parameter does not exist or the value does not fit.
+//
+func (sc *Scene) Set(path, value string) error {
+	tokens, err := splitPath(path)
+	if err != nil {
+		return &utils.FieldError{Field: path, Err: err}
+	}
+	root := reflect.ValueOf(sc).Elem()
+	for _, candidate := range []reflect.Value{root, reflect.ValueOf(sc.Objects).Elem(), reflect.ValueOf(sc.Lights).Elem()} {
+		if candidate.FieldByName(tokens[0]).IsValid() {
+			root = candidate
+			break
+		}
+	}
+	target, err := walk(root, tokens)
+	if err == nil {
+		err = assign(target, value)
+	}
+	if err != nil {
+		return &utils.FieldError{Field: path, Err: err}
+	}
+	if tokens[0] == "Settings" {
+		return sc.SelectCamera(sc.Settings.Camera)
+	}
+	return nil
+}
+
+// SelectCamera is a function to change the rendered camera.
+//
+// Parameters:
+//  name - the name of the camera, the current one is kept when empty.
+//
+// Returns:
+//  an error if the scene has no camera with the name.
+//
+func (sc *Scene) SelectCamera(name string) error {
+	if name == "" {
+		return nil
+	}
+	cam, found := sc.Cameras[name]
+	if !found {
+		return utils.WrapField("Settings.Camera", fmt.Errorf("camera %q not found", name))
+	}
+	sc.Camera = cam
+	sc.Settings.Camera = name
+	return nil
+}
+
+// splitPath is a function to split a path in field names, indices and map keys.
+//
+// Parameters:
+//  path - the path, like ObjList[2].Color or Cameras["front"].Pos.
+//
+// Returns:
+//  the tokens, the indices and keys keeping their brackets.
+//  an error if the path is empty or has an unclosed bracket.
+//
+func splitPath(path string) ([]string, error) {
+	tokens := make([]string, 0, 4)
+	for rest := path; rest != ""; {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.New("unclosed bracket")
+			}
+			tokens = append(tokens, rest[:end+1])
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, rest[:end])
+			rest = rest[end:]
+		}
+	}
+	if len(tokens) == 0 || tokens[0][0] == '[' {
+		return nil, errors.New("the path must start with a field")
+	}
+	return tokens, nil
+}
+
+// walk is a function to follow a path from a value.
+//
+// Parameters:
+//  value  - the value, a struct for the first token.
+//  tokens - the tokens of the path.
+//
+// Returns:
+//  the settable value at the end of the path, map entries must be pointers to structs.
+//  an error if a field, index or key does not exist or the value can not be set.
+//
+func walk(value reflect.Value, tokens []string) (reflect.Value, error) {
+	for _, token := range tokens {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, fmt.Errorf("%s of a nil value", token)
+			}
+			value = value.Elem()
+		}
+		if token[0] != '[' {
+			if value.Kind() != reflect.Struct {
+				return reflect.Value{}, fmt.Errorf("%s of a %s", token, value.Kind())
+			}
+			field := value.FieldByName(token)
+			if !field.IsValid() || !field.CanSet() {
+				return reflect.Value{}, fmt.Errorf("unknown field %q", token)
+			}
+			value = field
+			continue
+		}
+		key := token[1 : len(token)-1]
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= value.Len() {
+				return reflect.Value{}, fmt.Errorf("index %s outside of [0, %d)", key, value.Len())
+			}
+			value = value.Index(i)
+		case reflect.Map:
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				key = unquoted
+			}
+			entry := value.MapIndex(reflect.ValueOf(key))
+			if !entry.IsValid() {
+				return reflect.Value{}, fmt.Errorf("key %q not found", key)
+			}
+			if entry.Kind() != reflect.Ptr {
+				return reflect.Value{}, fmt.Errorf("map entry %q can not be set", key)
+			}
+			value = entry
+		default:
+			return reflect.Value{}, fmt.Errorf("%s of a %s", token, value.Kind())
+		}
+	}
+	if !value.CanSet() {
+		return reflect.Value{}, errors.New("the value can not be replaced, set its fields")
+	}
+	return value, nil
+}
+
+// assign is a function to set a value from its json.
+//
+// Parameters:
+//  target - the settable value.
+//  value  - the json, strings may be given without quotes.
+//
+// Returns:
+//  an error if the json does not fit the type of the target.
+//
+func assign(target reflect.Value, value string) error {
+	decoded := reflect.New(target.Type())
+	err := json.Unmarshal([]byte(value), decoded.Interface())
+	if err != nil && target.Kind() == reflect.String {
+		decoded.Elem().SetString(value)
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %s for a %s", value, target.Type())
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
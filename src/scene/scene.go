@@ -0,0 +1,806 @@
+package scene
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/io/obj"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/texture"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Scene is a class for everything needed to render an image.
+//
+// Members:
+//  Objects  - the objects, meshes and instances.
+//  Lights   - the lights.
+//  Cameras  - the cameras by name.
+//  Camera   - the camera selected by the Settings.
+//  Settings - the render settings.
+//
+type Scene struct {
+	Objects  *general.Objects
+	Lights   *light.Lights
+	Cameras  map[string]*camera.Camera
+	Camera   *camera.Camera
+	Settings Settings
+}
+
+// Settings is a class for the render settings of a scene.
+// Zero fields keep the value of the included files, or the default.
+//
+// Members:
+//  Width        - width of the image in pixels.
+//  Height       - height of the image in pixels.
+//  RaysPerPixel - number of rays traced through each pixel.
+//  Iterations   - number of bounces of each ray.
+//  Output       - path to the output folder.
+//  Camera       - name of the rendered camera, the first one when empty.
+//  LightSampler - strategy choosing the light sampled by the path tracer: "uniform", "power" or "bvh".
+//
+type Settings struct {
+	Width        int
+	Height       int
+	RaysPerPixel int
+	Iterations   int
+	Output       string
+	Camera       string
+	LightSampler string
+}
+
+// DefaultSettings is a function to get the settings used when a scene does not set them.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the settings.
+//
+func DefaultSettings() Settings {
+	return Settings{Width: 600, Height: 600, RaysPerPixel: 1000, Iterations: 5, Output: "out/pathtracing", LightSampler: light.SamplerPower}
+}
+
+// merge is a function to override the settings with the non zero fields of other settings.
+//
+// Parameters:
+//  other - the settings overriding.
+//
+// Returns:
+//  none
+//
+func (settings *Settings) merge(other *Settings) {
+	if other.Width != 0 {
+		settings.Width = other.Width
+	}
+	if other.Height != 0 {
+		settings.Height = other.Height
+	}
+	if other.RaysPerPixel != 0 {
+		settings.RaysPerPixel = other.RaysPerPixel
+	}
+	if other.Iterations != 0 {
+		settings.Iterations = other.Iterations
+	}
+	if other.Output != "" {
+		settings.Output = other.Output
+	}
+	if other.Camera != "" {
+		settings.Camera = other.Camera
+	}
+	if other.LightSampler != "" {
+		settings.LightSampler = other.LightSampler
+	}
+}
+
+// File is a class for the contents of a scene file.
+// Paths are relative to the file, the included files are read before it.
+//
+// Members:
+//  Include   - paths to other scene files.
+//  Settings  - the render settings.
+//  Materials - materials by name, used by Objects, Meshes, Instances and Lights.
+//  Objects   - the rendered objects.
+//  Meshes    - objects only rendered through Instances.
+//  Instances - placements of the Meshes.
+//  Lights    - the lights.
+//  Cameras   - the cameras.
+//
+type File struct {
+	Include   []string
+	Settings  Settings
+	Materials map[string]*general.Material
+	Objects   []ObjectDesc
+	Meshes    []ObjectDesc
+	Instances []InstanceDesc
+	Lights    []LightDesc
+	Cameras   []CameraDesc
+}
+
+// ObjectDesc is a class for an object of a scene file.
+// With a Path the geometry comes from the .obj file, as do the surface attributes
+// unless a Material or a Color is given.
+//
+// Members:
+//  Object   - the attributes of the object, inlined in the json.
+//  Path     - path to an .obj file (optional).
+//  Material - name of a material replacing the surface attributes (optional).
+//
+type ObjectDesc struct {
+	general.Object
+	Path     string
+	Material string
+}
+
+// InstanceDesc is a class for an instance of a scene file.
+//
+// Members:
+//  Name      - the name of the instance.
+//  Mesh      - the name of the mesh.
+//  Transform - placement of the mesh in the world, identity when empty.
+//  Material  - name of a material replacing the one of the mesh (optional).
+//
+type InstanceDesc struct {
+	Name      string
+	Mesh      string
+	Transform general.Transform
+	Material  string
+}
+
+// LightDesc is a class for a light of a scene file.
+// The fields used depend on the Type, as in the lights file.
+//
+// Members:
+//  Type             - light.TypeArea (or empty), light.TypePoint, light.TypeSpot, light.TypeDirectional,
+//                     light.TypeEnvironment or light.TypeSky.
+//  AmbientIntensity - the ambient intensity.
+//  LightIntensity   - the light intensity.
+//  Color            - RGB of the light.
+//  Object           - the shape of an area light.
+//  Position         - the position of a point or spot light.
+//  Direction        - the axis of a spot light or of the profile of a point light, or the direction
+//                     travelled by a directional light.
+//  Radius           - radius of the sphere of a point light, 0 for a single point.
+//  InnerAngle       - angle in degrees of the full intensity of a spot light.
+//  OuterAngle       - angle in degrees of the border of a spot light.
+//  Profile          - the IES file of a point or spot light.
+//  AngularDiameter  - apparent diameter in degrees of a directional light, 0 for parallel rays.
+//  Image            - the equirectangular image of an environment light.
+//  Rotation         - rotation in degrees of an environment light around the y axis.
+//  Elevation        - angle in degrees between the horizon and the sun of a sky.
+//  Azimuth          - angle in degrees around the y axis of the sun of a sky.
+//  Turbidity        - haziness of the air of a sky, from 2 (clear) to 10 (hazy).
+//  SunIntensity     - irradiance of the sun of a sky on top of the atmosphere, 0 for a sky without the sun.
+//
+type LightDesc struct {
+	Type             string
+	AmbientIntensity float64
+	LightIntensity   float64
+	Color            []float64
+	Object           ObjectDesc
+	Position         []float64
+	Direction        []float64
+	Radius           float64
+	InnerAngle       float64
+	OuterAngle       float64
+	Profile          *light.Profile
+	AngularDiameter  float64
+	Image            *texture.Image
+	Rotation         float64
+	Elevation        float64
+	Azimuth          float64
+	Turbidity        float64
+	SunIntensity     float64
+}
+
+// InitLightDesc is a function to describe a light for a scene file.
+//
+// Parameters:
+//  lgt - the light.
+//
+// Returns:
+//  the description, with the fields of the type of the light.
+//
+func InitLightDesc(lgt light.Light) LightDesc {
+	emitter := lgt.GetEmitter()
+	desc := LightDesc{Type: emitter.Type, AmbientIntensity: emitter.AmbientIntensity, LightIntensity: emitter.LightIntensity, Color: emitter.Color}
+	switch lgt := lgt.(type) {
+	case *light.AreaLight:
+		desc.Object = ObjectDesc{Object: lgt.LightObject}
+	case *light.PointLight:
+		desc.Position = lgt.Position
+		desc.Radius = lgt.Radius
+		desc.Direction = lgt.Direction
+		desc.Profile = lgt.Profile
+	case *light.SpotLight:
+		desc.Position = lgt.Position
+		desc.Direction = lgt.Direction
+		desc.InnerAngle = lgt.InnerAngle
+		desc.OuterAngle = lgt.OuterAngle
+		desc.Profile = lgt.Profile
+	case *light.DirectionalLight:
+		desc.Direction = lgt.Direction
+		desc.AngularDiameter = lgt.AngularDiameter
+	case *light.EnvironmentLight:
+		desc.Image = lgt.Image
+		desc.Rotation = lgt.Rotation
+	case *light.SkyLight:
+		desc.Elevation = lgt.Elevation
+		desc.Azimuth = lgt.Azimuth
+		desc.Turbidity = lgt.Turbidity
+		desc.SunIntensity = lgt.SunIntensity
+	}
+	return desc
+}
+
+// CameraDesc is a class for a camera of a scene file.
+//
+// Members:
+//  Name   - the name of the camera.
+//  Camera - the attributes of the camera, inlined in the json.
+//  Target - point looked at, replacing Look, Up and Right (optional).
+//
+type CameraDesc struct {
+	Name string
+	camera.Camera
+	Target []float64
+}
+
+// loadedFile is a class for a scene file read from the disk.
+//
+// Members:
+//  path - the path to the file.
+//  file - the contents.
+//
+type loadedFile struct {
+	path string
+	file *File
+}
+
+// Load is a function to read a scene file and its includes.
+//
+// Parameters:
+//  inPath - path to the scene file.
+//
+// Returns:
+//  the scene.
+//  an error, a *utils.FileError with the path to the invalid field when a file is invalid.
+//
+func Load(inPath string) (*Scene, error) {
+	return load(inPath, true)
+}
+
+// Read is a function to read a scene file and its includes without checking the objects,
+// lights and cameras, so the validation package can report every issue at once.
+// Normals are not generated and textures are not loaded, the scene is not ready to be rendered.
+//
+// Parameters:
+//  inPath - path to the scene file.
+//
+// Returns:
+//  the scene.
+//  an error, a *utils.FileError when a file, a material or a camera can not be found.
+//
+func Read(inPath string) (*Scene, error) {
+	return load(inPath, false)
+}
+
+// WriteJSON is a function to write a scene file.
+//
+// Parameters:
+//  filePath - path to the file, paths inside the file must be relative to its folder.
+//
+// Returns:
+//  an error if the file could not be written.
+//
+func (file *File) WriteJSON(filePath string) error {
+	data, err := json.MarshalIndent(file, "", "	")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(filePath); !utils.PathExists(dir) {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return utils.WrapFile("write", filePath, err)
+		}
+	}
+	return utils.WrapFile("write", filePath, ioutil.WriteFile(filePath, data, 0700))
+}
+
+// load is a function to read a scene file and its includes.
+//
+// Parameters:
+//  inPath  - path to the scene file.
+//  prepare - false to keep the objects, lights and cameras as they are written.
+//
+// Returns:
+//  the scene.
+//  an error.
+//
+func load(inPath string, prepare bool) (*Scene, error) {
+	files, err := readFiles(inPath, nil, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	label := strings.TrimSuffix(filepath.Base(inPath), filepath.Ext(inPath))
+	return build(label, files, prepare)
+}
+
+// readFiles is a function to read a scene file after the files it includes.
+//
+// Parameters:
+//  inPath  - path to the scene file.
+//  stack   - absolute paths of the files including this one.
+//  visited - absolute paths already read, each file is only read once.
+//
+// Returns:
+//  the files in the order they must be applied.
+//  an error.
+//
+func readFiles(inPath string, stack []string, visited map[string]bool) ([]loadedFile, error) {
+	absPath, err := filepath.Abs(inPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, including := range stack {
+		if including == absPath {
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack, absPath), " -> "))
+		}
+	}
+	if visited[absPath] {
+		return nil, nil
+	}
+	visited[absPath] = true
+
+	byteScene, err := ioutil.ReadFile(inPath)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err = json.Unmarshal(byteScene, &file); err != nil {
+		return nil, utils.WrapFile("parse", inPath, err)
+	}
+	dir := filepath.Dir(inPath)
+	for _, mat := range file.Materials {
+		if mat != nil {
+			resolveImage(dir, mat.Texture)
+		}
+	}
+
+	files := make([]loadedFile, 0, len(file.Include)+1)
+	for i, include := range file.Include {
+		included, err := readFiles(resolvePath(dir, include), append(stack, absPath), visited)
+		if err != nil {
+			// errors inside the included file already have its path
+			if _, located := err.(*utils.FileError); located {
+				return nil, err
+			}
+			return nil, utils.WrapFile("load", inPath, utils.WrapField(fmt.Sprintf("Include[%d]", i), err))
+		}
+		files = append(files, included...)
+	}
+	return append(files, loadedFile{path: inPath, file: &file}), nil
+}
+
+// build is a function to convert the scene files to a Scene.
+//
+// Parameters:
+//  label   - the label of the objects.
+//  files   - the files in the order they are applied.
+//  prepare - false to keep the objects, lights and cameras as they are written.
+//
+// Returns:
+//  the scene.
+//  an error.
+//
+func build(label string, files []loadedFile, prepare bool) (*Scene, error) {
+	settings := DefaultSettings()
+	materials := make(map[string]*general.Material)
+	for _, loaded := range files {
+		settings.merge(&loaded.file.Settings)
+		for name, mat := range loaded.file.Materials {
+			if mat == nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField("Materials."+name, errors.New("empty material")))
+			}
+			materials[name] = mat
+		}
+	}
+
+	objs := general.InitObjects(label, nil)
+	lights := light.InitLights(nil)
+	sc := Scene{Objects: objs, Lights: &lights, Cameras: make(map[string]*camera.Camera), Settings: settings}
+	firstCamera := ""
+	for _, loaded := range files {
+		dir := filepath.Dir(loaded.path)
+		file := loaded.file
+		for i := range file.Objects {
+			object, err := file.Objects[i].build(dir, materials, prepare)
+			if err != nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(fmt.Sprintf("Objects[%d]", i), err))
+			}
+			objs.ObjList = append(objs.ObjList, object)
+		}
+		for i := range file.Meshes {
+			object, err := file.Meshes[i].build(dir, materials, prepare)
+			if err != nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(fmt.Sprintf("Meshes[%d]", i), err))
+			}
+			objs.Meshes = append(objs.Meshes, object)
+		}
+		for i := range file.Lights {
+			lgt, err := file.Lights[i].build(dir, materials, prepare)
+			if err != nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(fmt.Sprintf("Lights[%d]", i), err))
+			}
+			lights.LightList = append(lights.LightList, lgt)
+		}
+		// a camera of an included file may be replaced, but not one of the same file
+		names := make(map[string]bool)
+		for i := range file.Cameras {
+			field := fmt.Sprintf("Cameras[%d]", i)
+			name := file.Cameras[i].Name
+			if names[name] {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(field+".Name", fmt.Errorf("camera %q defined twice", name)))
+			}
+			names[name] = true
+			cam, err := file.Cameras[i].build(prepare)
+			if err != nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(field, err))
+			}
+			if len(sc.Cameras) == 0 {
+				firstCamera = file.Cameras[i].Name
+			}
+			sc.Cameras[file.Cameras[i].Name] = cam
+		}
+	}
+	// instances are placed after every mesh is known
+	for _, loaded := range files {
+		for i := range loaded.file.Instances {
+			inst, err := loaded.file.Instances[i].build(objs, materials, prepare)
+			if err != nil {
+				return nil, utils.WrapFile("load", loaded.path, utils.WrapField(fmt.Sprintf("Instances[%d]", i), err))
+			}
+			objs.Instances = append(objs.Instances, inst)
+		}
+	}
+
+	scenePath := files[len(files)-1].path
+	if sc.Settings.Camera == "" {
+		sc.Settings.Camera = firstCamera
+	}
+	cam, found := sc.Cameras[sc.Settings.Camera]
+	if !found {
+		if len(sc.Cameras) == 0 {
+			return nil, utils.WrapFile("load", scenePath, utils.WrapField("Cameras", errors.New("no camera")))
+		}
+		return nil, utils.WrapFile("load", scenePath, utils.WrapField("Settings.Camera", fmt.Errorf("camera %q not found", sc.Settings.Camera)))
+	}
+	sc.Camera = cam
+	if prepare && (sc.Settings.Width <= 0 || sc.Settings.Height <= 0) {
+		return nil, utils.WrapFile("load", scenePath, utils.WrapField("Settings", fmt.Errorf("invalid image size (%d, %d)", sc.Settings.Width, sc.Settings.Height)))
+	}
+	return &sc, nil
+}
+
+// build is a function to convert the description to an Object ready to be rendered.
+//
+// Parameters:
+//  dir       - the folder of the scene file.
+//  materials - the materials by name.
+//  prepare   - false to skip the checks, the normals and the textures.
+//
+// Returns:
+//  the object.
+//  a *utils.FieldError if an attribute is invalid.
+//
+func (desc *ObjectDesc) build(dir string, materials map[string]*general.Material, prepare bool) (general.Object, error) {
+	object, err := desc.load(dir, materials)
+	if err != nil || !prepare {
+		return object, err
+	}
+	return object, object.Prepare()
+}
+
+// checkPathGeometry is a function to check that an object read from an .obj file has no geometry of its own.
+//
+// Parameters:
+//  object - the attributes of the description.
+//
+// Returns:
+//  a *utils.FieldError naming the geometry that would be replaced by the file.
+//
+func checkPathGeometry(object *general.Object) error {
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"Vertices", len(object.Vertices.Points) > 0},
+		{"Triangles", len(object.Triangles) > 0},
+		{"Normals", len(object.Normals) > 0},
+		{"TexCoords", len(object.TexCoords) > 0},
+		{"Tangents", len(object.Tangents) > 0},
+		{"Primitive", object.Primitive != nil},
+	} {
+		if field.set {
+			return utils.WrapField(field.name, errors.New("not allowed with Path"))
+		}
+	}
+	return nil
+}
+
+// mergeObject is a function to replace the attributes of an object read from an .obj file by the
+// ones set in the description. The zero values keep the attributes of the file.
+//
+// Parameters:
+//  object - the object read from the file.
+//  other  - the attributes of the description, without geometry.
+//
+// Returns:
+//  none
+//
+func mergeObject(object, other *general.Object) {
+	if other.Name != "" {
+		object.Name = other.Name
+	}
+	if len(other.Color) != 0 {
+		object.Color = other.Color
+	}
+	for _, attribute := range []struct {
+		value  float64
+		target *float64
+	}{
+		{other.SpecularDecay, &object.SpecularDecay},
+		{other.SpecularReflection, &object.SpecularReflection},
+		{other.TransReflection, &object.TransReflection},
+		{other.AmbientReflection, &object.AmbientReflection},
+		{other.DiffuseReflection, &object.DiffuseReflection},
+		{other.RoughNess, &object.RoughNess},
+		{other.CreaseAngle, &object.CreaseAngle},
+		{other.RefractiveIndex, &object.RefractiveIndex},
+		{other.EmissionStrength, &object.EmissionStrength},
+		{other.BumpScale, &object.BumpScale},
+	} {
+		if attribute.value != 0 {
+			*attribute.target = attribute.value
+		}
+	}
+	if other.NormalMode != "" {
+		object.NormalMode = other.NormalMode
+	}
+	if len(other.Emission) != 0 {
+		object.Emission = other.Emission
+	}
+	if other.Texture != nil {
+		object.Texture = other.Texture
+	}
+	if other.NormalMap != nil {
+		object.NormalMap = other.NormalMap
+	}
+	if other.BumpMap != nil {
+		object.BumpMap = other.BumpMap
+	}
+	if other.Procedural != nil {
+		object.Procedural = other.Procedural
+	}
+	if other.Transform != nil {
+		object.Transform = other.Transform
+	}
+}
+
+// load is a function to convert the description to an Object, reading its .obj file.
+//
+// Parameters:
+//  dir       - the folder of the scene file.
+//  materials - the materials by name.
+//
+// Returns:
+//  the object, not checked yet.
+//  a *utils.FieldError if the .obj file or the material can not be found, or the description has
+//  geometry besides the Path.
+//
+func (desc *ObjectDesc) load(dir string, materials map[string]*general.Material) (general.Object, error) {
+	object := desc.Object
+	resolveImage(dir, object.Texture)
+	resolveImage(dir, object.NormalMap)
+	resolveImage(dir, object.BumpMap)
+	if desc.Path != "" {
+		if err := checkPathGeometry(&object); err != nil {
+			return object, err
+		}
+		options := obj.Options{NormalMode: object.NormalMode, CreaseAngle: object.CreaseAngle}
+		mesh, err := obj.ReadObjWithOptions(resolvePath(dir, desc.Path), options)
+		if err != nil {
+			return object, utils.WrapField("Path", err)
+		}
+		if len(object.Color) == 0 && desc.Material == "" {
+			// keeping the materials of the .obj file, replaced by the attributes of the description
+			mergeObject(mesh, &object)
+			object = *mesh
+		} else {
+			object.Vertices = mesh.Vertices
+			object.Triangles = mesh.Triangles
+			object.Normals = mesh.Normals
+			object.TexCoords = mesh.TexCoords
+			if object.Name == "" {
+				object.Name = mesh.Name
+			}
+		}
+	}
+	if desc.Material != "" {
+		mat, found := materials[desc.Material]
+		if !found {
+			return object, utils.WrapField("Material", fmt.Errorf("material %q not found", desc.Material))
+		}
+		object.SetMaterial(mat)
+	}
+	return object, nil
+}
+
+// build is a function to convert the description to an Instance.
+//
+// Parameters:
+//  objs      - the objects with every mesh.
+//  materials - the materials by name.
+//  prepare   - false to skip the checks of the mesh and the transform.
+//
+// Returns:
+//  the instance.
+//  a *utils.FieldError if the mesh or the material does not exist or the transform is invalid.
+//
+func (desc *InstanceDesc) build(objs *general.Objects, materials map[string]*general.Material, prepare bool) (general.Instance, error) {
+	inst := general.Instance{Name: desc.Name, Mesh: desc.Mesh, Transform: desc.Transform}
+	if desc.Material != "" {
+		mat, found := materials[desc.Material]
+		if !found {
+			return inst, utils.WrapField("Material", fmt.Errorf("material %q not found", desc.Material))
+		}
+		inst.Material = mat
+	}
+	if !prepare {
+		return inst, nil
+	}
+	if _, found := objs.FindMesh(desc.Mesh); !found {
+		return inst, utils.WrapField("Mesh", fmt.Errorf("mesh %q not found", desc.Mesh))
+	}
+	_, _, err := inst.GetTransform()
+	return inst, err
+}
+
+// build is a function to convert the description to a Light.
+//
+// Parameters:
+//  dir       - the folder of the scene file.
+//  materials - the materials by name.
+//  prepare   - false to skip the checks and the normals.
+//
+// Returns:
+//  the light.
+//  a *utils.FieldError if an attribute is invalid.
+//
+func (desc *LightDesc) build(dir string, materials map[string]*general.Material, prepare bool) (light.Light, error) {
+	emitter := light.Emitter{Type: desc.Type, AmbientIntensity: desc.AmbientIntensity, LightIntensity: desc.LightIntensity, Color: desc.Color}
+	var lgt light.Light
+	switch desc.Type {
+	case "", light.TypeArea:
+		object, err := desc.Object.load(dir, materials)
+		if err != nil {
+			return nil, utils.WrapField("Object", err)
+		}
+		if prepare {
+			if err = object.CheckIntegrity(); err != nil {
+				return nil, utils.WrapField("Object", err)
+			}
+		}
+		lgt = &light.AreaLight{Emitter: emitter, LightObject: object}
+	case light.TypePoint:
+		resolveProfile(dir, desc.Profile)
+		lgt = &light.PointLight{Emitter: emitter, Position: desc.Position, Radius: desc.Radius, Profile: desc.Profile, Direction: desc.Direction}
+	case light.TypeSpot:
+		resolveProfile(dir, desc.Profile)
+		lgt = &light.SpotLight{Emitter: emitter, Position: desc.Position, Direction: desc.Direction, InnerAngle: desc.InnerAngle, OuterAngle: desc.OuterAngle, Profile: desc.Profile}
+	case light.TypeDirectional:
+		lgt = &light.DirectionalLight{Emitter: emitter, Direction: desc.Direction, AngularDiameter: desc.AngularDiameter}
+	case light.TypeEnvironment:
+		resolveImage(dir, desc.Image)
+		lgt = &light.EnvironmentLight{Emitter: emitter, Image: desc.Image, Rotation: desc.Rotation}
+	case light.TypeSky:
+		lgt = &light.SkyLight{Emitter: emitter, Elevation: desc.Elevation, Azimuth: desc.Azimuth, Turbidity: desc.Turbidity, SunIntensity: desc.SunIntensity}
+	default:
+		return nil, utils.WrapField("Type", fmt.Errorf("unknown light type %q", desc.Type))
+	}
+	if !prepare {
+		return lgt, nil
+	}
+	return lgt, lgt.Prepare()
+}
+
+// build is a function to convert the description to a Camera.
+//
+// Parameters:
+//  prepare - false to keep the camera as it is written when it is invalid.
+//
+// Returns:
+//  the camera.
+//  a *utils.FieldError if a vector or a point is not 3D.
+//
+func (desc *CameraDesc) build(prepare bool) (*camera.Camera, error) {
+	if desc.Target == nil {
+		cam, err := camera.InitCamera(desc.Pos, desc.Look, desc.Up, desc.Right, desc.FieldOfView, desc.Near)
+		if err != nil {
+			if !prepare {
+				return &desc.Camera, nil
+			}
+			return nil, err
+		}
+		cam.NormalizeCam()
+		return &cam, nil
+	}
+	target := entity.Point{Coordinates: desc.Target}
+	cam, err := camera.InitCameraWithPoints(&desc.Pos, &target)
+	if err != nil {
+		if !prepare {
+			return &desc.Camera, nil
+		}
+		return nil, err
+	}
+	if desc.FieldOfView != 0 {
+		cam.FieldOfView = desc.FieldOfView
+	}
+	if desc.Near != 0 {
+		cam.Near = desc.Near
+	}
+	return &cam, nil
+}
+
+// resolvePath is a function to get a path relative to the folder of a scene file.
+//
+// Parameters:
+//  dir    - the folder of the scene file.
+//  inPath - the path, kept when absolute.
+//
+// Returns:
+//  the path.
+//
+func resolvePath(dir, inPath string) string {
+	if filepath.IsAbs(inPath) {
+		return inPath
+	}
+	return filepath.Join(dir, inPath)
+}
+
+// resolveImage is a function to make the path of an image relative to the folder of a scene file.
+//
+// Parameters:
+//  dir - the folder of the scene file.
+//  img - the image, may be nil.
+//
+// Returns:
+//  none
+//
+func resolveImage(dir string, img *texture.Image) {
+	if img != nil && img.Path != "" {
+		img.Path = resolvePath(dir, img.Path)
+	}
+}
+
+// resolveProfile is a function to make the path of a light profile relative to the folder of a scene file.
+//
+// Parameters:
+//  dir     - the folder of the scene file.
+//  profile - the profile, may be nil.
+//
+// Returns:
+//  none
+//
+func resolveProfile(dir string, profile *light.Profile) {
+	if profile != nil && profile.Path != "" {
+		profile.Path = resolvePath(dir, profile.Path)
+	}
+}
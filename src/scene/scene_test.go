@@ -0,0 +1,139 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// writeFiles is a function to write files in a temporary folder, removed by the caller.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "scene")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// checkFieldError is a function to check that an error of a file has the path to a field.
+func checkFieldError(t *testing.T, err error, file, field string) {
+	t.Helper()
+	var fileErr *utils.FileError
+	var fieldErr *utils.FieldError
+	if !errors.As(err, &fileErr) || !errors.As(err, &fieldErr) {
+		t.Fatalf("got %v, want a *utils.FileError with a *utils.FieldError", err)
+	}
+	if filepath.Base(fileErr.Path) != file || fieldErr.Field != field {
+		t.Errorf("got %v, want %s in %s", err, field, file)
+	}
+}
+
+const triangleObj = `v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1 2 3
+`
+
+// cameraJSON is a function to write a camera of a scene file.
+func cameraJSON(name string, fov int) string {
+	return fmt.Sprintf(`{"Name": %q, "Pos": {"Coordinates": [0, 0, 3]}, "Target": [0, 0, 0], "FieldOfView": %d}`, name, fov)
+}
+
+func TestLoadIncludes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"common/materials.json": `{
+	"Settings": {"Width": 20, "Height": 10, "RaysPerPixel": 4},
+	"Materials": {"red": {"Color": [1, 0, 0], "DiffuseReflection": 1}},
+	"Cameras": [` + cameraJSON("front", 40) + `, ` + cameraJSON("side", 50) + `]
+}`,
+		"common/triangle.obj": triangleObj,
+		"shared.json": `{
+	"Include": ["common/materials.json"],
+	"Objects": [{"Path": "common/triangle.obj", "Material": "red"}]
+}`,
+		"other.json": `{"Include": ["common/materials.json", "shared.json"]}`,
+		"scene.json": `{
+	"Include": ["shared.json", "other.json"],
+	"Settings": {"Height": 30},
+	"Cameras": [` + cameraJSON("front", 60) + `]
+}`,
+	})
+	defer os.RemoveAll(dir)
+
+	sc, err := Load(filepath.Join(dir, "scene.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if sc.Settings.Width != 20 || sc.Settings.Height != 30 || sc.Settings.RaysPerPixel != 4 {
+		t.Errorf("settings not merged: %+v", sc.Settings)
+	}
+	// shared.json and common/materials.json are included twice but read once
+	if len(sc.Objects.ObjList) != 1 {
+		t.Fatalf("got %d objects, want 1", len(sc.Objects.ObjList))
+	}
+	if color := sc.Objects.ObjList[0].Color; len(color) != 3 || color[0] != 1 || color[1] != 0 {
+		t.Errorf("material of the included file not applied: %v", color)
+	}
+	if len(sc.Cameras) != 2 {
+		t.Errorf("got %d cameras, want 2", len(sc.Cameras))
+	}
+	// the camera of the scene replaces the one of the included file, the first camera is rendered
+	if sc.Settings.Camera != "front" || sc.Camera.FieldOfView != 60 {
+		t.Errorf("got camera %q with a field of view of %g, want front with 60", sc.Settings.Camera, sc.Camera.FieldOfView)
+	}
+}
+
+func TestLoadIncludeCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"Include": ["b.json"], "Cameras": [` + cameraJSON("front", 50) + `]}`,
+		"b.json": `{"Include": ["c.json"]}`,
+		"c.json": `{"Include": ["a.json"]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := Load(filepath.Join(dir, "a.json"))
+	checkFieldError(t, err, "c.json", "Include[0]")
+	if !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("got %v, want an include cycle", err)
+	}
+}
+
+func TestLoadIncludeMissing(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"scene.json": `{"Include": ["missing.json"], "Cameras": [` + cameraJSON("front", 50) + `]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := Load(filepath.Join(dir, "scene.json"))
+	checkFieldError(t, err, "scene.json", "Include[0]")
+}
+
+func TestLoadDuplicateCamera(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"scene.json": `{"Cameras": [` + cameraJSON("front", 50) + `, ` + cameraJSON("side", 50) + `, ` + cameraJSON("front", 40) + `]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	for name, read := range map[string]func(string) (*Scene, error){"Load": Load, "Read": Read} {
+		t.Run(name, func(t *testing.T) {
+			_, err := read(filepath.Join(dir, "scene.json"))
+			checkFieldError(t, err, "scene.json", "Cameras[2].Name")
+		})
+	}
+}
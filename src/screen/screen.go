@@ -2,8 +2,8 @@ package screen
 
 import (
 	"errors"
+	"fmt"
 	"math"
-	"strconv"
 
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
@@ -38,17 +38,59 @@ func InitColoredScreen(width, height int) ColoredScreen {
 	return ColoredScreen{Screen: Screen{Width: width, Height: height}, Colors: colors}
 }
 
+// ComposeSideBySide is a function to place two colored screens side by side.
+//
+// Parameters:
+// 	left  - the screen on the left.
+//  right - the screen on the right.
+//
+// Returns:
+// 	the composite colored Screen.
+//
+func ComposeSideBySide(left, right *ColoredScreen) ColoredScreen {
+	composite := InitColoredScreen(left.Width+right.Width, utils.MaxInt(left.Height, right.Height))
+	for i := 0; i < left.Height; i++ {
+		copy(composite.Colors[i], left.Colors[i])
+	}
+	for i := 0; i < right.Height; i++ {
+		copy(composite.Colors[i][left.Width:], right.Colors[i])
+	}
+	return composite
+}
+
+// ComposeTopBottom is a function to place one colored screen above the other.
+//
+// Parameters:
+// 	top    - the screen on the top.
+//  bottom - the screen on the bottom.
+//
+// Returns:
+// 	the composite colored Screen.
+//
+func ComposeTopBottom(top, bottom *ColoredScreen) ColoredScreen {
+	composite := InitColoredScreen(utils.MaxInt(top.Width, bottom.Width), top.Height+bottom.Height)
+	for i := 0; i < top.Height; i++ {
+		copy(composite.Colors[i], top.Colors[i])
+	}
+	for i := 0; i < bottom.Height; i++ {
+		copy(composite.Colors[top.Height+i], bottom.Colors[i])
+	}
+	return composite
+}
+
 // Screen is a class for image screen.
 //
 // Members:
 // 	Width      - the number of x pixels on the screen.
 // 	Height     - the number of y pixels on the screen.
 //  CamToWorld - the matrix from cam to world.
+//  Shift      - horizontal off-axis shift of the viewport (used by stereo rigs).
 //
 type Screen struct {
 	Width      int
 	Height     int
 	CamToWorld *utils.Matrix
+	Shift      float64
 }
 
 // PixelToWorld is a function to get the position of a pixel in world coordinates.
@@ -64,10 +106,14 @@ type Screen struct {
 //
 // Returns:
 // 	a Vector.
+// 	a *utils.FieldError if the pixel is outside the screen, or a *utils.SizeError if CamToWorld is not 4x4.
 //
-func (sc *Screen) PixelToWorld(x, y int, d float64, px, py, fov float64) utils.Vector {
-	if x >= sc.Height || y >= sc.Width {
-		utils.ShowError(errors.New("Invalid Pixel"), "X("+strconv.Itoa(x)+") or Y("+strconv.Itoa(y)+") invalid for screen("+strconv.Itoa(sc.Height)+", "+strconv.Itoa(sc.Width)+").")
+func (sc *Screen) PixelToWorld(x, y int, d float64, px, py, fov float64) (utils.Vector, error) {
+	if x < 0 || x >= sc.Width {
+		return utils.Vector{}, utils.WrapField("x", fmt.Errorf("pixel %d outside of [0, %d)", x, sc.Width))
+	}
+	if y < 0 || y >= sc.Height {
+		return utils.Vector{}, utils.WrapField("y", fmt.Errorf("pixel %d outside of [0, %d)", y, sc.Height))
 	}
 	camWorld := sc.CamToWorld
 
@@ -75,10 +121,10 @@ func (sc *Screen) PixelToWorld(x, y int, d float64, px, py, fov float64) utils.V
 	alpha := (fov / 2) * math.Pi / 180.0
 	z := d
 
-	camerax := (2*(float64(x)+px)/float64(sc.Width) - 1) * aspectRatio * math.Tan(alpha)
+	camerax := (2*(float64(x)+px)/float64(sc.Width)-1)*aspectRatio*math.Tan(alpha) + sc.Shift*d
 	cameray := (1 - 2*(float64(y)+py)/float64(sc.Height)) * math.Tan(alpha)
 
-	v := utils.InitVector(3)
+	v, _ := utils.InitVector(3) // a valid size
 
 	v.Coordinates[0] = camerax
 	v.Coordinates[1] = cameray
@@ -86,13 +132,34 @@ func (sc *Screen) PixelToWorld(x, y int, d float64, px, py, fov float64) utils.V
 
 	vMat := utils.VectorToHomogeneousCoord(&v)
 
-	vMatPos := utils.MultMatrix(camWorld, &vMat)
+	vMatPos, err := utils.MultMatrix(camWorld, &vMat)
+	if err != nil {
+		return utils.Vector{}, utils.WrapField("CamToWorld", err)
+	}
 	for i := 0; i < 3; i++ {
 		v.Coordinates[i] = vMatPos.Values[i][0]
 	}
 	vNormalized := utils.NormalizeVector(&v)
 
-	return vNormalized
+	return vNormalized, nil
+}
+
+// CheckIntegrity is a function to check the matrix moving the pixels to the world.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	a *utils.FieldError if CamToWorld is missing or not 4x4.
+//
+func (sc *Screen) CheckIntegrity() error {
+	if sc.CamToWorld == nil {
+		return utils.WrapField("CamToWorld", errors.New("missing matrix"))
+	}
+	if err := utils.CheckSize(sc.CamToWorld.Lines, 4); err != nil {
+		return utils.WrapField("CamToWorld", err)
+	}
+	return utils.WrapField("CamToWorld", utils.CheckSize(sc.CamToWorld.Columns, 4))
 }
 
 // InitScreen is a function to initialize a screen.
@@ -103,11 +170,12 @@ func (sc *Screen) PixelToWorld(x, y int, d float64, px, py, fov float64) utils.V
 //
 // Returns:
 // 	a Screen.
+//  an error if the width or the height is negative.
 //
-func InitScreen(width, height int) Screen {
+func InitScreen(width, height int) (Screen, error) {
 	if width < 0 || height < 0 {
-		utils.ShowError(errors.New("Invalid Screen"), "width("+strconv.Itoa(width)+") or height("+strconv.Itoa(height)+") invalid for screen.")
+		return Screen{}, fmt.Errorf("invalid screen size (%d, %d)", width, height)
 	}
 	sc := Screen{Width: width, Height: height}
-	return sc
+	return sc, nil
 }
@@ -0,0 +1,64 @@
+package screen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// testScreen is a function to create a screen looking down -z from the origin.
+func testScreen(t *testing.T, width, height int) Screen {
+	t.Helper()
+	sc, err := InitScreen(width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	camToWorld, _ := utils.IDMatrix(4)
+	sc.CamToWorld = &camToWorld
+	return sc
+}
+
+func TestPixelToWorldOutsideOfScreen(t *testing.T) {
+	sc := testScreen(t, 4, 2)
+	tests := []struct {
+		name  string
+		x, y  int
+		field string
+	}{
+		{"x too large", 4, 0, "x"},
+		{"negative x", -1, 0, "x"},
+		{"y too large", 0, 2, "y"},
+		{"negative y", 0, -1, "y"},
+	}
+	for _, test := range tests {
+		_, err := sc.PixelToWorld(test.x, test.y, 1, 0.5, 0.5, 90)
+		var fieldErr *utils.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != test.field {
+			t.Errorf("%s: got %v, want a *utils.FieldError on %s", test.name, err, test.field)
+		}
+	}
+	if _, err := sc.PixelToWorld(3, 1, 1, 0.5, 0.5, 90); err != nil {
+		t.Errorf("last pixel: %v", err)
+	}
+}
+
+func TestScreenCheckIntegrity(t *testing.T) {
+	sc := testScreen(t, 4, 2)
+	if err := sc.CheckIntegrity(); err != nil {
+		t.Errorf("4x4 matrix: %v", err)
+	}
+	small, _ := utils.IDMatrix(3)
+	sc.CamToWorld = &small
+	var sizeErr *utils.SizeError
+	if err := sc.CheckIntegrity(); !errors.As(err, &sizeErr) {
+		t.Errorf("3x3 matrix: got %v, want a *utils.SizeError", err)
+	}
+	if _, err := sc.PixelToWorld(0, 0, 1, 0.5, 0.5, 90); !errors.As(err, &sizeErr) {
+		t.Errorf("3x3 matrix pixel: got %v, want a *utils.SizeError", err)
+	}
+	sc.CamToWorld = nil
+	if err := sc.CheckIntegrity(); err == nil {
+		t.Errorf("missing matrix: got no error")
+	}
+}
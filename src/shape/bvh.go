@@ -0,0 +1,188 @@
+package shape
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+)
+
+// maxLeafShapes is the number of shapes (or triangles of a Mesh) below which a BVH node is not split.
+const maxLeafShapes = 2
+
+// bvhNode is a class for a node of a bounding volume hierarchy over the shapes of a List or the
+// triangles of a Mesh.
+//
+// Members:
+//  bounds - box around all shapes of the node.
+//  left   - first child, nil on leaves.
+//  right  - second child, nil on leaves.
+//  shapes - indices of the shapes (or triangles) of a leaf.
+//
+type bvhNode struct {
+	bounds []float64
+	left   *bvhNode
+	right  *bvhNode
+	shapes []int
+}
+
+// mergeBounds is a function to get the box around two boxes.
+//
+// Parameters:
+//  a - the first box.
+//  b - the second box.
+//
+// Returns:
+//  the merged box.
+//
+func mergeBounds(a, b []float64) []float64 {
+	bb := make([]float64, 6)
+	for j := 0; j < 3; j++ {
+		bb[j] = math.Min(a[j], b[j])
+		bb[j+3] = math.Max(a[j+3], b[j+3])
+	}
+	return bb
+}
+
+// buildBVH is a function to build a node splitting the shapes at the median of the longest axis.
+//
+// Parameters:
+//  indices - the indices of the shapes.
+//  bounds  - the box of every shape of the List.
+//
+// Returns:
+//  the node.
+//
+func buildBVH(indices []int, bounds [][]float64) *bvhNode {
+	node := &bvhNode{bounds: bounds[indices[0]]}
+	for _, idx := range indices[1:] {
+		node.bounds = mergeBounds(node.bounds, bounds[idx])
+	}
+	if len(indices) <= maxLeafShapes {
+		node.shapes = indices
+		return node
+	}
+	axis := 0
+	for j := 1; j < 3; j++ {
+		if node.bounds[j+3]-node.bounds[j] > node.bounds[axis+3]-node.bounds[axis] {
+			axis = j
+		}
+	}
+	sort.Slice(indices, func(a, b int) bool {
+		return bounds[indices[a]][axis]+bounds[indices[a]][axis+3] < bounds[indices[b]][axis]+bounds[indices[b]][axis+3]
+	})
+	half := len(indices) / 2
+	node.left = buildBVH(indices[:half], bounds)
+	node.right = buildBVH(indices[half:], bounds)
+	return node
+}
+
+// hitsBox is a function to check if a ray crosses a box with t in [tMin, tMax).
+//
+// Parameters:
+//  ray    - the ray.
+//  bounds - the box.
+//  tMin   - the smallest t accepted.
+//  tMax   - the greatest t accepted.
+//
+// Returns:
+//  true if the box is crossed.
+//
+func hitsBox(ray entity.Ray, bounds []float64, tMin, tMax float64) bool {
+	for j := 0; j < 3; j++ {
+		origin := ray.Origin[j]
+		dir := ray.Direction[j]
+		if dir == 0 {
+			if origin < bounds[j] || origin > bounds[j+3] {
+				return false
+			}
+			continue
+		}
+		t0 := (bounds[j] - origin) / dir
+		t1 := (bounds[j+3] - origin) / dir
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tMin = math.Max(tMin, t0)
+		tMax = math.Min(tMax, t1)
+		if tMin > tMax {
+			return false
+		}
+	}
+	return true
+}
+
+// closest is a function to find the closest intersection inside a node.
+//
+// Parameters:
+//  list    - the List owning the shapes.
+//  ray     - the ray.
+//  tMin    - the smallest t accepted.
+//  closest - the closest hit so far, its T is the greatest t accepted.
+//  cost    - counter of the boxes and primitives tested, may be nil.
+//
+// Returns:
+//  true if a closer hit was found.
+//
+func (node *bvhNode) closest(list *List, ray entity.Ray, tMin float64, closest *Hit, cost *int) bool {
+	if cost != nil {
+		*cost++
+	}
+	if !hitsBox(ray, node.bounds, tMin, closest.T) {
+		return false
+	}
+	if node.left == nil {
+		found := false
+		for _, idx := range node.shapes {
+			hit, intersected := intersect(list.Shapes[idx], ray, tMin, closest.T, cost)
+			if intersected {
+				hit.Index = idx
+				*closest = hit
+				found = true
+			}
+		}
+		return found
+	}
+	foundLeft := node.left.closest(list, ray, tMin, closest, cost)
+	foundRight := node.right.closest(list, ray, tMin, closest, cost)
+	return foundLeft || foundRight
+}
+
+// closestTriangle is a function to find the closest triangle of a Mesh inside a node.
+//
+// Parameters:
+//  mesh    - the Mesh owning the triangles.
+//  ray     - the ray.
+//  tMin    - the smallest t accepted.
+//  closest - the closest triangle so far, its t is the greatest t accepted.
+//  cost    - counter of the boxes and triangles tested, may be nil.
+//
+// Returns:
+//  true if a closer triangle was found.
+//
+func (node *bvhNode) closestTriangle(mesh *Mesh, ray entity.Ray, tMin float64, closest *triangleHit, cost *int) bool {
+	if cost != nil {
+		*cost++
+	}
+	if !hitsBox(ray, node.bounds, tMin, closest.t) {
+		return false
+	}
+	if node.left == nil {
+		found := false
+		if cost != nil {
+			*cost += len(node.shapes)
+		}
+		for _, idx := range node.shapes {
+			vertices := &mesh.triangles[idx]
+			t, u, v, intersected := ray.IntersectTriangle(vertices[0], vertices[1], vertices[2])
+			if intersected && inRange(t, tMin, closest.t) {
+				*closest = triangleHit{t: t, triangle: idx, u: u, v: v}
+				found = true
+			}
+		}
+		return found
+	}
+	foundLeft := node.left.closestTriangle(mesh, ray, tMin, closest, cost)
+	foundRight := node.right.closestTriangle(mesh, ray, tMin, closest, cost)
+	return foundLeft || foundRight
+}
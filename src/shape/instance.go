@@ -0,0 +1,209 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Instance is a class for a transformed reference to a shape.
+// Rays are moved to the space of the shape, so many instances share the same geometry.
+//
+// Members:
+//  Name         - the name of the instance.
+//  Shape        - the shared shape.
+//  Object       - the object holding the material of the instance.
+//  toWorld      - matrix from the shape to the world.
+//  toObject     - matrix from the world to the shape.
+//  normalMatrix - inverse transpose of toWorld, used on normals.
+//  area         - the area of the transformed shape.
+//  areas        - cumulative area of the transformed triangles on meshes, used for sampling.
+//
+type Instance struct {
+	Name         string
+	Shape        Shape
+	Object       *general.Object
+	toWorld      utils.Matrix
+	toObject     utils.Matrix
+	normalMatrix utils.Matrix
+	area         float64
+	areas        []float64
+}
+
+// InitInstance is a function to initialize an Instance.
+//
+// Parameters:
+//  name     - the name of the instance.
+//  base     - the shared shape.
+//  obj      - the object holding the material of the instance.
+//  toWorld  - matrix from the shape to the world.
+//  toObject - inverse of toWorld.
+//
+// Returns:
+//  the Instance.
+//
+func InitInstance(name string, base Shape, obj *general.Object, toWorld, toObject utils.Matrix) *Instance {
+	// toObject already proved toWorld is invertible
+	normalMatrix, _ := utils.NormalMatrix(&toWorld)
+	inst := &Instance{
+		Name:         name,
+		Shape:        base,
+		Object:       obj,
+		toWorld:      toWorld,
+		toObject:     toObject,
+		normalMatrix: normalMatrix,
+	}
+	if mesh, isMesh := base.(*Mesh); isMesh {
+		inst.areas = mesh.transformedAreas(&toWorld)
+		if len(inst.areas) > 0 {
+			inst.area = inst.areas[len(inst.areas)-1]
+		}
+	} else {
+		inst.area = transformedArea(base, &toWorld)
+	}
+	return inst
+}
+
+// transformedArea is a function to get the area of a shape moved by a matrix.
+// The area is exact for meshes, quads and disks (affine maps keep them flat), and the area of other
+// shapes is scaled by |det|^(2/3), exact for rigid motions and uniform scales.
+//
+// Parameters:
+//  s      - the shape.
+//  matrix - the transform.
+//
+// Returns:
+//  the area.
+//
+func transformedArea(s Shape, matrix *utils.Matrix) float64 {
+	switch shp := s.(type) {
+	case *Mesh:
+		areas := shp.transformedAreas(matrix)
+		if len(areas) == 0 {
+			return 0
+		}
+		return areas[len(areas)-1]
+	case *Quad:
+		return shp.EdgeU.TransformDirection(matrix).Cross(shp.EdgeV.TransformDirection(matrix)).Length()
+	case *Disk:
+		// the unit square of the axes becomes a parallelogram, the disk an ellipse
+		scale := shp.tangent.TransformDirection(matrix).Cross(shp.bitangent.TransformDirection(matrix)).Length()
+		return shp.Area() * scale
+	case *Instance:
+		toWorld, _ := utils.MultMatrix(matrix, &shp.toWorld) // the matrices are 4x4
+		return transformedArea(shp.Shape, &toWorld)
+	}
+	det, _ := utils.Determinant(matrix)
+	return s.Area() * math.Pow(math.Abs(det), 2.0/3.0)
+}
+
+// Intersect is a function to intersect the Instance.
+// The direction is not normalized in the shape space, so the t parameter is the same in both spaces.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the Hit, with the position in world space and the Local position of the shape.
+//  false if the instance was not intersected.
+//
+func (inst *Instance) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	return inst.intersect(ray, tMin, tMax, nil)
+}
+
+// intersect is a function to intersect the Instance, counting the work done by the shared shape.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//  cost - counter of the boxes and primitives tested, may be nil.
+//
+// Returns:
+//  the Hit, with the position in world space and the Local position of the shape.
+//  false if the instance was not intersected.
+//
+func (inst *Instance) intersect(ray entity.Ray, tMin, tMax float64, cost *int) (Hit, bool) {
+	localRay := entity.Ray{
+		Origin:    ray.Origin.TransformPoint(&inst.toObject),
+		Direction: ray.Direction.TransformDirection(&inst.toObject),
+	}
+	hit, found := intersect(inst.Shape, localRay, tMin, tMax, cost)
+	if !found {
+		return Hit{}, false
+	}
+	hit.Point = ray.At(hit.T)
+	hit.Object = inst.Object
+	hit.Shape = inst
+	return hit, true
+}
+
+// Normal is a function to get the normal of the shape moved to world space.
+//
+// Parameters:
+//  hit - the hit on the Instance.
+//
+// Returns:
+//  the normal.
+//
+func (inst *Instance) Normal(hit Hit) utils.Vec3 {
+	localHit := hit
+	localHit.Point = hit.Point.TransformPoint(&inst.toObject)
+	localHit.Shape = inst.Shape
+	return inst.Shape.Normal(localHit).TransformDirection(&inst.normalMatrix).Normalize()
+}
+
+// Area is a function to get the area of the Instance, the sum of the transformed triangles on meshes.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the area.
+//
+func (inst *Instance) Area() float64 {
+	return inst.area
+}
+
+// Sample is a function to sample the shape and move the point to world space.
+// The triangles of meshes are chosen by their transformed area, keeping the points uniform.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the normal.
+//
+func (inst *Instance) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	var pos, normal utils.Vec3
+	if mesh, isMesh := inst.Shape.(*Mesh); isMesh {
+		pos, normal = mesh.sample(inst.areas, u1, u2)
+	} else {
+		pos, normal = inst.Shape.Sample(u1, u2)
+	}
+	return pos.TransformPoint(&inst.toWorld), normal.TransformDirection(&inst.normalMatrix).Normalize()
+}
+
+// Bounds is a function to get the world bounding box of the transformed box of the shape.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func (inst *Instance) Bounds() []float64 {
+	local := inst.Shape.Bounds()
+	for _, value := range local {
+		if math.IsInf(value, 0) {
+			return []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1), math.Inf(1)}
+		}
+	}
+	return general.TransformBoundingBox(&inst.toWorld, local)
+}
@@ -0,0 +1,87 @@
+package shape
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// instance is a function to place a shape with a Transform, failing the test on errors.
+func instance(t *testing.T, base Shape, tr general.Transform) *Instance {
+	t.Helper()
+	toWorld, toObject, err := tr.GetMatrices()
+	if err != nil {
+		t.Fatalf("GetMatrices: %v", err)
+	}
+	return InitInstance("test", base, &general.Object{}, toWorld, toObject)
+}
+
+// corner is a function to create an object with a triangle in the xy plane and another in the xz plane.
+func corner() *general.Object {
+	points := []entity.Point{
+		{Coordinates: []float64{0, 0, 0}},
+		{Coordinates: []float64{1, 0, 0}},
+		{Coordinates: []float64{0, 1, 0}},
+		{Coordinates: []float64{0, 0, 1}},
+	}
+	return &general.Object{
+		Vertices: entity.InitVertices(points),
+		Triangles: []entity.Triangle{
+			entity.InitTriangle([]int{0, 1, 2}, []int{0, 0, 0}),
+			entity.InitTriangle([]int{0, 3, 1}, []int{0, 0, 0}),
+		},
+		Normals: []utils.Vector{{Coordinates: []float64{0, 0, 1}}},
+	}
+}
+
+func TestInstanceArea(t *testing.T) {
+	tests := []struct {
+		name string
+		base Shape
+		tr   general.Transform
+		area float64
+	}{
+		// the xz triangle is stretched 10 times, the xy one is kept
+		{"mesh non uniform scale", InitMesh(corner()), general.Transform{Scale: []float64{1, 1, 10}}, 0.5 + 5},
+		{"mesh sheared", InitMesh(corner()), general.Transform{Shear: []float64{0, 0, 0, 0, 1, 0}}, 0.5 + math.Sqrt(2)/2},
+		{"mesh rotated and translated", InitMesh(corner()), general.Transform{Rotate: []float64{30, 45, 60}, Translate: []float64{1, 2, 3}}, 1},
+		{"quad non uniform scale", InitQuad(utils.Vec3{}, utils.Vec3{1, 0, 0}, utils.Vec3{0, 1, 0}, nil), general.Transform{Scale: []float64{2, 3, 5}}, 6},
+		{"disk non uniform scale", InitDisk(utils.Vec3{}, utils.Vec3{0, 0, 1}, 1, nil), general.Transform{Scale: []float64{2, 3, 5}}, 6 * math.Pi},
+		{"sphere uniform scale", InitSphere(utils.Vec3{}, 1, nil), general.Transform{Scale: []float64{2, 2, 2}}, 16 * math.Pi},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inst := instance(t, test.base, test.tr)
+			if math.Abs(inst.Area()-test.area) > 1e-9 {
+				t.Errorf("got area %g, want %g", inst.Area(), test.area)
+			}
+		})
+	}
+}
+
+func TestNestedInstanceArea(t *testing.T) {
+	inner := instance(t, InitMesh(corner()), general.Transform{Scale: []float64{1, 1, 10}})
+	outer := instance(t, inner, general.Transform{Scale: []float64{2, 2, 2}})
+	if want := 4 * 5.5; math.Abs(outer.Area()-want) > 1e-9 {
+		t.Errorf("got area %g, want %g", outer.Area(), want)
+	}
+}
+
+func TestInstanceSampleByTransformedArea(t *testing.T) {
+	inst := instance(t, InitMesh(corner()), general.Transform{Scale: []float64{1, 1, 10}})
+	const samples = 1000
+	inXZ := 0
+	for i := 0; i < samples; i++ {
+		pos, _ := inst.Sample((float64(i)+0.5)/samples, 0.5)
+		if pos[1] == 0 && pos[2] > 0 {
+			inXZ++
+		}
+	}
+	// the xz triangle has 5 of the 5.5 of the area
+	if want := samples * 5 / 5.5; math.Abs(float64(inXZ)-want) > 2 {
+		t.Errorf("got %d samples on the stretched triangle, want %g", inXZ, want)
+	}
+}
@@ -0,0 +1,248 @@
+package shape
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Mesh is a class for the triangles of an Object.
+//
+// Members:
+//  Object    - the object.
+//  areas     - cumulative area of the triangles, used for sampling.
+//  triangles - the vertices of each triangle, copied so intersections do not allocate.
+//  root      - bounding volume hierarchy over the triangles, nil without triangles.
+//
+type Mesh struct {
+	Object    *general.Object
+	areas     []float64
+	triangles [][3]utils.Vec3
+	root      *bvhNode
+}
+
+// triangleHit is a class for the closest triangle found while traversing the hierarchy of a Mesh.
+//
+// Members:
+//  t        - the ray t parameter, the greatest t accepted while traversing.
+//  triangle - the index of the triangle, -1 when none was found.
+//  u        - the second baricentric coordinate.
+//  v        - the third baricentric coordinate.
+//
+type triangleHit struct {
+	t        float64
+	triangle int
+	u        float64
+	v        float64
+}
+
+// InitMesh is a function to initialize a Mesh.
+//
+// Parameters:
+//  obj - the object.
+//
+// Returns:
+//  the Mesh.
+//
+func InitMesh(obj *general.Object) *Mesh {
+	areas := make([]float64, len(obj.Triangles))
+	triangles := make([][3]utils.Vec3, len(obj.Triangles))
+	bounds := make([][]float64, len(obj.Triangles))
+	indices := make([]int, len(obj.Triangles))
+	total := 0.0
+	for i, triangle := range obj.Triangles {
+		for j := 0; j < 3; j++ {
+			triangles[i][j] = entity.PointToVec3(&obj.Vertices.Points[triangle.Vertices[j]])
+		}
+		edge1 := triangles[i][1].Sub(triangles[i][0])
+		edge2 := triangles[i][2].Sub(triangles[i][0])
+		total += edge1.Cross(edge2).Length() / 2
+		areas[i] = total
+		bounds[i] = triangleBounds(&triangles[i])
+		indices[i] = i
+	}
+	mesh := &Mesh{Object: obj, areas: areas, triangles: triangles}
+	if len(indices) > 0 {
+		mesh.root = buildBVH(indices, bounds)
+	}
+	return mesh
+}
+
+// triangleBounds is a function to get the bounding box of a triangle.
+//
+// Parameters:
+//  vertices - the vertices of the triangle.
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func triangleBounds(vertices *[3]utils.Vec3) []float64 {
+	bb := make([]float64, 6)
+	for j := 0; j < 3; j++ {
+		bb[j] = math.Min(vertices[0][j], math.Min(vertices[1][j], vertices[2][j]))
+		bb[j+3] = math.Max(vertices[0][j], math.Max(vertices[1][j], vertices[2][j]))
+	}
+	return bb
+}
+
+// Intersect is a function to intersect the triangles of the Mesh, traversing its hierarchy.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the closest Hit.
+//  false if no triangle was intersected.
+//
+func (mesh *Mesh) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	return mesh.intersect(ray, tMin, tMax, nil)
+}
+
+// intersect is a function to intersect the triangles of the Mesh, counting the work done.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//  cost - counter of the boxes and triangles tested, may be nil.
+//
+// Returns:
+//  the closest Hit.
+//  false if no triangle was intersected.
+//
+func (mesh *Mesh) intersect(ray entity.Ray, tMin, tMax float64, cost *int) (Hit, bool) {
+	closest := triangleHit{t: tMax, triangle: -1}
+	if mesh.root == nil || !mesh.root.closestTriangle(mesh, ray, tMin, &closest, cost) {
+		return Hit{}, false
+	}
+	obj := mesh.Object
+	hit := Hit{T: closest.t, Triangle: closest.triangle}
+	hit.BCoords = []float64{1 - closest.u - closest.v, closest.u, closest.v}
+	hit.Point = ray.At(hit.T)
+	hit.Local = hit.Point
+	hit.Object = obj
+	hit.Shape = mesh
+	hit.UV, _ = obj.GetTexCoordByBaricentricCoords(hit.Triangle, hit.BCoords)
+	return hit, true
+}
+
+// Normal is a function to get the shading normal, with normal and bump maps applied.
+//
+// Parameters:
+//  hit - the hit on the Mesh.
+//
+// Returns:
+//  the normal.
+//
+func (mesh *Mesh) Normal(hit Hit) utils.Vec3 {
+	normal := mesh.Object.GetShadingNormal(hit.Triangle, hit.BCoords)
+	vec, _ := utils.VectorToVec3(&normal) // the normals are 3D after CheckIntegrity
+	return vec
+}
+
+// Area is a function to get the area of all triangles.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the area.
+//
+func (mesh *Mesh) Area() float64 {
+	if len(mesh.areas) == 0 {
+		return 0
+	}
+	return mesh.areas[len(mesh.areas)-1]
+}
+
+// Sample is a function to get a point uniformly distributed over the triangles.
+// The triangle is chosen by u1 proportionally to its area, and u1 is then reused inside the triangle.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the interpolated normal.
+//
+func (mesh *Mesh) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	return mesh.sample(mesh.areas, u1, u2)
+}
+
+// sample is a function to get a point of the triangles, choosing the triangle proportionally to a
+// cumulative area.
+//
+// Parameters:
+//  areas - the cumulative area of the triangles.
+//  u1    - random number in [0, 1).
+//  u2    - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the interpolated normal.
+//
+func (mesh *Mesh) sample(areas []float64, u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	target := u1 * areas[len(areas)-1]
+	triangIdx := sort.SearchFloat64s(areas, target)
+	if triangIdx >= len(areas) {
+		triangIdx = len(areas) - 1
+	}
+	start := 0.0
+	if triangIdx > 0 {
+		start = areas[triangIdx-1]
+	}
+	if area := areas[triangIdx] - start; area > 0 {
+		u1 = math.Min((target-start)/area, 1)
+	}
+
+	su := math.Sqrt(u1)
+	bCoords := []float64{1 - su, u2 * su, su * (1 - u2)}
+	vertices := mesh.triangles[triangIdx]
+	pos := vertices[0].Scale(bCoords[0]).AddScaled(vertices[1], bCoords[1]).AddScaled(vertices[2], bCoords[2])
+	normal := mesh.Object.GetNormalByBaricentricCoords(triangIdx, bCoords)
+	vec, _ := utils.VectorToVec3(&normal) // the normals are 3D after CheckIntegrity
+	return pos, vec
+}
+
+// transformedAreas is a function to get the cumulative area of the triangles moved by a matrix.
+//
+// Parameters:
+//  matrix - the transform.
+//
+// Returns:
+//  the cumulative areas.
+//
+func (mesh *Mesh) transformedAreas(matrix *utils.Matrix) []float64 {
+	areas := make([]float64, len(mesh.triangles))
+	total := 0.0
+	for i := range mesh.triangles {
+		vertices := &mesh.triangles[i]
+		edge1 := vertices[1].Sub(vertices[0]).TransformDirection(matrix)
+		edge2 := vertices[2].Sub(vertices[0]).TransformDirection(matrix)
+		total += edge1.Cross(edge2).Length() / 2
+		areas[i] = total
+	}
+	return areas
+}
+
+// Bounds is a function to get the bounding box of the Mesh.
+// The box is in the space of the vertices, the Transform of the object is applied by its Instance.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ], an empty box (min greater than max) without vertices.
+//
+func (mesh *Mesh) Bounds() []float64 {
+	if len(mesh.Object.Vertices.Points) == 0 {
+		return []float64{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	}
+	return mesh.Object.GetLocalBoundingBox()
+}
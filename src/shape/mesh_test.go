@@ -0,0 +1,111 @@
+package shape
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// randomMesh is a function to create an object with random triangles inside the unit cube.
+func randomMesh(random *rand.Rand, triangles int) *general.Object {
+	points := make([]entity.Point, 0, 3*triangles)
+	obj := &general.Object{Triangles: make([]entity.Triangle, triangles)}
+	for i := range obj.Triangles {
+		center := []float64{random.Float64(), random.Float64(), random.Float64()}
+		for j := 0; j < 3; j++ {
+			coordinates := make([]float64, 3)
+			for k := range coordinates {
+				coordinates[k] = center[k] + 0.2*(random.Float64()-0.5)
+			}
+			points = append(points, entity.Point{Coordinates: coordinates})
+		}
+		obj.Triangles[i] = entity.InitTriangle([]int{3 * i, 3*i + 1, 3*i + 2}, []int{0, 0, 0})
+	}
+	obj.Vertices = entity.InitVertices(points)
+	obj.Normals = []utils.Vector{{Coordinates: []float64{0, 0, 1}}}
+	return obj
+}
+
+// bruteForce is a function to find the closest triangle testing all of them.
+func bruteForce(mesh *Mesh, ray entity.Ray, tMin, tMax float64) (float64, int) {
+	closest, triangle := tMax, -1
+	for i := range mesh.triangles {
+		vertices := &mesh.triangles[i]
+		t, _, _, intersected := ray.IntersectTriangle(vertices[0], vertices[1], vertices[2])
+		if intersected && inRange(t, tMin, closest) {
+			closest, triangle = t, i
+		}
+	}
+	return closest, triangle
+}
+
+func TestMeshIntersectMatchesAllTriangles(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	mesh := InitMesh(randomMesh(random, 500))
+	hits := 0
+	for i := 0; i < 2000; i++ {
+		origin := utils.Vec3{random.Float64()*3 - 1, random.Float64()*3 - 1, random.Float64()*3 - 1}
+		target := utils.Vec3{random.Float64(), random.Float64(), random.Float64()}
+		ray := entity.Ray{Origin: origin, Direction: target.Sub(origin).Normalize()}
+		tMax := math.Inf(1)
+		if i%2 == 1 {
+			tMax = random.Float64() * 2
+		}
+		wantT, wantTriangle := bruteForce(mesh, ray, 0, tMax)
+		hit, found := mesh.Intersect(ray, 0, tMax)
+		if found != (wantTriangle >= 0) {
+			t.Fatalf("ray %d: found %v, want triangle %d", i, found, wantTriangle)
+		}
+		if !found {
+			continue
+		}
+		hits++
+		if hit.T != wantT || hit.Triangle != wantTriangle {
+			t.Fatalf("ray %d: got triangle %d at %g, want %d at %g", i, hit.Triangle, hit.T, wantTriangle, wantT)
+		}
+		sum := hit.BCoords[0] + hit.BCoords[1] + hit.BCoords[2]
+		if math.Abs(sum-1) > 1e-9 || hit.Point.Sub(ray.At(wantT)).Length() > 1e-12 {
+			t.Fatalf("ray %d: inconsistent hit %+v", i, hit)
+		}
+	}
+	if hits == 0 {
+		t.Fatalf("no ray hit the mesh")
+	}
+}
+
+func TestMeshWithoutTriangles(t *testing.T) {
+	mesh := InitMesh(&general.Object{})
+	ray := entity.Ray{Origin: utils.Vec3{0, 0, -1}, Direction: utils.Vec3{0, 0, 1}}
+	if _, found := mesh.Intersect(ray, 0, math.Inf(1)); found {
+		t.Errorf("empty mesh intersected")
+	}
+}
+
+func TestClosestCostCountsMeshNodes(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	mesh := InitMesh(randomMesh(random, 500))
+	meshes := InitList([]Shape{mesh})
+	instances := InitList([]Shape{instance(t, mesh, general.Transform{})})
+	ray := entity.Ray{Origin: utils.Vec3{0.5, 0.5, -1}, Direction: utils.Vec3{0, 0, 1}}
+
+	_, found, cost := meshes.ClosestCost(ray, 0, math.Inf(1))
+	if !found {
+		t.Fatal("the ray missed the mesh")
+	}
+	// the box of the list, the boxes of the mesh and a few triangles
+	if cost <= 2 || cost >= len(mesh.triangles) {
+		t.Errorf("got a cost of %d, want the nodes visited inside the mesh", cost)
+	}
+	if _, _, instanceCost := instances.ClosestCost(ray, 0, math.Inf(1)); instanceCost != cost {
+		t.Errorf("got a cost of %d through the instance, want %d", instanceCost, cost)
+	}
+	// missing the box of the list
+	ray.Origin = utils.Vec3{5, 5, -1}
+	if _, found, cost := meshes.ClosestCost(ray, 0, math.Inf(1)); found || cost != 1 {
+		t.Errorf("got %v with a cost of %d, want a miss with a cost of 1", found, cost)
+	}
+}
@@ -0,0 +1,278 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// TangentFrame is a function to get two unit vectors ortogonal to a normal and to each other.
+//
+// Parameters:
+//  normal - the normalized normal.
+//
+// Returns:
+//  the tangent.
+//  the bitangent.
+//
+func TangentFrame(normal utils.Vec3) (utils.Vec3, utils.Vec3) {
+	axis := utils.Vec3{1, 0, 0}
+	if math.Abs(normal[0]) > 0.9 {
+		axis = utils.Vec3{0, 1, 0}
+	}
+	tangent := axis.AddScaled(normal, -axis.Dot(normal)).Normalize()
+	return tangent, normal.Cross(tangent)
+}
+
+// Plane is a class for infinite analytic planes.
+//
+// Members:
+//  Point     - a point of the plane.
+//  Norm      - the normalized normal.
+//  Object    - the object holding the material.
+//  tangent   - first axis of the texture coordinates.
+//  bitangent - second axis of the texture coordinates.
+//
+type Plane struct {
+	Point     utils.Vec3
+	Norm      utils.Vec3
+	Object    *general.Object
+	tangent   utils.Vec3
+	bitangent utils.Vec3
+}
+
+// InitPlane is a function to initialize a Plane.
+//
+// Parameters:
+//  point  - a point of the plane.
+//  normal - the normal.
+//  obj    - the object holding the material.
+//
+// Returns:
+//  the Plane.
+//
+func InitPlane(point, normal utils.Vec3, obj *general.Object) *Plane {
+	normal = normal.Normalize()
+	tangent, bitangent := TangentFrame(normal)
+	return &Plane{Point: point, Norm: normal, Object: obj, tangent: tangent, bitangent: bitangent}
+}
+
+// intersectPlane is a function to intersect the plane through a point with a normal.
+//
+// Parameters:
+//  ray    - the ray.
+//  point  - a point of the plane.
+//  normal - the normal.
+//  tMin   - the smallest t accepted.
+//  tMax   - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the ray t parameter.
+//  the position.
+//  false if there is no accepted intersection.
+//
+func intersectPlane(ray entity.Ray, point, normal utils.Vec3, tMin, tMax float64) (float64, utils.Vec3, bool) {
+	t, intersected := ray.IntersectPlane(point, normal)
+	if !intersected || !inRange(t, tMin, tMax) {
+		return 0, utils.Vec3{}, false
+	}
+	return t, ray.At(t), true
+}
+
+// Intersect is a function to intersect the Plane.
+// Texture coordinates are the distances to the Point along the tangent frame, one unit per repetition.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the Hit.
+//  false if the plane was not intersected.
+//
+func (plane *Plane) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	t, pos, found := intersectPlane(ray, plane.Point, plane.Norm, tMin, tMax)
+	if !found {
+		return Hit{}, false
+	}
+	d := pos.Sub(plane.Point)
+	uv := []float64{d.Dot(plane.tangent), d.Dot(plane.bitangent)}
+	return Hit{T: t, Point: pos, Local: pos, Object: plane.Object, Shape: plane, Triangle: -1, UV: uv}, true
+}
+
+// Normal is a function to get the normal of the Plane.
+//
+// Parameters:
+//  hit - the hit on the Plane.
+//
+// Returns:
+//  the normal.
+//
+func (plane *Plane) Normal(hit Hit) utils.Vec3 {
+	return plane.Norm
+}
+
+// Area is a function to get the area of the Plane.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  +Inf.
+//
+func (plane *Plane) Area() float64 {
+	return math.Inf(1)
+}
+
+// Sample is a function to sample the Plane, since an infinite plane can not be sampled uniformly the Point is returned.
+//
+// Parameters:
+//  u1 - ignored.
+//  u2 - ignored.
+//
+// Returns:
+//  the Point.
+//  the normal.
+//
+func (plane *Plane) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	return plane.Point, plane.Norm
+}
+
+// Bounds is a function to get the bounding box of the Plane.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  an infinite box.
+//
+func (plane *Plane) Bounds() []float64 {
+	return []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1), math.Inf(1)}
+}
+
+// Disk is a class for analytic disks.
+//
+// Members:
+//  Center    - the center.
+//  Norm      - the normalized normal.
+//  Radius    - the radius.
+//  Object    - the object holding the material.
+//  tangent   - first axis of the disk.
+//  bitangent - second axis of the disk.
+//
+type Disk struct {
+	Center    utils.Vec3
+	Norm      utils.Vec3
+	Radius    float64
+	Object    *general.Object
+	tangent   utils.Vec3
+	bitangent utils.Vec3
+}
+
+// InitDisk is a function to initialize a Disk.
+//
+// Parameters:
+//  center - the center.
+//  normal - the normal.
+//  radius - the radius.
+//  obj    - the object holding the material.
+//
+// Returns:
+//  the Disk.
+//
+func InitDisk(center, normal utils.Vec3, radius float64, obj *general.Object) *Disk {
+	normal = normal.Normalize()
+	tangent, bitangent := TangentFrame(normal)
+	return &Disk{Center: center, Norm: normal, Radius: radius, Object: obj, tangent: tangent, bitangent: bitangent}
+}
+
+// Intersect is a function to intersect the Disk.
+// Texture coordinates map the square around the disk to [0, 1].
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the Hit.
+//  false if the disk was not intersected.
+//
+func (disk *Disk) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	t, pos, found := intersectPlane(ray, disk.Center, disk.Norm, tMin, tMax)
+	if !found {
+		return Hit{}, false
+	}
+	d := pos.Sub(disk.Center)
+	if d.Dot(d) > disk.Radius*disk.Radius {
+		return Hit{}, false
+	}
+	uv := []float64{
+		0.5 + d.Dot(disk.tangent)/(2*disk.Radius),
+		0.5 + d.Dot(disk.bitangent)/(2*disk.Radius),
+	}
+	return Hit{T: t, Point: pos, Local: pos, Object: disk.Object, Shape: disk, Triangle: -1, UV: uv}, true
+}
+
+// Normal is a function to get the normal of the Disk.
+//
+// Parameters:
+//  hit - the hit on the Disk.
+//
+// Returns:
+//  the normal.
+//
+func (disk *Disk) Normal(hit Hit) utils.Vec3 {
+	return disk.Norm
+}
+
+// Area is a function to get the area of the Disk.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the area.
+//
+func (disk *Disk) Area() float64 {
+	return math.Pi * disk.Radius * disk.Radius
+}
+
+// Sample is a function to get a point uniformly distributed over the Disk.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the normal.
+//
+func (disk *Disk) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	r := disk.Radius * math.Sqrt(u1)
+	theta := 2 * math.Pi * u2
+	pos := disk.Center.AddScaled(disk.tangent, r*math.Cos(theta)).AddScaled(disk.bitangent, r*math.Sin(theta))
+	return pos, disk.Norm
+}
+
+// Bounds is a function to get the bounding box of the Disk.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func (disk *Disk) Bounds() []float64 {
+	bb := make([]float64, 6)
+	for j := 0; j < 3; j++ {
+		n := disk.Norm[j]
+		extent := disk.Radius * math.Sqrt(math.Max(0, 1-n*n))
+		bb[j] = disk.Center[j] - extent
+		bb[j+3] = disk.Center[j] + extent
+	}
+	return bb
+}
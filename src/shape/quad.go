@@ -0,0 +1,127 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Quad is a class for analytic parallelograms.
+//
+// Members:
+//  Corner - the first corner.
+//  EdgeU  - the edge leaving the Corner along the u texture coordinate.
+//  EdgeV  - the edge leaving the Corner along the v texture coordinate.
+//  Norm   - the normalized normal (EdgeU x EdgeV).
+//  Object - the object holding the material.
+//  w      - the normal divided by its squared length, used to find the edge coordinates.
+//
+type Quad struct {
+	Corner utils.Vec3
+	EdgeU  utils.Vec3
+	EdgeV  utils.Vec3
+	Norm   utils.Vec3
+	Object *general.Object
+	w      utils.Vec3
+}
+
+// InitQuad is a function to initialize a Quad.
+//
+// Parameters:
+//  corner - the first corner.
+//  edgeU  - the first edge.
+//  edgeV  - the second edge.
+//  obj    - the object holding the material.
+//
+// Returns:
+//  the Quad.
+//
+func InitQuad(corner, edgeU, edgeV utils.Vec3, obj *general.Object) *Quad {
+	cross := edgeU.Cross(edgeV)
+	w := cross.Scale(1 / cross.Dot(cross))
+	return &Quad{Corner: corner, EdgeU: edgeU, EdgeV: edgeV, Norm: cross.Normalize(), Object: obj, w: w}
+}
+
+// Intersect is a function to intersect the Quad.
+// Texture coordinates are the position along each edge in [0, 1].
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the Hit.
+//  false if the quad was not intersected.
+//
+func (quad *Quad) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	t, pos, found := intersectPlane(ray, quad.Corner, quad.Norm, tMin, tMax)
+	if !found {
+		return Hit{}, false
+	}
+	d := pos.Sub(quad.Corner)
+	a := quad.w.Dot(d.Cross(quad.EdgeV))
+	b := quad.w.Dot(quad.EdgeU.Cross(d))
+	if a < 0 || a > 1 || b < 0 || b > 1 {
+		return Hit{}, false
+	}
+	return Hit{T: t, Point: pos, Local: pos, Object: quad.Object, Shape: quad, Triangle: -1, UV: []float64{a, b}}, true
+}
+
+// Normal is a function to get the normal of the Quad.
+//
+// Parameters:
+//  hit - the hit on the Quad.
+//
+// Returns:
+//  the normal.
+//
+func (quad *Quad) Normal(hit Hit) utils.Vec3 {
+	return quad.Norm
+}
+
+// Area is a function to get the area of the Quad.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the area.
+//
+func (quad *Quad) Area() float64 {
+	return quad.EdgeU.Cross(quad.EdgeV).Length()
+}
+
+// Sample is a function to get a point uniformly distributed over the Quad.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the normal.
+//
+func (quad *Quad) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	return quad.Corner.AddScaled(quad.EdgeU, u1).AddScaled(quad.EdgeV, u2), quad.Norm
+}
+
+// Bounds is a function to get the bounding box of the Quad.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func (quad *Quad) Bounds() []float64 {
+	bb := make([]float64, 6)
+	for j := 0; j < 3; j++ {
+		u, v := quad.EdgeU[j], quad.EdgeV[j]
+		bb[j] = quad.Corner[j] + math.Min(0, u) + math.Min(0, v)
+		bb[j+3] = quad.Corner[j] + math.Max(0, u) + math.Max(0, v)
+	}
+	return bb
+}
@@ -0,0 +1,348 @@
+package shape
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Epsilon is the smallest t accepted by an intersection, avoiding rays hitting the surface they leave.
+const Epsilon = 1e-6
+
+// Hit is a class for the intersection of a ray with a shape.
+//
+// Members:
+//  T        - the ray t parameter (A + tV).
+//  Point    - the position of the intersection.
+//  Local    - the position of the intersection in the space of the geometry, before the transforms
+//             of the instances.
+//  Object   - the object holding the material of the shape.
+//  Shape    - the shape intersected.
+//  Index    - the index of the shape on its List.
+//  Triangle - the index of the triangle on meshes, -1 on analytic shapes.
+//  BCoords  - the baricentric coordinates of the triangle on meshes.
+//  UV       - the texture coordinates, nil when there are none.
+//
+type Hit struct {
+	T        float64
+	Point    utils.Vec3
+	Local    utils.Vec3
+	Object   *general.Object
+	Shape    Shape
+	Index    int
+	Triangle int
+	BCoords  []float64
+	UV       []float64
+}
+
+// Shape is an interface for surfaces that can be rendered.
+//
+// Methods:
+//  Intersect - the closest intersection with t in [tMin, tMax).
+//  Normal    - the normalized shading normal at a hit.
+//  Area      - the surface area, infinite for unbounded shapes.
+//  Sample    - an uniformly distributed point of the surface and its normal from two random numbers in [0, 1).
+//  Bounds    - the bounding box [minX, minY, minZ, maxX, maxY, maxZ].
+//
+type Shape interface {
+	Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool)
+	Normal(hit Hit) utils.Vec3
+	Area() float64
+	Sample(u1, u2 float64) (utils.Vec3, utils.Vec3)
+	Bounds() []float64
+}
+
+// inRange is a function to check if an intersection is inside the accepted interval.
+//
+// Parameters:
+//  t    - the ray t parameter.
+//  tMin - the smallest t accepted.
+//  tMax - the t of the closest hit so far.
+//
+// Returns:
+//  true if the intersection is accepted.
+//
+func inRange(t, tMin, tMax float64) bool {
+	return t >= tMin && t > Epsilon && t < tMax
+}
+
+// toVec3 is a function to convert a slice of 3 coordinates to a Vec3.
+//
+// Parameters:
+//  coordinates - the coordinates.
+//
+// Returns:
+//  the Vec3.
+//
+func toVec3(coordinates []float64) utils.Vec3 {
+	return utils.Vec3{coordinates[0], coordinates[1], coordinates[2]}
+}
+
+// FromObject is a function to get the shape of an object, its Primitive or its triangles,
+// placed by an Instance when the object has a Transform.
+//
+// Parameters:
+//  obj - the object, already checked by CheckIntegrity.
+//
+// Returns:
+//  the Shape.
+//
+func FromObject(obj *general.Object) Shape {
+	base := fromGeometry(obj)
+	if obj.Transform == nil {
+		return base
+	}
+	toWorld, toObject, _ := obj.Transform.GetMatrices()
+	return InitInstance(obj.Name, base, obj, toWorld, toObject)
+}
+
+// fromGeometry is a function to get the shape of an object ignoring its Transform.
+//
+// Parameters:
+//  obj - the object.
+//
+// Returns:
+//  the Shape.
+//
+func fromGeometry(obj *general.Object) Shape {
+	prim := obj.Primitive
+	if prim == nil {
+		return InitMesh(obj)
+	}
+	switch prim.Type {
+	case general.PrimitiveSphere:
+		return InitSphere(toVec3(prim.Center), prim.Radius, obj)
+	case general.PrimitivePlane:
+		return InitPlane(toVec3(prim.Center), toVec3(prim.Normal), obj)
+	case general.PrimitiveDisk:
+		return InitDisk(toVec3(prim.Center), toVec3(prim.Normal), prim.Radius, obj)
+	}
+	return InitQuad(toVec3(prim.Corner), toVec3(prim.Edges[0]), toVec3(prim.Edges[1]), obj)
+}
+
+// List is a class for a group of shapes intersected together.
+// Lists built by InitList use a bounding volume hierarchy over the bounded shapes.
+//
+// Members:
+//  Shapes    - the shapes.
+//  root      - the root of the hierarchy, nil when it was not built.
+//  unbounded - indices of the shapes with infinite bounds, tested on every ray.
+//
+type List struct {
+	Shapes    []Shape
+	root      *bvhNode
+	unbounded []int
+}
+
+// InitList is a function to initialize a List and build its hierarchy.
+//
+// Parameters:
+//  shapes - the shapes.
+//
+// Returns:
+//  the List.
+//
+func InitList(shapes []Shape) List {
+	list := List{Shapes: shapes, unbounded: make([]int, 0)}
+	bounds := make([][]float64, len(shapes))
+	bounded := make([]int, 0, len(shapes))
+	for i, s := range shapes {
+		bounds[i] = s.Bounds()
+		infinite := false
+		for _, value := range bounds[i] {
+			infinite = infinite || math.IsInf(value, 0)
+		}
+		if infinite {
+			list.unbounded = append(list.unbounded, i)
+		} else {
+			bounded = append(bounded, i)
+		}
+	}
+	if len(bounded) > 0 {
+		list.root = buildBVH(bounded, bounds)
+	}
+	return list
+}
+
+// FromObjects is a function to build a List with the shapes of the objects followed by the instances.
+// The shape of a mesh is built once and shared by all its instances.
+//
+// Parameters:
+//  objs - the objects.
+//
+// Returns:
+//  the List, the shape of ObjList[i] has index i.
+//  an error if an instance is invalid.
+//
+func FromObjects(objs *general.Objects) (List, error) {
+	shapes := make([]Shape, 0, len(objs.ObjList)+len(objs.Instances))
+	for i := range objs.ObjList {
+		shapes = append(shapes, FromObject(&objs.ObjList[i]))
+	}
+	meshes := make(map[string]Shape)
+	for i := range objs.Instances {
+		inst := &objs.Instances[i]
+		obj, err := objs.GetInstanceObject(inst)
+		if err != nil {
+			return List{}, utils.WrapField(fmt.Sprintf("Instances[%d]", i), err)
+		}
+		toWorld, toObject, err := inst.GetTransform()
+		if err != nil {
+			return List{}, utils.WrapField(fmt.Sprintf("Instances[%d]", i), err)
+		}
+		base, found := meshes[inst.Mesh]
+		if !found {
+			mesh, _ := objs.FindMesh(inst.Mesh)
+			base = FromObject(mesh)
+			meshes[inst.Mesh] = base
+		}
+		shapes = append(shapes, InitInstance(inst.Name, base, obj, toWorld, toObject))
+	}
+	return InitList(shapes), nil
+}
+
+// Closest is a function to find the closest intersection of a ray with the shapes.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the closest Hit.
+//  false if no shape was intersected.
+//
+func (list *List) Closest(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	return list.closest(ray, tMin, tMax, nil)
+}
+
+// ClosestCost is a function to find the closest intersection of a ray with the shapes, counting the
+// work done by the traversal.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the closest Hit.
+//  false if no shape was intersected.
+//  the number of bounding boxes and primitives tested, the boxes of the meshes and instances included
+//  and each triangle being a primitive.
+//
+func (list *List) ClosestCost(ray entity.Ray, tMin, tMax float64) (Hit, bool, int) {
+	cost := 0
+	hit, found := list.closest(ray, tMin, tMax, &cost)
+	return hit, found, cost
+}
+
+// closest is a function to find the closest intersection of a ray with the shapes.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//  cost - counter of the boxes and primitives tested, may be nil.
+//
+// Returns:
+//  the closest Hit.
+//  false if no shape was intersected.
+//
+func (list *List) closest(ray entity.Ray, tMin, tMax float64, cost *int) (Hit, bool) {
+	closest := Hit{T: tMax}
+	found := false
+	candidates := list.unbounded
+	if list.root == nil && list.unbounded == nil {
+		// not built by InitList, testing every shape
+		candidates = make([]int, len(list.Shapes))
+		for i := range candidates {
+			candidates[i] = i
+		}
+	}
+	for _, i := range candidates {
+		hit, intersected := intersect(list.Shapes[i], ray, tMin, closest.T, cost)
+		if intersected {
+			hit.Index = i
+			closest = hit
+			found = true
+		}
+	}
+	if list.root != nil && list.root.closest(list, ray, tMin, &closest, cost) {
+		found = true
+	}
+	return closest, found
+}
+
+// intersect is a function to intersect a shape, counting the work done by the hierarchies of the meshes.
+//
+// Parameters:
+//  s    - the shape.
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//  cost - counter of the boxes and primitives tested, may be nil.
+//
+// Returns:
+//  the Hit.
+//  false if the shape was not intersected.
+//
+func intersect(s Shape, ray entity.Ray, tMin, tMax float64, cost *int) (Hit, bool) {
+	switch shp := s.(type) {
+	case *Mesh:
+		return shp.intersect(ray, tMin, tMax, cost)
+	case *Instance:
+		return shp.intersect(ray, tMin, tMax, cost)
+	}
+	if cost != nil {
+		*cost++
+	}
+	return s.Intersect(ray, tMin, tMax)
+}
+
+// GeometricNormal is a function to get the normal of the surface at a hit, without the interpolated
+// vertex normals and the normal and bump maps.
+//
+// Parameters:
+//  hit - the hit.
+//
+// Returns:
+//  the normalized normal, given by the winding of the triangle on meshes.
+//
+func GeometricNormal(hit Hit) utils.Vec3 {
+	switch shp := hit.Shape.(type) {
+	case *Mesh:
+		if hit.Triangle >= 0 && hit.Triangle < len(shp.triangles) {
+			vertices := &shp.triangles[hit.Triangle]
+			return vertices[1].Sub(vertices[0]).Cross(vertices[2].Sub(vertices[0])).Normalize()
+		}
+	case *Instance:
+		localHit := hit
+		localHit.Point = hit.Point.TransformPoint(&shp.toObject)
+		localHit.Shape = shp.Shape
+		return GeometricNormal(localHit).TransformDirection(&shp.normalMatrix).Normalize()
+	}
+	return hit.Shape.Normal(hit)
+}
+
+// Bounds is a function to get the bounding box of all shapes.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func (list *List) Bounds() []float64 {
+	bb := []float64{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, s := range list.Shapes {
+		sbb := s.Bounds()
+		for j := 0; j < 3; j++ {
+			bb[j] = math.Min(bb[j], sbb[j])
+			bb[j+3] = math.Max(bb[j+3], sbb[j+3])
+		}
+	}
+	return bb
+}
@@ -0,0 +1,132 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Sphere is a class for analytic spheres.
+//
+// Members:
+//  Center - the center.
+//  Radius - the radius.
+//  Object - the object holding the material.
+//
+type Sphere struct {
+	Center utils.Vec3
+	Radius float64
+	Object *general.Object
+}
+
+// InitSphere is a function to initialize a Sphere.
+//
+// Parameters:
+//  center - the center.
+//  radius - the radius.
+//  obj    - the object holding the material.
+//
+// Returns:
+//  the Sphere.
+//
+func InitSphere(center utils.Vec3, radius float64, obj *general.Object) *Sphere {
+	return &Sphere{Center: center, Radius: radius, Object: obj}
+}
+
+// Intersect is a function to intersect the Sphere.
+// Texture coordinates are the longitude (u) and latitude (v) around the y axis.
+//
+// Parameters:
+//  ray  - the ray.
+//  tMin - the smallest t accepted.
+//  tMax - the greatest t accepted (exclusive).
+//
+// Returns:
+//  the closest Hit.
+//  false if the sphere was not intersected.
+//
+func (sphere *Sphere) Intersect(ray entity.Ray, tMin, tMax float64) (Hit, bool) {
+	t0, t1, intersected := ray.IntersectSphere(sphere.Center, sphere.Radius)
+	if !intersected {
+		return Hit{}, false
+	}
+	hit := Hit{T: tMax, Triangle: -1}
+	if inRange(t0, tMin, tMax) {
+		hit.T = t0
+	} else if inRange(t1, tMin, tMax) {
+		hit.T = t1
+	} else {
+		return Hit{}, false
+	}
+	hit.Point = ray.At(hit.T)
+	hit.Local = hit.Point
+	hit.Object = sphere.Object
+	hit.Shape = sphere
+	d := hit.Point.Sub(sphere.Center).Normalize()
+	hit.UV = []float64{
+		0.5 + math.Atan2(d[2], d[0])/(2*math.Pi),
+		0.5 + math.Asin(math.Max(-1, math.Min(1, d[1])))/math.Pi,
+	}
+	return hit, true
+}
+
+// Normal is a function to get the outward normal.
+//
+// Parameters:
+//  hit - the hit on the Sphere.
+//
+// Returns:
+//  the normal.
+//
+func (sphere *Sphere) Normal(hit Hit) utils.Vec3 {
+	return hit.Point.Sub(sphere.Center).Normalize()
+}
+
+// Area is a function to get the area of the Sphere.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the area.
+//
+func (sphere *Sphere) Area() float64 {
+	return 4 * math.Pi * sphere.Radius * sphere.Radius
+}
+
+// Sample is a function to get a point uniformly distributed over the Sphere.
+//
+// Parameters:
+//  u1 - random number in [0, 1).
+//  u2 - random number in [0, 1).
+//
+// Returns:
+//  the point.
+//  the normal.
+//
+func (sphere *Sphere) Sample(u1, u2 float64) (utils.Vec3, utils.Vec3) {
+	z := 1 - 2*u1
+	r := math.Sqrt(math.Max(0, 1-z*z))
+	phi := 2 * math.Pi * u2
+	normal := utils.Vec3{r * math.Cos(phi), r * math.Sin(phi), z}
+	return sphere.Center.AddScaled(normal, sphere.Radius), normal
+}
+
+// Bounds is a function to get the bounding box of the Sphere.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  [minX, minY, minZ, maxX, maxY, maxZ].
+//
+func (sphere *Sphere) Bounds() []float64 {
+	bb := make([]float64, 6)
+	for j := 0; j < 3; j++ {
+		bb[j] = sphere.Center[j] - sphere.Radius
+		bb[j+3] = sphere.Center[j] + sphere.Radius
+	}
+	return bb
+}
@@ -0,0 +1,207 @@
+package texture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rgbeToFloat is a function to convert a shared exponent pixel of a Radiance file to RGB.
+//
+// Parameters:
+//  rgbe - the mantissas of red, green and blue and the exponent.
+//  rgb  - the slice receiving the RGB.
+//
+// Returns:
+//  none
+//
+func rgbeToFloat(rgbe []byte, rgb []float64) {
+	if rgbe[3] == 0 {
+		rgb[0], rgb[1], rgb[2] = 0, 0, 0
+		return
+	}
+	f := math.Ldexp(1, int(rgbe[3])-(128+8))
+	for i := 0; i < 3; i++ {
+		rgb[i] = (float64(rgbe[i]) + 0.5) * f
+	}
+}
+
+// readHDRScanline is a function to read a line of RGBE pixels, run length encoded or not.
+//
+// Parameters:
+//  reader - the file reader.
+//  line   - the slice receiving the 4 bytes of every pixel.
+//
+// Returns:
+//  an error.
+//
+func readHDRScanline(reader *bufio.Reader, line []byte) error {
+	width := len(line) / 4
+	if _, err := io.ReadFull(reader, line[:4]); err != nil {
+		return err
+	}
+	if width < 8 || width > 0x7fff || line[0] != 2 || line[1] != 2 || line[2]&0x80 != 0 {
+		// flat pixels
+		_, err := io.ReadFull(reader, line[4:])
+		return err
+	}
+	if int(line[2])<<8|int(line[3]) != width {
+		return errors.New("hdr scanline width mismatch")
+	}
+	// the channels are stored one after the other, each one as runs and literals
+	channel := make([]byte, width)
+	for c := 0; c < 4; c++ {
+		for x := 0; x < width; {
+			count, err := reader.ReadByte()
+			if err != nil {
+				return err
+			}
+			if count > 128 {
+				n := int(count) - 128
+				value, err := reader.ReadByte()
+				if err != nil {
+					return err
+				}
+				if x+n > width {
+					return errors.New("hdr run past the end of the scanline")
+				}
+				for i := 0; i < n; i++ {
+					channel[x+i] = value
+				}
+				x += n
+			} else {
+				n := int(count)
+				if n == 0 || x+n > width {
+					return errors.New("invalid hdr literal count")
+				}
+				if _, err := io.ReadFull(reader, channel[x:x+n]); err != nil {
+					return err
+				}
+				x += n
+			}
+		}
+		for x := 0; x < width; x++ {
+			line[x*4+c] = channel[x]
+		}
+	}
+	return nil
+}
+
+// loadHDR is a function to read a Radiance RGBE (.hdr) file.
+//
+// Parameters:
+//  file - the opened file.
+//
+// Returns:
+//  the width, height and linear RGB values.
+//  an error.
+//
+func loadHDR(file io.Reader) (int, int, []float64, error) {
+	reader := bufio.NewReader(file)
+	magic, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if !strings.HasPrefix(magic, "#?") {
+		return 0, 0, nil, errors.New("missing radiance header")
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return 0, 0, nil, fmt.Errorf("unsupported hdr format %q", strings.TrimPrefix(line, "FORMAT="))
+		}
+	}
+	resolution, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	fields := strings.Fields(resolution)
+	if len(fields) != 4 || fields[0] != "-Y" || fields[2] != "+X" {
+		return 0, 0, nil, fmt.Errorf("unsupported hdr resolution %q", strings.TrimSpace(resolution))
+	}
+	height, errH := strconv.Atoi(fields[1])
+	width, errW := strconv.Atoi(fields[3])
+	if errH != nil || errW != nil || width <= 0 || height <= 0 {
+		return 0, 0, nil, fmt.Errorf("invalid hdr resolution %q", strings.TrimSpace(resolution))
+	}
+	values := make([]float64, width*height*3)
+	line := make([]byte, width*4)
+	for y := 0; y < height; y++ {
+		if err := readHDRScanline(reader, line); err != nil {
+			return 0, 0, nil, err
+		}
+		for x := 0; x < width; x++ {
+			idx := (y*width + x) * 3
+			rgbeToFloat(line[x*4:x*4+4], values[idx:idx+3])
+		}
+	}
+	return width, height, values, nil
+}
+
+// loadPFM is a function to read a color (PF) or grayscale (Pf) portable float map.
+//
+// Parameters:
+//  file - the opened file.
+//
+// Returns:
+//  the width, height and linear RGB values.
+//  an error.
+//
+func loadPFM(file io.Reader) (int, int, []float64, error) {
+	reader := bufio.NewReader(file)
+	magic, err := readPPMToken(reader)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if magic != "PF" && magic != "Pf" {
+		return 0, 0, nil, fmt.Errorf("unsupported pfm format %q", magic)
+	}
+	header := make([]string, 3)
+	for i := range header {
+		if header[i], err = readPPMToken(reader); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	width, errW := strconv.Atoi(header[0])
+	height, errH := strconv.Atoi(header[1])
+	scale, errS := strconv.ParseFloat(header[2], 64)
+	if errW != nil || errH != nil || errS != nil || width <= 0 || height <= 0 || scale == 0 {
+		return 0, 0, nil, fmt.Errorf("invalid pfm header %q", strings.Join(header, " "))
+	}
+	// a negative scale means little endian
+	var order binary.ByteOrder = binary.BigEndian
+	if scale < 0 {
+		order = binary.LittleEndian
+	}
+	channels := 3
+	if magic == "Pf" {
+		channels = 1
+	}
+	row := make([]float32, width*channels)
+	values := make([]float64, width*height*3)
+	// the lines are stored from the bottom
+	for y := height - 1; y >= 0; y-- {
+		if err := binary.Read(reader, order, row); err != nil {
+			return 0, 0, nil, err
+		}
+		for x := 0; x < width; x++ {
+			idx := (y*width + x) * 3
+			for i := 0; i < 3; i++ {
+				values[idx+i] = float64(row[x*channels+i%channels])
+			}
+		}
+	}
+	return width, height, values, nil
+}
@@ -0,0 +1,347 @@
+package texture
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	_ "image/jpeg" // registering the jpeg decoder
+	_ "image/png"  // registering the png decoder
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Wrap modes for texture coordinates outside [0, 1].
+const (
+	WrapRepeat = "repeat"
+	WrapClamp  = "clamp"
+	WrapMirror = "mirror"
+)
+
+// Image is a class for image textures sampled with bilinear filtering.
+//
+// Members:
+//  Path   - path to the PNG, JPEG, PPM, Radiance HDR or PFM file.
+//  Wrap   - the wrap mode (repeat, clamp or mirror), repeat when empty.
+//  Linear - true when the file stores linear data (normal and height maps) instead of sRGB colors,
+//           HDR and PFM files are always linear.
+//  width  - number of columns.
+//  height - number of lines.
+//  pixels - linear RGB values, in [0, 1] except for HDR and PFM files, line by line from the top.
+//
+type Image struct {
+	Path   string
+	Wrap   string
+	Linear bool
+	width  int
+	height int
+	pixels []float64
+}
+
+// srgbToLinear is a function to convert a sRGB component to linear.
+//
+// Parameters:
+//  c - the component in [0, 1].
+//
+// Returns:
+//  the linear component.
+//
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// readPPMToken is a function to read the next token of a PPM header, skipping comments.
+//
+// Parameters:
+//  reader - the file reader.
+//
+// Returns:
+//  the token.
+//  an error.
+//
+func readPPMToken(reader *bufio.Reader) (string, error) {
+	token := ""
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && token != "" {
+				return token, nil
+			}
+			return "", err
+		}
+		switch {
+		case b == '#' && token == "":
+			if _, err := reader.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if token != "" {
+				return token, nil
+			}
+		default:
+			token += string(b)
+		}
+	}
+}
+
+// loadPPM is a function to read a P3 or P6 file.
+//
+// Parameters:
+//  file - the opened file.
+//
+// Returns:
+//  the width, height and RGB values in [0, 1].
+//  an error.
+//
+func loadPPM(file io.Reader) (int, int, []float64, error) {
+	reader := bufio.NewReader(file)
+	header := make([]int, 3)
+	magic, err := readPPMToken(reader)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if magic != "P3" && magic != "P6" {
+		return 0, 0, nil, fmt.Errorf("unsupported ppm format %q", magic)
+	}
+	for i := range header {
+		token, err := readPPMToken(reader)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		header[i], err = strconv.Atoi(token)
+		if err != nil || header[i] <= 0 {
+			return 0, 0, nil, fmt.Errorf("invalid ppm header value %q", token)
+		}
+	}
+	width, height, maxVal := header[0], header[1], float64(header[2])
+	values := make([]float64, width*height*3)
+	for i := range values {
+		if magic == "P3" {
+			token, err := readPPMToken(reader)
+			if err != nil {
+				return 0, 0, nil, err
+			}
+			value, err := strconv.Atoi(token)
+			if err != nil {
+				return 0, 0, nil, fmt.Errorf("invalid ppm value %q", token)
+			}
+			values[i] = float64(value) / maxVal
+		} else if maxVal < 256 {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return 0, 0, nil, err
+			}
+			values[i] = float64(b) / maxVal
+		} else {
+			hi, err := reader.ReadByte()
+			if err != nil {
+				return 0, 0, nil, err
+			}
+			lo, err := reader.ReadByte()
+			if err != nil {
+				return 0, 0, nil, err
+			}
+			values[i] = float64(int(hi)<<8|int(lo)) / maxVal
+		}
+	}
+	return width, height, values, nil
+}
+
+// loadDecoded is a function to read a file with the image package decoders.
+//
+// Parameters:
+//  file - the opened file.
+//
+// Returns:
+//  the width, height and RGB values in [0, 1].
+//  an error.
+//
+func loadDecoded(file io.Reader) (int, int, []float64, error) {
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	values := make([]float64, width*height*3)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			idx := (y*width + x) * 3
+			values[idx] = float64(r) / 0xffff
+			values[idx+1] = float64(g) / 0xffff
+			values[idx+2] = float64(b) / 0xffff
+		}
+	}
+	return width, height, values, nil
+}
+
+// Load is a function to read the pixels of the Image from its Path.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  an error.
+//
+func (img *Image) Load() error {
+	if img.Wrap != "" && img.Wrap != WrapRepeat && img.Wrap != WrapClamp && img.Wrap != WrapMirror {
+		return fmt.Errorf("%s: invalid wrap mode %q", img.Path, img.Wrap)
+	}
+	file, err := os.Open(img.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var values []float64
+	linear := img.Linear
+	switch strings.ToLower(filepath.Ext(img.Path)) {
+	case ".ppm":
+		img.width, img.height, values, err = loadPPM(file)
+	case ".hdr":
+		img.width, img.height, values, err = loadHDR(file)
+		linear = true
+	case ".pfm":
+		img.width, img.height, values, err = loadPFM(file)
+		linear = true
+	default:
+		img.width, img.height, values, err = loadDecoded(file)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s", img.Path, err)
+	}
+	if !linear {
+		for i := range values {
+			values[i] = srgbToLinear(values[i])
+		}
+	}
+	img.pixels = values
+	return nil
+}
+
+// Loaded is a function to check if the pixels of the Image were read.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true if the image can be sampled.
+//
+func (img *Image) Loaded() bool {
+	return img.pixels != nil
+}
+
+// Size is a function to get the dimensions of the Image.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the width and height.
+//
+func (img *Image) Size() (int, int) {
+	return img.width, img.height
+}
+
+// wrapIndex is a function to bring a texel index inside the image using the wrap mode.
+//
+// Parameters:
+//  idx  - the index.
+//  size - the number of texels.
+//
+// Returns:
+//  the index inside [0, size).
+//
+func (img *Image) wrapIndex(idx, size int) int {
+	switch img.Wrap {
+	case WrapClamp:
+		if idx < 0 {
+			return 0
+		} else if idx >= size {
+			return size - 1
+		}
+		return idx
+	case WrapMirror:
+		period := 2 * size
+		idx = ((idx % period) + period) % period
+		if idx >= size {
+			idx = period - 1 - idx
+		}
+		return idx
+	}
+	return ((idx % size) + size) % size
+}
+
+// Texel is a function to get the RGB of a texel.
+//
+// Parameters:
+//  x - the column, wrapped if needed.
+//  y - the line, wrapped if needed.
+//
+// Returns:
+//  the RGB.
+//
+func (img *Image) Texel(x, y int) []float64 {
+	x = img.wrapIndex(x, img.width)
+	y = img.wrapIndex(y, img.height)
+	idx := (y*img.width + x) * 3
+	return img.pixels[idx : idx+3]
+}
+
+// Sample is a function to get the bilinear filtered RGB at a texture coordinate.
+// The v axis points up, so v = 0 is the bottom line of the image.
+//
+// Parameters:
+//  u - the horizontal coordinate.
+//  v - the vertical coordinate.
+//
+// Returns:
+//  the RGB.
+//
+func (img *Image) Sample(u, v float64) []float64 {
+	x := u*float64(img.width) - 0.5
+	y := (1-v)*float64(img.height) - 0.5
+	x0 := math.Floor(x)
+	y0 := math.Floor(y)
+	fx := x - x0
+	fy := y - y0
+	ix := int(x0)
+	iy := int(y0)
+
+	c00 := img.Texel(ix, iy)
+	c10 := img.Texel(ix+1, iy)
+	c01 := img.Texel(ix, iy+1)
+	c11 := img.Texel(ix+1, iy+1)
+	color := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		top := c00[i]*(1-fx) + c10[i]*fx
+		bottom := c01[i]*(1-fx) + c11[i]*fx
+		color[i] = top*(1-fy) + bottom*fy
+	}
+	return color
+}
+
+// LoadImage is a function to initialize and read an Image.
+//
+// Parameters:
+//  path - path to the PNG, JPEG, PPM, Radiance HDR or PFM file.
+//  wrap - the wrap mode.
+//
+// Returns:
+//  the Image.
+//  an error.
+//
+func LoadImage(path, wrap string) (*Image, error) {
+	img := &Image{Path: path, Wrap: wrap}
+	if err := img.Load(); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
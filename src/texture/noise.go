@@ -0,0 +1,245 @@
+package texture
+
+import (
+	"math"
+	"math/rand"
+)
+
+// gradients3D are the 12 gradient directions (cube edge midpoints) shared by Perlin and simplex noise.
+var gradients3D = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// Noise is a class for seeded gradient noise.
+//
+// Members:
+//  perm - permutation table of 0..255 repeated twice.
+//
+type Noise struct {
+	perm []int
+}
+
+// InitNoise is a function to initialize a Noise.
+//
+// Parameters:
+//  seed - the seed of the permutation table.
+//
+// Returns:
+//  the Noise.
+//
+func InitNoise(seed int64) *Noise {
+	values := rand.New(rand.NewSource(seed)).Perm(256)
+	perm := make([]int, 512)
+	for i := range perm {
+		perm[i] = values[i&255]
+	}
+	return &Noise{perm: perm}
+}
+
+// hash is a function to get a pseudo random value of a lattice point.
+//
+// Parameters:
+//  x, y, z - the lattice point.
+//
+// Returns:
+//  a value in [0, 255].
+//
+func (noise *Noise) hash(x, y, z int) int {
+	return noise.perm[noise.perm[noise.perm[x&255]+(y&255)]+(z&255)]
+}
+
+// fade is a function for the quintic interpolation curve of Perlin noise.
+//
+// Parameters:
+//  t - a value in [0, 1].
+//
+// Returns:
+//  the smoothed value.
+//
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp is a function for linear interpolation.
+//
+// Parameters:
+//  t - the weight of b.
+//  a - the first value.
+//  b - the second value.
+//
+// Returns:
+//  the interpolated value.
+//
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// gradDot is a function to get the dot product of a hashed gradient and an offset.
+//
+// Parameters:
+//  h       - the hash of the lattice point.
+//  x, y, z - the offset to the lattice point.
+//
+// Returns:
+//  the dot product.
+//
+func gradDot(h int, x, y, z float64) float64 {
+	g := gradients3D[h%12]
+	return g[0]*x + g[1]*y + g[2]*z
+}
+
+// Perlin is a function to evaluate improved Perlin noise.
+//
+// Parameters:
+//  x, y, z - the point.
+//
+// Returns:
+//  a value roughly in [-1, 1].
+//
+func (noise *Noise) Perlin(x, y, z float64) float64 {
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	ix, iy, iz := int(fx), int(fy), int(fz)
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, gradDot(noise.hash(ix, iy, iz), x, y, z), gradDot(noise.hash(ix+1, iy, iz), x-1, y, z)),
+			lerp(u, gradDot(noise.hash(ix, iy+1, iz), x, y-1, z), gradDot(noise.hash(ix+1, iy+1, iz), x-1, y-1, z))),
+		lerp(v,
+			lerp(u, gradDot(noise.hash(ix, iy, iz+1), x, y, z-1), gradDot(noise.hash(ix+1, iy, iz+1), x-1, y, z-1)),
+			lerp(u, gradDot(noise.hash(ix, iy+1, iz+1), x, y-1, z-1), gradDot(noise.hash(ix+1, iy+1, iz+1), x-1, y-1, z-1))))
+}
+
+// Simplex is a function to evaluate 3D simplex noise.
+//
+// Parameters:
+//  x, y, z - the point.
+//
+// Returns:
+//  a value roughly in [-1, 1].
+//
+func (noise *Noise) Simplex(x, y, z float64) float64 {
+	const f3 = 1.0 / 3.0
+	const g3 = 1.0 / 6.0
+	// skewing to find the simplex cell
+	s := (x + y + z) * f3
+	i := int(math.Floor(x + s))
+	j := int(math.Floor(y + s))
+	k := int(math.Floor(z + s))
+	t := float64(i+j+k) * g3
+	x0 := x - (float64(i) - t)
+	y0 := y - (float64(j) - t)
+	z0 := z - (float64(k) - t)
+
+	// finding which of the six simplices contains the point
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+	offsets := [4][3]float64{
+		{x0, y0, z0},
+		{x0 - float64(i1) + g3, y0 - float64(j1) + g3, z0 - float64(k1) + g3},
+		{x0 - float64(i2) + 2*g3, y0 - float64(j2) + 2*g3, z0 - float64(k2) + 2*g3},
+		{x0 - 1 + 3*g3, y0 - 1 + 3*g3, z0 - 1 + 3*g3},
+	}
+	corners := [4][3]int{{0, 0, 0}, {i1, j1, k1}, {i2, j2, k2}, {1, 1, 1}}
+
+	total := 0.0
+	for c := 0; c < 4; c++ {
+		o := offsets[c]
+		falloff := 0.6 - o[0]*o[0] - o[1]*o[1] - o[2]*o[2]
+		if falloff > 0 {
+			falloff *= falloff
+			h := noise.hash(i+corners[c][0], j+corners[c][1], k+corners[c][2])
+			total += falloff * falloff * gradDot(h, o[0], o[1], o[2])
+		}
+	}
+	return 32 * total
+}
+
+// FBM is a function to evaluate fractal Brownian motion over Perlin noise.
+//
+// Parameters:
+//  x, y, z - the point.
+//  octaves - number of layers.
+//
+// Returns:
+//  a value roughly in [-1, 1].
+//
+func (noise *Noise) FBM(x, y, z float64, octaves int) float64 {
+	total, amplitude, frequency := 0.0, 0.5, 1.0
+	for i := 0; i < octaves; i++ {
+		total += amplitude * noise.Perlin(x*frequency, y*frequency, z*frequency)
+		amplitude *= 0.5
+		frequency *= 2
+	}
+	return total
+}
+
+// Turbulence is a function to evaluate the sum of absolute Perlin noise layers.
+//
+// Parameters:
+//  x, y, z - the point.
+//  octaves - number of layers.
+//
+// Returns:
+//  a value roughly in [0, 1].
+//
+func (noise *Noise) Turbulence(x, y, z float64, octaves int) float64 {
+	total, amplitude, frequency := 0.0, 0.5, 1.0
+	for i := 0; i < octaves; i++ {
+		total += amplitude * math.Abs(noise.Perlin(x*frequency, y*frequency, z*frequency))
+		amplitude *= 0.5
+		frequency *= 2
+	}
+	return total
+}
+
+// Worley is a function to evaluate cellular noise, with one feature point per unit cell.
+//
+// Parameters:
+//  x, y, z - the point.
+//
+// Returns:
+//  the distance to the closest feature point.
+//
+func (noise *Noise) Worley(x, y, z float64) float64 {
+	ix, iy, iz := int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))
+	closest := math.MaxFloat64
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				cx, cy, cz := ix+dx, iy+dy, iz+dz
+				h := noise.hash(cx, cy, cz)
+				// feature point inside the cell from three chained hashes
+				px := float64(cx) + float64(h)/255
+				h = noise.perm[h+1]
+				py := float64(cy) + float64(h)/255
+				h = noise.perm[h+1]
+				pz := float64(cz) + float64(h)/255
+				dist := math.Sqrt((px-x)*(px-x) + (py-y)*(py-y) + (pz-z)*(pz-z))
+				if dist < closest {
+					closest = dist
+				}
+			}
+		}
+	}
+	return closest
+}
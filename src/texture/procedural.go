@@ -0,0 +1,168 @@
+package texture
+
+import (
+	"fmt"
+	"math"
+)
+
+// Procedural texture types.
+const (
+	ProceduralChecker2D  = "checker2d"
+	ProceduralChecker3D  = "checker3d"
+	ProceduralStripes    = "stripes"
+	ProceduralGradient   = "gradient"
+	ProceduralPerlin     = "perlin"
+	ProceduralSimplex    = "simplex"
+	ProceduralFBM        = "fbm"
+	ProceduralTurbulence = "turbulence"
+	ProceduralMarble     = "marble"
+	ProceduralWood       = "wood"
+	ProceduralWorley     = "worley"
+)
+
+// Spaces where procedural textures are evaluated.
+const (
+	SpaceObject = "object"
+	SpaceWorld  = "world"
+)
+
+// Procedural is a class for textures computed from the hit point.
+//
+// Members:
+//  Type    - the pattern (checker2d, checker3d, stripes, gradient, perlin, simplex, fbm, turbulence, marble, wood or worley).
+//  Colors  - the two RGB blended by the pattern, black and white when empty.
+//  Scale   - frequency of the pattern (1 when 0).
+//  Space   - object or world, object when empty.
+//  Origin  - origin of the object space, the object center before its Transform when empty.
+//  Axis    - main axis (0 x, 1 y, 2 z) of stripes, gradient and wood, checker2d uses the two other axes.
+//  Octaves - number of layers of fbm, turbulence and marble (5 when 0).
+//  Seed    - seed of the noise.
+//  noise   - the noise generator.
+//
+type Procedural struct {
+	Type    string
+	Colors  [][]float64
+	Scale   float64
+	Space   string
+	Origin  []float64
+	Axis    int
+	Octaves int
+	Seed    int64
+	noise   *Noise
+}
+
+// Init is a function to validate the Procedural and build its noise, it must be called before Evaluate.
+//
+// Parameters:
+//  center - the object center, used as Origin in object space when none is given.
+//
+// Returns:
+//  an error.
+//
+func (proc *Procedural) Init(center []float64) error {
+	switch proc.Type {
+	case ProceduralChecker2D, ProceduralChecker3D, ProceduralStripes, ProceduralGradient, ProceduralPerlin,
+		ProceduralSimplex, ProceduralFBM, ProceduralTurbulence, ProceduralMarble, ProceduralWood, ProceduralWorley:
+	default:
+		return fmt.Errorf("invalid procedural texture type %q", proc.Type)
+	}
+	if proc.Space != "" && proc.Space != SpaceObject && proc.Space != SpaceWorld {
+		return fmt.Errorf("invalid procedural texture space %q", proc.Space)
+	}
+	if len(proc.Colors) == 0 {
+		proc.Colors = [][]float64{{0, 0, 0}, {1, 1, 1}}
+	}
+	if len(proc.Colors) != 2 || len(proc.Colors[0]) != 3 || len(proc.Colors[1]) != 3 {
+		return fmt.Errorf("procedural texture needs 2 RGB colors")
+	}
+	if proc.Axis < 0 || proc.Axis > 2 {
+		return fmt.Errorf("invalid procedural texture axis %d", proc.Axis)
+	}
+	if proc.Space != SpaceWorld && len(proc.Origin) != 3 {
+		proc.Origin = center
+	}
+	proc.noise = InitNoise(proc.Seed)
+	return nil
+}
+
+// pattern is a function to get the scalar value of the pattern.
+//
+// Parameters:
+//  p - the point in texture space.
+//
+// Returns:
+//  a value in [0, 1].
+//
+func (proc *Procedural) pattern(p []float64) float64 {
+	octaves := proc.Octaves
+	if octaves == 0 {
+		octaves = 5
+	}
+	a := proc.Axis
+	b := (a + 1) % 3
+	c := (a + 2) % 3
+	x, y, z := p[0], p[1], p[2]
+	switch proc.Type {
+	case ProceduralChecker2D:
+		if (int(math.Floor(p[b]))+int(math.Floor(p[c])))%2 == 0 {
+			return 0
+		}
+		return 1
+	case ProceduralChecker3D:
+		if (int(math.Floor(x))+int(math.Floor(y))+int(math.Floor(z)))%2 == 0 {
+			return 0
+		}
+		return 1
+	case ProceduralStripes:
+		return 0.5 + 0.5*math.Sin(p[a]*math.Pi)
+	case ProceduralGradient:
+		return p[a] - math.Floor(p[a])
+	case ProceduralPerlin:
+		return 0.5 + 0.5*proc.noise.Perlin(x, y, z)
+	case ProceduralSimplex:
+		return 0.5 + 0.5*proc.noise.Simplex(x, y, z)
+	case ProceduralFBM:
+		return 0.5 + 0.5*proc.noise.FBM(x, y, z, octaves)
+	case ProceduralTurbulence:
+		return proc.noise.Turbulence(x, y, z, octaves)
+	case ProceduralMarble:
+		return 0.5 + 0.5*math.Sin(p[a]*math.Pi+5*proc.noise.Turbulence(x, y, z, octaves))
+	case ProceduralWood:
+		rings := math.Sqrt(p[b]*p[b]+p[c]*p[c]) + 0.5*proc.noise.Perlin(x, y, z)
+		return rings - math.Floor(rings)
+	case ProceduralWorley:
+		return proc.noise.Worley(x, y, z)
+	}
+	return 0
+}
+
+// Evaluate is a function to get the RGB of the texture at a point.
+//
+// Parameters:
+//  pos   - the world position of the hit point.
+//  local - the position of the hit point in object space, before the transforms of the object.
+//
+// Returns:
+//  the RGB.
+//
+func (proc *Procedural) Evaluate(pos, local []float64) []float64 {
+	scale := proc.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	p := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		if proc.Space == SpaceWorld {
+			p[i] = pos[i]
+		} else {
+			p[i] = local[i] - proc.Origin[i]
+		}
+		p[i] *= scale
+	}
+	t := math.Max(0, math.Min(1, proc.pattern(p)))
+	color := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		color[i] = lerp(t, proc.Colors[0][i], proc.Colors[1][i])
+	}
+	return color
+}
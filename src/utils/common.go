@@ -5,7 +5,7 @@ import (
 	"os"
 )
 
-// ShowError is a function to print an error.
+// ShowError is a function to print an error and exit, only used by the commands since the library returns its errors.
 //
 // Parameters:
 // 	err - The error.
@@ -52,3 +52,19 @@ func CheckTolerance(value, pos float64) bool {
 	tol := 10E-10
 	return (pos-tol <= value && value <= pos+tol)
 }
+
+// MaxInt is a function to get the greatest of two integers.
+//
+// Parameters:
+// 	a - the first integer.
+//  b - the second integer.
+//
+// Returns:
+// 	the greatest value.
+//
+func MaxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
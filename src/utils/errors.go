@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SizeError is a class for errors of lists, vectors and matrices with the wrong number of elements.
+//
+// Members:
+// 	Size     - the number of elements found.
+// 	Expected - the number of elements expected, 0 when any positive number is accepted.
+//
+type SizeError struct {
+	Size     int
+	Expected int
+}
+
+// Error is a function to format the SizeError.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the error message.
+//
+func (err *SizeError) Error() string {
+	if err.Expected == 0 {
+		return fmt.Sprintf("invalid length %d", err.Size)
+	}
+	return fmt.Sprintf("length %d, expected %d", err.Size, err.Expected)
+}
+
+// CheckSize is a function to check the number of elements of a list.
+//
+// Parameters:
+// 	size     - the number of elements found.
+// 	expected - the number of elements expected.
+//
+// Returns:
+// 	a *SizeError if the sizes differ.
+//
+func CheckSize(size, expected int) error {
+	if size != expected {
+		return &SizeError{Size: size, Expected: expected}
+	}
+	return nil
+}
+
+// FieldError is a class for errors of a field of a structure.
+//
+// Members:
+// 	Field - the path to the field, like ObjList[3].Triangles[12].Vertices.
+// 	Err   - the error of the field.
+//
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error is a function to format the FieldError.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the error message.
+//
+func (err *FieldError) Error() string {
+	return err.Field + ": " + err.Err.Error()
+}
+
+// Unwrap is a function to get the error of the field.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the error.
+//
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+// WrapField is a function to add a field to the path of an error.
+//
+// Parameters:
+// 	field - the field, prepended to the path when err is already a *FieldError.
+// 	err   - the error.
+//
+// Returns:
+// 	a *FieldError, or nil when err is nil.
+//
+func WrapField(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if inner, ok := err.(*FieldError); ok {
+		if strings.HasPrefix(inner.Field, "[") {
+			return &FieldError{Field: field + inner.Field, Err: inner.Err}
+		}
+		return &FieldError{Field: field + "." + inner.Field, Err: inner.Err}
+	}
+	return &FieldError{Field: field, Err: err}
+}
+
+// FileError is a class for errors reading or writing a file.
+//
+// Members:
+// 	Op   - the operation (open, read, parse, write...).
+// 	Path - the path to the file.
+// 	Err  - the error.
+//
+type FileError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+// Error is a function to format the FileError.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the error message.
+//
+func (err *FileError) Error() string {
+	return err.Op + " " + err.Path + ": " + err.Err.Error()
+}
+
+// Unwrap is a function to get the error of the operation.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the error.
+//
+func (err *FileError) Unwrap() error {
+	return err.Err
+}
+
+// WrapFile is a function to add the operation and the file to an error.
+//
+// Parameters:
+// 	op   - the operation.
+// 	path - the path to the file.
+// 	err  - the error.
+//
+// Returns:
+// 	a *FileError, or nil when err is nil.
+//
+func WrapFile(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FileError{Op: op, Path: path, Err: err}
+}
@@ -1,23 +1,43 @@
 package utils
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"math"
 )
 
-// InitHomogeneousCoordMatrix is a function to initialize a matrix with homogeneous coordinates.
+// ErrZeroAxis is the error of a rotation around an axis with length 0.
+var ErrZeroAxis = errors.New("rotation axis with length 0")
+
+// checkDimension is a function to check the dimension of a homogeneous matrix.
 //
 // Parameters:
 // 	dim - The number of dimensitons(2 or 3).
 //
 // Returns:
-// 	none
+// 	an error if the dimension is not 2 or 3.
 //
-func InitHomogeneousCoordMatrix(dim int) Matrix {
+func checkDimension(dim int) error {
 	if dim > 3 || dim < 2 {
-		log.Fatalf("Invalid dimensions: %d.\n", dim)
+		return &FieldError{Field: "dimensions", Err: &SizeError{Size: dim, Expected: 3}}
 	}
-	maux := IDMatrix(dim + 1)
-	return maux
+	return nil
+}
+
+// InitHomogeneousCoordMatrix is a function to initialize a matrix with homogeneous coordinates.
+//
+// Parameters:
+// 	dim - The number of dimensitons(2 or 3).
+//
+// Returns:
+// 	the identity Matrix.
+// 	an error if the dimension is invalid.
+//
+func InitHomogeneousCoordMatrix(dim int) (Matrix, error) {
+	if err := checkDimension(dim); err != nil {
+		return Matrix{}, err
+	}
+	return idMatrix(dim + 1), nil
 }
 
 // InitTranslationMatrix is a function to initialize a translation matrix.
@@ -27,18 +47,206 @@ func InitHomogeneousCoordMatrix(dim int) Matrix {
 // 	trans - List of values to translate (x,y,z...).
 //
 // Returns:
-// 	none
+// 	the Matrix.
+// 	an error if the dimension or the number of values is invalid.
 //
-func InitTranslationMatrix(dim int, trans []float64) Matrix {
-	if dim > 3 || dim < 2 {
-		log.Fatalf("Invalid dimensions: %d.\n", dim)
+func InitTranslationMatrix(dim int, trans []float64) (Matrix, error) {
+	if err := checkDimension(dim); err != nil {
+		return Matrix{}, err
 	}
-	if len(trans) != dim {
-		log.Fatalf("Invalid number of values to translate, expected: %d and got: %d.\n", dim, len(trans))
+	if err := CheckSize(len(trans), dim); err != nil {
+		return Matrix{}, WrapField("translate", err)
 	}
-	maux := InitHomogeneousCoordMatrix(dim)
+	maux := idMatrix(dim + 1)
 	for i := 0; i < dim; i++ {
 		maux.Values[i][dim] = trans[i]
 	}
+	return maux, nil
+}
+
+// InitScaleMatrix is a function to initialize a scale matrix.
+//
+// Parameters:
+// 	dim   - The number of dimensitons(2 or 3).
+// 	scale - List of factors of each axis (x,y,z...).
+//
+// Returns:
+// 	the Matrix.
+// 	an error if the dimension or the number of values is invalid.
+//
+func InitScaleMatrix(dim int, scale []float64) (Matrix, error) {
+	if err := checkDimension(dim); err != nil {
+		return Matrix{}, err
+	}
+	if err := CheckSize(len(scale), dim); err != nil {
+		return Matrix{}, WrapField("scale", err)
+	}
+	maux := idMatrix(dim + 1)
+	for i := 0; i < dim; i++ {
+		maux.Values[i][i] = scale[i]
+	}
+	return maux, nil
+}
+
+// InitShearMatrix is a function to initialize a 3D shear matrix.
+//
+// Parameters:
+// 	xy, xz - how much x grows with y and z.
+// 	yx, yz - how much y grows with x and z.
+// 	zx, zy - how much z grows with x and y.
+//
+// Returns:
+// 	the Matrix.
+//
+func InitShearMatrix(xy, xz, yx, yz, zx, zy float64) Matrix {
+	maux := idMatrix(4)
+	maux.Values[0][1] = xy
+	maux.Values[0][2] = xz
+	maux.Values[1][0] = yx
+	maux.Values[1][2] = yz
+	maux.Values[2][0] = zx
+	maux.Values[2][1] = zy
 	return maux
 }
+
+// InitRotationMatrix is a function to initialize a 3D rotation matrix around an axis (Rodrigues formula).
+//
+// Parameters:
+// 	axis  - the axis, it does not need to be normalized.
+// 	angle - the counterclockwise angle in degrees.
+//
+// Returns:
+// 	the Matrix.
+// 	an error if the axis is not 3D or ErrZeroAxis.
+//
+func InitRotationMatrix(axis []float64, angle float64) (Matrix, error) {
+	if err := CheckSize(len(axis), 3); err != nil {
+		return Matrix{}, WrapField("axis", err)
+	}
+	norm := math.Sqrt(axis[0]*axis[0] + axis[1]*axis[1] + axis[2]*axis[2])
+	if norm == 0 {
+		return Matrix{}, ErrZeroAxis
+	}
+	x, y, z := axis[0]/norm, axis[1]/norm, axis[2]/norm
+	rad := angle * math.Pi / 180
+	c, s := math.Cos(rad), math.Sin(rad)
+	t := 1 - c
+	maux := idMatrix(4)
+	maux.Values[0] = []float64{t*x*x + c, t*x*y - s*z, t*x*z + s*y, 0}
+	maux.Values[1] = []float64{t*x*y + s*z, t*y*y + c, t*y*z - s*x, 0}
+	maux.Values[2] = []float64{t*x*z - s*y, t*y*z + s*x, t*z*z + c, 0}
+	return maux, nil
+}
+
+// InitEulerRotationMatrix is a function to initialize a 3D rotation matrix from Euler angles.
+// The rotations are applied in the XYZ order, so the matrix is Rz * Ry * Rx.
+//
+// Parameters:
+// 	angles - the angles around x, y and z in degrees.
+//
+// Returns:
+// 	the Matrix.
+// 	an error if there are not 3 angles.
+//
+func InitEulerRotationMatrix(angles []float64) (Matrix, error) {
+	if err := CheckSize(len(angles), 3); err != nil {
+		return Matrix{}, WrapField("angles", err)
+	}
+	// the axes are valid, ignoring the errors
+	rx, _ := InitRotationMatrix([]float64{1, 0, 0}, angles[0])
+	ry, _ := InitRotationMatrix([]float64{0, 1, 0}, angles[1])
+	rz, _ := InitRotationMatrix([]float64{0, 0, 1}, angles[2])
+	// the rotations are 4x4, ignoring the errors
+	maux, _ := MultMatrix(&ry, &rx)
+	maux, _ = MultMatrix(&rz, &maux)
+	return maux, nil
+}
+
+// InitLookAtMatrix is a function to initialize a view matrix, from world to camera coordinates.
+// The camera looks down its -z axis, with y up.
+//
+// Parameters:
+// 	eye    - the position of the camera.
+// 	target - the point looked at.
+// 	up     - the approximate up direction.
+//
+// Returns:
+// 	the Matrix.
+// 	an error if a vector is not 3D.
+//
+func InitLookAtMatrix(eye, target, up []float64) (Matrix, error) {
+	for name, vect := range map[string][]float64{"eye": eye, "target": target, "up": up} {
+		if err := CheckSize(len(vect), 3); err != nil {
+			return Matrix{}, WrapField(name, err)
+		}
+	}
+	back := Vector{Coordinates: []float64{eye[0] - target[0], eye[1] - target[1], eye[2] - target[2]}}
+	back = NormalizeVector(&back)
+	upVector := Vector{Coordinates: append([]float64{}, up...)}
+	// the vectors are 3D, ignoring the errors
+	right, _ := VectorCrossProduct(&upVector, &back)
+	right = NormalizeVector(&right)
+	trueUp, _ := VectorCrossProduct(&back, &right)
+	eyeVector := Vector{Coordinates: append([]float64{}, eye...)}
+
+	maux := idMatrix(4)
+	for i, axis := range []Vector{right, trueUp, back} {
+		copy(maux.Values[i], axis.Coordinates)
+		maux.Values[i][3] = -DotProduct(&axis, &eyeVector)
+	}
+	return maux, nil
+}
+
+// InitPerspectiveMatrix is a function to initialize a perspective projection matrix.
+// Points between the near and far planes in front of the camera (-z) are mapped to z in [-1, 1].
+//
+// Parameters:
+// 	fieldOfView - the vertical field of view in degrees.
+// 	aspect      - width divided by height.
+// 	near        - distance to the near plane.
+// 	far         - distance to the far plane.
+//
+// Returns:
+// 	the Matrix.
+// 	an error if near is not positive, far is not greater than near or aspect is not positive.
+//
+func InitPerspectiveMatrix(fieldOfView, aspect, near, far float64) (Matrix, error) {
+	if near <= 0 || far <= near {
+		return Matrix{}, fmt.Errorf("invalid perspective planes near %g, far %g", near, far)
+	}
+	if aspect <= 0 {
+		return Matrix{}, fmt.Errorf("invalid perspective aspect %g", aspect)
+	}
+	f := 1 / math.Tan(fieldOfView*math.Pi/360)
+	maux := newMatrix(4, 4)
+	maux.Values[0][0] = f / aspect
+	maux.Values[1][1] = f
+	maux.Values[2][2] = (far + near) / (near - far)
+	maux.Values[2][3] = 2 * far * near / (near - far)
+	maux.Values[3][2] = -1
+	return maux, nil
+}
+
+// NormalMatrix is a function to get the matrix that transforms normals, the inverse transpose.
+//
+// Parameters:
+// 	matrix - the homogeneous Matrix transforming points.
+//
+// Returns:
+// 	the normal Matrix, without translation.
+//  an error if the matrix is singular.
+//
+func NormalMatrix(matrix *Matrix) (Matrix, error) {
+	if err := matrixLinesColumns(matrix.Lines, matrix.Columns); err != nil {
+		return Matrix{}, err
+	}
+	linear := idMatrix(matrix.Lines)
+	for i := 0; i < matrix.Lines-1; i++ {
+		copy(linear.Values[i], matrix.Values[i][:matrix.Columns-1])
+	}
+	inverse, err := InvertMatrix(&linear)
+	if err != nil {
+		return Matrix{}, err
+	}
+	return TransposeMatrix(&inverse), nil
+}
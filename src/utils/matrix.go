@@ -3,10 +3,13 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"log"
+	"math"
 	"strconv"
 )
 
+// ErrSingularMatrix is the error of inverting a matrix with determinant 0.
+var ErrSingularMatrix = errors.New("singular matrix")
+
 // Matrix is a class for matrices.
 //
 // Members:
@@ -30,8 +33,11 @@ type Matrix struct {
 // 	An error.
 //
 func matrixLinesColumns(lin, col int) error {
-	if lin <= 0 || col <= 0 {
-		return errors.New("Invalid Number of lines and columns")
+	if lin <= 0 {
+		return &FieldError{Field: "lines", Err: &SizeError{Size: lin}}
+	}
+	if col <= 0 {
+		return &FieldError{Field: "columns", Err: &SizeError{Size: col}}
 	}
 	return nil
 }
@@ -65,7 +71,7 @@ func PrintMatrix(matrix *Matrix) {
 // 	The resulting matrix
 //
 func CMultMatrix(matrix *Matrix, c float64) Matrix {
-	maux := InitMatrix(matrix.Lines, matrix.Columns)
+	maux := newMatrix(matrix.Lines, matrix.Columns)
 	for i := 0; i < matrix.Lines; i++ {
 		for j := 0; j < matrix.Columns; j++ {
 			maux.Values[i][j] += matrix.Values[i][j] * c
@@ -81,13 +87,14 @@ func CMultMatrix(matrix *Matrix, c float64) Matrix {
 //  m2 - A pointer to a Matrix.
 //
 // Returns:
-// 	The resulting matrix
+// 	The resulting matrix.
+// 	a *SizeError if the lines of m2 are not the columns of m1.
 //
-func MultMatrix(m1, m2 *Matrix) Matrix {
-	if m1.Columns != m2.Lines {
-		log.Fatalf("Invalid length of matrices. col m1: %d, lin m2: %d.\n", m1.Columns, m2.Lines)
+func MultMatrix(m1, m2 *Matrix) (Matrix, error) {
+	if err := CheckSize(m2.Lines, m1.Columns); err != nil {
+		return Matrix{}, err
 	}
-	maux := InitMatrix(m1.Lines, m2.Columns)
+	maux := newMatrix(m1.Lines, m2.Columns)
 	for i := 0; i < m1.Lines; i++ {
 		for j := 0; j < m2.Columns; j++ {
 			for z := 0; z < m1.Columns; z++ {
@@ -95,7 +102,7 @@ func MultMatrix(m1, m2 *Matrix) Matrix {
 			}
 		}
 	}
-	return maux
+	return maux, nil
 }
 
 // TransposeMatrix is a function to transpose a Matrix.
@@ -107,7 +114,7 @@ func MultMatrix(m1, m2 *Matrix) Matrix {
 // 	The transposed Matrix.
 //
 func TransposeMatrix(matrix *Matrix) Matrix {
-	maux := InitMatrix(matrix.Columns, matrix.Lines)
+	maux := newMatrix(matrix.Columns, matrix.Lines)
 	for i := 0; i < matrix.Lines; i++ {
 		for j := 0; j < matrix.Columns; j++ {
 			maux.Values[j][i] = matrix.Values[i][j]
@@ -123,12 +130,25 @@ func TransposeMatrix(matrix *Matrix) Matrix {
 //
 // Returns:
 // 	A Matrix.
+// 	An error if the size is not positive.
 //
-func IDMatrix(size int) Matrix {
-	if size < 1 {
-		log.Fatalf("Invalid size of identity matrix: %d.\n", size)
+func IDMatrix(size int) (Matrix, error) {
+	if err := matrixLinesColumns(size, size); err != nil {
+		return Matrix{}, err
 	}
-	maux := InitMatrix(size, size)
+	return idMatrix(size), nil
+}
+
+// idMatrix is a function to initialize a Identity Matrix of a valid size.
+//
+// Parameters:
+// 	size - The number of lines of the matrix.
+//
+// Returns:
+// 	A Matrix.
+//
+func idMatrix(size int) Matrix {
+	maux := newMatrix(size, size)
 	for i := 0; i < size; i++ {
 		maux.Values[i][i] = 1
 	}
@@ -143,10 +163,25 @@ func IDMatrix(size int) Matrix {
 //
 // Returns:
 // 	A Matrix.
+// 	An error if the number of lines or columns is not positive.
+//
+func InitMatrix(lin, col int) (Matrix, error) {
+	if err := matrixLinesColumns(lin, col); err != nil {
+		return Matrix{}, err
+	}
+	return newMatrix(lin, col), nil
+}
+
+// newMatrix is a function to initialize a Matrix of a valid size.
+//
+// Parameters:
+// 	lin - The number of lines of the matrix.
+// 	col - The number of columns of the matrix.
+//
+// Returns:
+// 	A Matrix.
 //
-func InitMatrix(lin, col int) Matrix {
-	err := matrixLinesColumns(lin, col)
-	ShowError(err, "lines - %d, columns - %d.") //add lin and col here
+func newMatrix(lin, col int) Matrix {
 	matrix := Matrix{Values: make([][]float64, lin), Lines: lin, Columns: col}
 	// setting default values
 	for i := range matrix.Values {
@@ -154,3 +189,100 @@ func InitMatrix(lin, col int) Matrix {
 	}
 	return matrix
 }
+
+// Determinant is a function to calculate the determinant of a square Matrix with Gaussian elimination.
+//
+// Parameters:
+// 	matrix - the target Matrix.
+//
+// Returns:
+// 	the determinant.
+//  an error if the matrix is not square.
+//
+func Determinant(matrix *Matrix) (float64, error) {
+	if matrix.Lines != matrix.Columns {
+		return 0, &FieldError{Field: "columns", Err: &SizeError{Size: matrix.Columns, Expected: matrix.Lines}}
+	}
+	size := matrix.Lines
+	maux := newMatrix(size, size)
+	for i := 0; i < size; i++ {
+		copy(maux.Values[i], matrix.Values[i])
+	}
+	det := 1.0
+	for col := 0; col < size; col++ {
+		pivot := col
+		for i := col + 1; i < size; i++ {
+			if math.Abs(maux.Values[i][col]) > math.Abs(maux.Values[pivot][col]) {
+				pivot = i
+			}
+		}
+		if maux.Values[pivot][col] == 0 {
+			return 0, nil
+		}
+		if pivot != col {
+			maux.Values[col], maux.Values[pivot] = maux.Values[pivot], maux.Values[col]
+			det = -det
+		}
+		det *= maux.Values[col][col]
+		for i := col + 1; i < size; i++ {
+			factor := maux.Values[i][col] / maux.Values[col][col]
+			for j := col; j < size; j++ {
+				maux.Values[i][j] -= factor * maux.Values[col][j]
+			}
+		}
+	}
+	return det, nil
+}
+
+// InvertMatrix is a function to invert a square Matrix with Gauss-Jordan elimination.
+//
+// Parameters:
+// 	matrix - the target Matrix.
+//
+// Returns:
+// 	the inverse Matrix.
+//  an error if the matrix is not square, ErrSingularMatrix if it is singular.
+//
+func InvertMatrix(matrix *Matrix) (Matrix, error) {
+	if matrix.Lines != matrix.Columns {
+		return Matrix{}, &FieldError{Field: "columns", Err: &SizeError{Size: matrix.Columns, Expected: matrix.Lines}}
+	}
+	size := matrix.Lines
+	// augmented matrix [M | I]
+	aug := newMatrix(size, 2*size)
+	for i := 0; i < size; i++ {
+		copy(aug.Values[i], matrix.Values[i])
+		aug.Values[i][size+i] = 1
+	}
+	for col := 0; col < size; col++ {
+		// partial pivoting
+		pivot := col
+		for i := col + 1; i < size; i++ {
+			if math.Abs(aug.Values[i][col]) > math.Abs(aug.Values[pivot][col]) {
+				pivot = i
+			}
+		}
+		if math.Abs(aug.Values[pivot][col]) < 1e-12 {
+			return Matrix{}, ErrSingularMatrix
+		}
+		aug.Values[col], aug.Values[pivot] = aug.Values[pivot], aug.Values[col]
+		div := aug.Values[col][col]
+		for j := range aug.Values[col] {
+			aug.Values[col][j] /= div
+		}
+		for i := 0; i < size; i++ {
+			if i == col || aug.Values[i][col] == 0 {
+				continue
+			}
+			factor := aug.Values[i][col]
+			for j := range aug.Values[i] {
+				aug.Values[i][j] -= factor * aug.Values[col][j]
+			}
+		}
+	}
+	inverse := newMatrix(size, size)
+	for i := 0; i < size; i++ {
+		copy(inverse.Values[i], aug.Values[i][size:])
+	}
+	return inverse, nil
+}
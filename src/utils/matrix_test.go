@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// matrixOf is a function to create a Matrix from its lines.
+func matrixOf(lines ...[]float64) Matrix {
+	matrix := newMatrix(len(lines), len(lines[0]))
+	for i := range lines {
+		copy(matrix.Values[i], lines[i])
+	}
+	return matrix
+}
+
+// checkIdentity is a function to check if a matrix is the identity.
+func checkIdentity(t *testing.T, name string, matrix *Matrix) {
+	t.Helper()
+	for i := range matrix.Values {
+		for j, value := range matrix.Values[i] {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(value-want) > 1e-9 {
+				t.Errorf("%s: not the identity %v", name, matrix.Values)
+				return
+			}
+		}
+	}
+}
+
+// transforms is a function to get a few invertible transforms.
+func transforms(t *testing.T) map[string]Matrix {
+	t.Helper()
+	translation, err := InitTranslationMatrix(3, []float64{1, -2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scale, err := InitScaleMatrix(3, []float64{2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotation, err := InitRotationMatrix([]float64{1, 1, 0}, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	euler, err := InitEulerRotationMatrix([]float64{10, 20, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookAt, err := InitLookAtMatrix([]float64{1, 2, 3}, []float64{0, 0, 0}, []float64{0, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	perspective, err := InitPerspectiveMatrix(60, 1.5, 0.1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]Matrix{
+		"translation": translation,
+		"scale":       scale,
+		"rotation":    rotation,
+		"euler":       euler,
+		"shear":       InitShearMatrix(1, 0.5, 0, 2, 0, 0),
+		"look at":     lookAt,
+		"perspective": perspective,
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix Matrix
+		det    float64
+	}{
+		{"identity", idMatrix(4), 1},
+		{"1x1", matrixOf([]float64{-3}), -3},
+		{"3x3", matrixOf([]float64{6, 1, 1}, []float64{4, -2, 5}, []float64{2, 8, 7}), -306},
+		// the first pivot is 0, the lines are swapped
+		{"swap", matrixOf([]float64{0, 1}, []float64{1, 0}), -1},
+		{"singular", matrixOf([]float64{1, 2, 3}, []float64{2, 4, 6}, []float64{1, 0, 1}), 0},
+		{"null column", matrixOf([]float64{0, 1}, []float64{0, 2}), 0},
+	}
+	for _, test := range tests {
+		det, err := Determinant(&test.matrix)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if math.Abs(det-test.det) > 1e-9 {
+			t.Errorf("%s: got %g, want %g", test.name, det, test.det)
+		}
+	}
+	swap := matrixOf([]float64{0, 1}, []float64{1, 0})
+	Determinant(&swap)
+	if swap.Values[0][0] != 0 || swap.Values[1][0] != 1 {
+		t.Errorf("the matrix was modified: %v", swap.Values)
+	}
+}
+
+func TestDeterminantOfTransforms(t *testing.T) {
+	all := transforms(t)
+	for name, want := range map[string]float64{"translation": 1, "scale": 24, "rotation": 1, "euler": 1, "shear": 1, "look at": 1} {
+		matrix := all[name]
+		det, err := Determinant(&matrix)
+		if err != nil || math.Abs(det-want) > 1e-9 {
+			t.Errorf("%s: got %g %v, want %g", name, det, err, want)
+		}
+	}
+	// det(AB) = det(A) det(B)
+	scale, rotation := all["scale"], all["rotation"]
+	product, err := MultMatrix(&scale, &rotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	det, _ := Determinant(&product)
+	if math.Abs(det-24) > 1e-9 {
+		t.Errorf("product: got %g, want 24", det)
+	}
+}
+
+func TestInvertMatrix(t *testing.T) {
+	all := transforms(t)
+	all["swap"] = matrixOf([]float64{0, 1, 0}, []float64{1, 0, 0}, []float64{0, 0, 2})
+	all["3x3"] = matrixOf([]float64{6, 1, 1}, []float64{4, -2, 5}, []float64{2, 8, 7})
+	for name, matrix := range all {
+		inverse, err := InvertMatrix(&matrix)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		left, _ := MultMatrix(&inverse, &matrix)
+		right, _ := MultMatrix(&matrix, &inverse)
+		checkIdentity(t, name+" inverse * matrix", &left)
+		checkIdentity(t, name+" matrix * inverse", &right)
+	}
+}
+
+func TestInvertMatrixErrors(t *testing.T) {
+	singular := matrixOf([]float64{1, 2, 3}, []float64{2, 4, 6}, []float64{1, 0, 1})
+	if _, err := InvertMatrix(&singular); err != ErrSingularMatrix {
+		t.Errorf("singular: got %v, want ErrSingularMatrix", err)
+	}
+	scale, _ := InitScaleMatrix(3, []float64{1, 0, 1})
+	if _, err := InvertMatrix(&scale); err != ErrSingularMatrix {
+		t.Errorf("null scale: got %v, want ErrSingularMatrix", err)
+	}
+	rectangular := newMatrix(3, 4)
+	var sizeErr *SizeError
+	if _, err := InvertMatrix(&rectangular); !errors.As(err, &sizeErr) {
+		t.Errorf("rectangular: got %v, want a *SizeError", err)
+	}
+	if _, err := Determinant(&rectangular); !errors.As(err, &sizeErr) {
+		t.Errorf("rectangular determinant: got %v, want a *SizeError", err)
+	}
+	if _, err := MultMatrix(&rectangular, &rectangular); !errors.As(err, &sizeErr) {
+		t.Errorf("3x4 times 3x4: got %v, want a *SizeError", err)
+	}
+}
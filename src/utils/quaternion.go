@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrZeroQuaternion is the error of normalizing a Quaternion with length 0.
+var ErrZeroQuaternion = errors.New("quaternion with length 0")
+
+// Quaternion is a class for rotations as unit quaternions (W + Xi + Yj + Zk).
+//
+// Members:
+// 	W - the real part.
+// 	X - the i coefficient.
+// 	Y - the j coefficient.
+// 	Z - the k coefficient.
+//
+type Quaternion struct {
+	W float64
+	X float64
+	Y float64
+	Z float64
+}
+
+// InitQuaternion is a function to initialize a Quaternion from an axis and an angle.
+//
+// Parameters:
+// 	axis  - the axis, it does not need to be normalized.
+// 	angle - the counterclockwise angle in degrees.
+//
+// Returns:
+// 	the unit Quaternion.
+// 	an error if the axis is not 3D or ErrZeroAxis.
+//
+func InitQuaternion(axis []float64, angle float64) (Quaternion, error) {
+	if err := CheckSize(len(axis), 3); err != nil {
+		return Quaternion{}, WrapField("axis", err)
+	}
+	norm := math.Sqrt(axis[0]*axis[0] + axis[1]*axis[1] + axis[2]*axis[2])
+	if norm == 0 {
+		return Quaternion{}, ErrZeroAxis
+	}
+	half := angle * math.Pi / 360
+	s := math.Sin(half) / norm
+	return Quaternion{W: math.Cos(half), X: axis[0] * s, Y: axis[1] * s, Z: axis[2] * s}, nil
+}
+
+// NormalizeQuaternion is a function to normalize a Quaternion.
+//
+// Parameters:
+// 	q - the Quaternion.
+//
+// Returns:
+// 	the unit Quaternion.
+// 	ErrZeroQuaternion if the Quaternion has length 0.
+//
+func NormalizeQuaternion(q Quaternion) (Quaternion, error) {
+	norm := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	if norm == 0 {
+		return q, ErrZeroQuaternion
+	}
+	return Quaternion{W: q.W / norm, X: q.X / norm, Y: q.Y / norm, Z: q.Z / norm}, nil
+}
+
+// MultQuaternion is a function to multiply two Quaternions, the rotation q2 followed by q1.
+//
+// Parameters:
+// 	q1 - the first Quaternion.
+// 	q2 - the second Quaternion.
+//
+// Returns:
+// 	the product q1 * q2.
+//
+func MultQuaternion(q1, q2 Quaternion) Quaternion {
+	return Quaternion{
+		W: q1.W*q2.W - q1.X*q2.X - q1.Y*q2.Y - q1.Z*q2.Z,
+		X: q1.W*q2.X + q1.X*q2.W + q1.Y*q2.Z - q1.Z*q2.Y,
+		Y: q1.W*q2.Y - q1.X*q2.Z + q1.Y*q2.W + q1.Z*q2.X,
+		Z: q1.W*q2.Z + q1.X*q2.Y - q1.Y*q2.X + q1.Z*q2.W,
+	}
+}
+
+// SlerpQuaternion is a function to interpolate two rotations along the shortest arc.
+//
+// Parameters:
+// 	q1 - the rotation at t = 0.
+// 	q2 - the rotation at t = 1.
+// 	t  - the interpolation parameter.
+//
+// Returns:
+// 	the interpolated unit Quaternion.
+// 	ErrZeroQuaternion if a Quaternion has length 0.
+//
+func SlerpQuaternion(q1, q2 Quaternion, t float64) (Quaternion, error) {
+	q1, err := NormalizeQuaternion(q1)
+	if err != nil {
+		return q1, err
+	}
+	q2, err = NormalizeQuaternion(q2)
+	if err != nil {
+		return q2, err
+	}
+	dot := q1.W*q2.W + q1.X*q2.X + q1.Y*q2.Y + q1.Z*q2.Z
+	if dot < 0 {
+		q2 = Quaternion{W: -q2.W, X: -q2.X, Y: -q2.Y, Z: -q2.Z}
+		dot = -dot
+	}
+	k1, k2 := 1-t, t
+	if dot < 0.9995 {
+		theta := math.Acos(dot)
+		k1 = math.Sin((1-t)*theta) / math.Sin(theta)
+		k2 = math.Sin(t*theta) / math.Sin(theta)
+	}
+	return NormalizeQuaternion(Quaternion{
+		W: k1*q1.W + k2*q2.W,
+		X: k1*q1.X + k2*q2.X,
+		Y: k1*q1.Y + k2*q2.Y,
+		Z: k1*q1.Z + k2*q2.Z,
+	})
+}
+
+// QuaternionToMatrix is a function to get the 3D homogeneous rotation matrix of a Quaternion.
+//
+// Parameters:
+// 	q - the Quaternion, normalized before the conversion.
+//
+// Returns:
+// 	the Matrix.
+// 	ErrZeroQuaternion if the Quaternion has length 0.
+//
+func QuaternionToMatrix(q Quaternion) (Matrix, error) {
+	q, err := NormalizeQuaternion(q)
+	if err != nil {
+		return Matrix{}, err
+	}
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	maux := idMatrix(4)
+	maux.Values[0] = []float64{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0}
+	maux.Values[1] = []float64{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0}
+	maux.Values[2] = []float64{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0}
+	return maux, nil
+}
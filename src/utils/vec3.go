@@ -0,0 +1,257 @@
+package utils
+
+import (
+	"math"
+)
+
+// Vec3 is a class for 3D vectors stored by value, used where Vector allocations are too slow.
+//
+// Members:
+// 	[0], [1], [2] - the x, y and z coordinates.
+//
+type Vec3 [3]float64
+
+// VectorToVec3 is a function to convert a 3D Vector to a Vec3.
+//
+// Parameters:
+// 	vect - The Vector.
+//
+// Returns:
+// 	the Vec3.
+// 	a *SizeError if the Vector is not 3D.
+//
+func VectorToVec3(vect *Vector) (Vec3, error) {
+	if err := CheckSize(len(vect.Coordinates), 3); err != nil {
+		return Vec3{}, err
+	}
+	return Vec3{vect.Coordinates[0], vect.Coordinates[1], vect.Coordinates[2]}, nil
+}
+
+// ToVector is a function to convert a Vec3 to a Vector.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the Vector.
+//
+func (v Vec3) ToVector() Vector {
+	return Vector{Coordinates: []float64{v[0], v[1], v[2]}}
+}
+
+// Add is a function to sum two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	v + u.
+//
+func (v Vec3) Add(u Vec3) Vec3 {
+	return Vec3{v[0] + u[0], v[1] + u[1], v[2] + u[2]}
+}
+
+// Sub is a function to subtract two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	v - u.
+//
+func (v Vec3) Sub(u Vec3) Vec3 {
+	return Vec3{v[0] - u[0], v[1] - u[1], v[2] - u[2]}
+}
+
+// Scale is a function to multiply a Vec3 by a constant.
+//
+// Parameters:
+// 	k - The constant.
+//
+// Returns:
+// 	k * v.
+//
+func (v Vec3) Scale(k float64) Vec3 {
+	return Vec3{v[0] * k, v[1] * k, v[2] * k}
+}
+
+// AddScaled is a function to sum a Vec3 multiplied by a constant.
+//
+// Parameters:
+// 	u - The other Vec3.
+// 	k - The constant.
+//
+// Returns:
+// 	v + k * u.
+//
+func (v Vec3) AddScaled(u Vec3, k float64) Vec3 {
+	return Vec3{v[0] + u[0]*k, v[1] + u[1]*k, v[2] + u[2]*k}
+}
+
+// Mul is a function to multiply two Vec3 component by component.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	the component product.
+//
+func (v Vec3) Mul(u Vec3) Vec3 {
+	return Vec3{v[0] * u[0], v[1] * u[1], v[2] * u[2]}
+}
+
+// Neg is a function to get the opposite Vec3.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	-v.
+//
+func (v Vec3) Neg() Vec3 {
+	return Vec3{-v[0], -v[1], -v[2]}
+}
+
+// Dot is a function to calculate the dot product of two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	v . u.
+//
+func (v Vec3) Dot(u Vec3) float64 {
+	return v[0]*u[0] + v[1]*u[1] + v[2]*u[2]
+}
+
+// Cross is a function to calculate the cross product of two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	v x u.
+//
+func (v Vec3) Cross(u Vec3) Vec3 {
+	return Vec3{
+		v[1]*u[2] - v[2]*u[1],
+		v[2]*u[0] - v[0]*u[2],
+		v[0]*u[1] - v[1]*u[0],
+	}
+}
+
+// Length is a function to calculate the norm of a Vec3.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the norm.
+//
+func (v Vec3) Length() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Normalize is a function to normalize a Vec3.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the normalized Vec3, or v itself when its norm is 0.
+//
+func (v Vec3) Normalize() Vec3 {
+	norm := v.Length()
+	if norm == 0 {
+		return v
+	}
+	return v.Scale(1 / norm)
+}
+
+// Lerp is a function to interpolate two Vec3.
+//
+// Parameters:
+// 	u - The Vec3 at t = 1.
+// 	t - The interpolation parameter.
+//
+// Returns:
+// 	(1 - t) * v + t * u.
+//
+func (v Vec3) Lerp(u Vec3, t float64) Vec3 {
+	return v.Scale(1 - t).AddScaled(u, t)
+}
+
+// Min is a function to get the smallest components of two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	the component minimum.
+//
+func (v Vec3) Min(u Vec3) Vec3 {
+	return Vec3{math.Min(v[0], u[0]), math.Min(v[1], u[1]), math.Min(v[2], u[2])}
+}
+
+// Max is a function to get the greatest components of two Vec3.
+//
+// Parameters:
+// 	u - The other Vec3.
+//
+// Returns:
+// 	the component maximum.
+//
+func (v Vec3) Max(u Vec3) Vec3 {
+	return Vec3{math.Max(v[0], u[0]), math.Max(v[1], u[1]), math.Max(v[2], u[2])}
+}
+
+// MaxComponent is a function to get the greatest coordinate of a Vec3.
+//
+// Parameters:
+// 	none
+//
+// Returns:
+// 	the greatest coordinate.
+//
+func (v Vec3) MaxComponent() float64 {
+	return math.Max(v[0], math.Max(v[1], v[2]))
+}
+
+// TransformPoint is a function to multiply a Vec3 as a point (homogeneous coord 1) by a 4x4 Matrix.
+//
+// Parameters:
+// 	matrix - The homogeneous Matrix.
+//
+// Returns:
+// 	the transformed point, divided by the homogeneous coord when it is not 1.
+//
+func (v Vec3) TransformPoint(matrix *Matrix) Vec3 {
+	m := matrix.Values
+	result := Vec3{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2] + m[0][3],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2] + m[1][3],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2] + m[2][3],
+	}
+	w := m[3][0]*v[0] + m[3][1]*v[1] + m[3][2]*v[2] + m[3][3]
+	if w != 1 && w != 0 {
+		result = result.Scale(1 / w)
+	}
+	return result
+}
+
+// TransformDirection is a function to multiply a Vec3 as a direction (homogeneous coord 0) by a 4x4 Matrix.
+//
+// Parameters:
+// 	matrix - The homogeneous Matrix.
+//
+// Returns:
+// 	the transformed direction.
+//
+func (v Vec3) TransformDirection(matrix *Matrix) Vec3 {
+	m := matrix.Values
+	return Vec3{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
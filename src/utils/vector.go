@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"log"
 	"math"
 )
 
@@ -21,11 +20,28 @@ type Vector struct {
 // 	vect2 - The second vector.
 //
 // Returns:
+// 	a *SizeError with the size of vect2 if the sizes differ.
+//
+func CheckVectorCoordinates(vect1 *Vector, vect2 *Vector) error {
+	return CheckSize(len(vect2.Coordinates), len(vect1.Coordinates))
+}
+
+// mustMatch is a function to stop an arithmetic operation on vectors of different sizes.
+// The vectors read from files are checked to be 3D at the loaders: Object.CheckIntegrity is called by
+// general.InitObject, general.LoadJSONObjects, obj.ReadObj and scene.Load, the cameras are checked by
+// camera.CheckLenVector and the lights by their Prepare. A difference is then a programming error, so it panics
+// instead of returning an error.
+//
+// Parameters:
+// 	vect1 - The first vector.
+// 	vect2 - The second vector.
+//
+// Returns:
 // 	none
 //
-func CheckVectorCoordinates(vect1 *Vector, vect2 *Vector) {
-	if len(vect1.Coordinates) != len(vect2.Coordinates) {
-		log.Fatalf("Invalid size of vector. Expected: %d and Got: %d.\n", len(vect1.Coordinates), len(vect2.Coordinates))
+func mustMatch(vect1 *Vector, vect2 *Vector) {
+	if err := CheckVectorCoordinates(vect1, vect2); err != nil {
+		panic(err)
 	}
 }
 
@@ -39,7 +55,7 @@ func CheckVectorCoordinates(vect1 *Vector, vect2 *Vector) {
 // 	The resulting vector.
 //
 func CMultVector(vect *Vector, k float64) Vector {
-	vectAux := InitVector(len(vect.Coordinates))
+	vectAux := Vector{Coordinates: make([]float64, len(vect.Coordinates))}
 	for i := 0; i < len(vect.Coordinates); i++ {
 		vectAux.Coordinates[i] = k * vect.Coordinates[i]
 	}
@@ -58,11 +74,11 @@ func CMultVector(vect *Vector, k float64) Vector {
 // 	The resulting vector.
 //
 func SumVector(vect1 *Vector, vect2 *Vector, k1 float64, k2 float64) Vector {
-	CheckVectorCoordinates(vect1, vect2)
+	mustMatch(vect1, vect2)
 	vect1Aux := CMultVector(vect1, k1)
 	vect2Aux := CMultVector(vect2, k2)
 
-	vectAux := InitVector(len(vect1.Coordinates))
+	vectAux := Vector{Coordinates: make([]float64, len(vect1.Coordinates))}
 	for i := 0; i < len(vect1.Coordinates); i++ {
 		vectAux.Coordinates[i] = vect1Aux.Coordinates[i] + vect2Aux.Coordinates[i]
 	}
@@ -79,7 +95,7 @@ func SumVector(vect1 *Vector, vect2 *Vector, k1 float64, k2 float64) Vector {
 // 	The resulting sum.
 //
 func DotProduct(vect1 *Vector, vect2 *Vector) float64 {
-	CheckVectorCoordinates(vect1, vect2)
+	mustMatch(vect1, vect2)
 
 	var totalSum float64
 	for i := 0; i < len(vect1.Coordinates); i++ {
@@ -98,7 +114,7 @@ func DotProduct(vect1 *Vector, vect2 *Vector) float64 {
 // 	The resulting vector.
 //
 func ProjVector(vect1 *Vector, vect2 *Vector) Vector {
-	CheckVectorCoordinates(vect1, vect2)
+	mustMatch(vect1, vect2)
 	topConstant := DotProduct(vect1, vect2)
 	bottomConstant := DotProduct(vect2, vect2)
 
@@ -148,13 +164,33 @@ func CheckOrtogonalVector(vect1 *Vector, vect2 *Vector) bool {
 // 	a Matrix.
 //
 func VectorToHomogeneousCoord(vect *Vector) Matrix {
-	maux := InitMatrix(len(vect.Coordinates)+1, 1)
+	maux := newMatrix(len(vect.Coordinates)+1, 1)
 	for i := 0; i < len(vect.Coordinates); i++ {
 		maux.Values[i][0] = vect.Coordinates[i]
 	}
 	return maux
 }
 
+// TransformVector is a function to multiply a Vector by a homogeneous Matrix, ignoring the translation.
+//
+// Parameters:
+// 	matrix - the (n+1)x(n+1) Matrix.
+//  vect   - the Vector with n coordinates.
+//
+// Returns:
+// 	the transformed Vector.
+//
+func TransformVector(matrix *Matrix, vect *Vector) Vector {
+	size := len(vect.Coordinates)
+	vaux := Vector{Coordinates: make([]float64, size)}
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			vaux.Coordinates[i] += matrix.Values[i][j] * vect.Coordinates[j]
+		}
+	}
+	return vaux
+}
+
 // VectorCrossProduct is a function to calculate the cross product of two Vectors.
 //
 // Parameters:
@@ -162,21 +198,22 @@ func VectorToHomogeneousCoord(vect *Vector) Matrix {
 //  vect2 - The second Vector.
 //
 // Returns:
-// 	a Vector
+// 	a Vector.
+// 	a *SizeError if a Vector is not 3D.
 //
-func VectorCrossProduct(vect1, vect2 *Vector) Vector {
-	CheckVectorCoordinates(vect1, vect2)
-	if len(vect1.Coordinates) != 3 {
-		log.Fatalf("Invalid size of 3D vector: %d.\n", len(vect1.Coordinates))
+func VectorCrossProduct(vect1, vect2 *Vector) (Vector, error) {
+	if err := CheckSize(len(vect1.Coordinates), 3); err != nil {
+		return Vector{}, err
+	}
+	if err := CheckVectorCoordinates(vect1, vect2); err != nil {
+		return Vector{}, err
 	}
-	vaux := InitVector(3)
 	coord1 := vect1.Coordinates
 	coord2 := vect2.Coordinates
 	i := (coord1[1] * coord2[2]) - (coord1[2] * coord2[1])
 	j := (coord1[2] * coord2[0]) - (coord1[0] * coord2[2])
 	k := (coord1[0] * coord2[1]) - (coord1[1] * coord2[0])
-	vaux.Coordinates = []float64{i, j, k}
-	return vaux
+	return Vector{Coordinates: []float64{i, j, k}}, nil
 }
 
 // VectorNorm is a function to calculate the norm of a Vector.
@@ -210,12 +247,13 @@ func NormalizeVector(vect *Vector) Vector {
 // 	size - The size of the Vector.
 //
 // Returns:
-// 	a Vector
+// 	a Vector.
+// 	a *SizeError if the size is negative.
 //
-func InitVector(size int) Vector {
+func InitVector(size int) (Vector, error) {
 	if size < 0 {
-		log.Fatalf("Invalid vector size %d.\n", size)
+		return Vector{}, &SizeError{Size: size}
 	}
 	vect := Vector{Coordinates: make([]float64, size)}
-	return vect
+	return vect, nil
 }
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVectorCrossProduct(t *testing.T) {
+	x := Vector{Coordinates: []float64{1, 0, 0}}
+	y := Vector{Coordinates: []float64{0, 1, 0}}
+	z, err := VectorCrossProduct(&x, &y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCoordinates(t, "x cross y", z.Coordinates, []float64{0, 0, 1})
+}
+
+func TestVectorSizeErrors(t *testing.T) {
+	var sizeErr *SizeError
+	plane := Vector{Coordinates: []float64{1, 0}}
+	space := Vector{Coordinates: []float64{0, 1, 0}}
+	if _, err := VectorCrossProduct(&plane, &plane); !errors.As(err, &sizeErr) {
+		t.Errorf("2D cross product: got %v, want a *SizeError", err)
+	}
+	if _, err := VectorCrossProduct(&space, &plane); !errors.As(err, &sizeErr) {
+		t.Errorf("3D cross 2D: got %v, want a *SizeError", err)
+	}
+	if _, err := VectorToVec3(&plane); !errors.As(err, &sizeErr) {
+		t.Errorf("2D to Vec3: got %v, want a *SizeError", err)
+	}
+	if _, err := InitVector(-1); !errors.As(err, &sizeErr) {
+		t.Errorf("negative size: got %v, want a *SizeError", err)
+	}
+}
+
+// checkCoordinates is a function to compare the coordinates of a vector with the expected ones.
+func checkCoordinates(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package validation
+
+import (
+	"math"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// areaTolerance is the smallest area of a triangle, relative to its squared edges.
+const areaTolerance = 1e-12
+
+// hasEmission is a function to check if an object, a mesh or the material of an instance emits light.
+//
+// Parameters:
+//  objs - the objects.
+//
+// Returns:
+//  true if the objects light the scene without lights.
+//
+func hasEmission(objs *general.Objects) bool {
+	for _, list := range [][]general.Object{objs.ObjList, objs.Meshes} {
+		for i := range list {
+			if list[i].GetEmission() != nil {
+				return true
+			}
+		}
+	}
+	for _, inst := range objs.Instances {
+		if inst.Material != nil {
+			var obj general.Object
+			obj.SetMaterial(inst.Material)
+			if obj.GetEmission() != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateObjects is a function to check the objects, meshes and instances.
+//
+// Parameters:
+//  objs - the objects.
+//  path - path to the objects, empty for an objects file.
+//
+// Returns:
+//  the report.
+//
+func ValidateObjects(objs *general.Objects, path string) *Report {
+	report := &Report{}
+	if len(objs.ObjList) == 0 && len(objs.Instances) == 0 {
+		report.warnf(join(path, "ObjList"), "nothing to render")
+	}
+	for i := range objs.ObjList {
+		checkObject(report, index(join(path, "ObjList"), i), &objs.ObjList[i])
+	}
+	for i := range objs.Meshes {
+		checkObject(report, index(join(path, "Meshes"), i), &objs.Meshes[i])
+	}
+	for i := range objs.Instances {
+		inst := &objs.Instances[i]
+		instPath := index(join(path, "Instances"), i)
+		if _, found := objs.FindMesh(inst.Mesh); !found {
+			report.errorf(join(instPath, "Mesh"), "mesh %q not found", inst.Mesh)
+		}
+		if _, _, err := inst.Transform.GetMatrices(); err != nil {
+			report.errorf(join(instPath, "Transform"), "%s", err)
+		}
+		if inst.Material != nil {
+			checkMaterial(report, join(instPath, "Material"), inst.Material)
+		}
+	}
+	return report
+}
+
+// checkObject is a function to check the geometry and the surface of an object.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the object.
+//  obj    - the object.
+//
+// Returns:
+//  none
+//
+func checkObject(report *Report, path string, obj *general.Object) {
+	checkGeometry(report, path, obj)
+	if obj.Primitive == nil && len(obj.Triangles) == 0 {
+		report.warnf(path, "object without triangles or primitive")
+	}
+	if len(obj.Color) == 0 {
+		report.errorf(join(path, "Color"), "missing color")
+	}
+	mat := general.Material{
+		Color:              obj.Color,
+		SpecularDecay:      obj.SpecularDecay,
+		SpecularReflection: obj.SpecularReflection,
+		TransReflection:    obj.TransReflection,
+		AmbientReflection:  obj.AmbientReflection,
+		DiffuseReflection:  obj.DiffuseReflection,
+		RoughNess:          obj.RoughNess,
+		RefractiveIndex:    obj.RefractiveIndex,
+		Emission:           obj.Emission,
+		EmissionStrength:   obj.EmissionStrength,
+	}
+	checkMaterial(report, path, &mat)
+}
+
+// checkMaterial is a function to check the color and the coefficients of a surface.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the object or material.
+//  mat    - the surface attributes.
+//
+// Returns:
+//  none
+//
+func checkMaterial(report *Report, path string, mat *general.Material) {
+	// a material without a color keeps the one of the object
+	if len(mat.Color) != 0 {
+		checkColor(report, join(path, "Color"), mat.Color)
+	}
+	if len(mat.Emission) != 0 {
+		checkColor(report, join(path, "Emission"), mat.Emission)
+	}
+	coefficients := []struct {
+		field string
+		value float64
+	}{
+		{"DiffuseReflection", mat.DiffuseReflection},
+		{"SpecularReflection", mat.SpecularReflection},
+		{"TransReflection", mat.TransReflection},
+		{"AmbientReflection", mat.AmbientReflection},
+		{"RoughNess", mat.RoughNess},
+		{"SpecularDecay", mat.SpecularDecay},
+		{"EmissionStrength", mat.EmissionStrength},
+	}
+	for _, coefficient := range coefficients {
+		if coefficient.value < 0 || math.IsNaN(coefficient.value) {
+			report.errorf(join(path, coefficient.field), "invalid coefficient %g", coefficient.value)
+		}
+	}
+	total := mat.DiffuseReflection + mat.SpecularReflection + mat.TransReflection
+	if total > 1 && !utils.CheckTolerance(total, 1) {
+		report.warnf(path, "DiffuseReflection + SpecularReflection + TransReflection = %g, more than 1 does not conserve energy", total)
+	}
+	if mat.RefractiveIndex < 0 || math.IsNaN(mat.RefractiveIndex) {
+		report.errorf(join(path, "RefractiveIndex"), "invalid refractive index %g", mat.RefractiveIndex)
+	}
+}
+
+// checkGeometry is a function to check the vertices, normals, texture coordinates,
+// triangles, primitive and transform of an object.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the object.
+//  obj    - the object.
+//
+// Returns:
+//  none
+//
+func checkGeometry(report *Report, path string, obj *general.Object) {
+	points := obj.Vertices.Points
+	validPoints := true
+	for i := range points {
+		if !checkVector(report, index(join(path, "Vertices.Points"), i), points[i].Coordinates, 3) {
+			validPoints = false
+		}
+	}
+	for i := range obj.Normals {
+		normalPath := index(join(path, "Normals"), i)
+		if checkVector(report, normalPath, obj.Normals[i].Coordinates, 3) && dot(obj.Normals[i].Coordinates, obj.Normals[i].Coordinates) == 0 {
+			report.errorf(normalPath, "zero length normal")
+		}
+	}
+	for i := range obj.TexCoords {
+		checkVector(report, index(join(path, "TexCoords"), i), obj.TexCoords[i].Coordinates, 2)
+	}
+	for i := range obj.Triangles {
+		checkTriangle(report, index(join(path, "Triangles"), i), obj, i, validPoints)
+	}
+	if obj.Primitive != nil {
+		if err := obj.Primitive.CheckIntegrity(); err != nil {
+			report.errorf(join(path, "Primitive"), "%s", err)
+		} else {
+			checkPrimitive(report, join(path, "Primitive"), obj.Primitive)
+		}
+	}
+	if obj.Transform != nil {
+		if _, _, err := obj.Transform.GetMatrices(); err != nil {
+			report.errorf(join(path, "Transform"), "%s", err)
+		}
+	}
+}
+
+// checkTriangle is a function to check the indices and the area of a triangle.
+//
+// Parameters:
+//  report      - the report.
+//  path        - path to the triangle.
+//  obj         - the object.
+//  triangleIdx - the index of the triangle.
+//  validPoints - false if a vertex is invalid, skipping the area.
+//
+// Returns:
+//  none
+//
+func checkTriangle(report *Report, path string, obj *general.Object, triangleIdx int, validPoints bool) {
+	triangle := &obj.Triangles[triangleIdx]
+	lists := []struct {
+		field   string
+		indices []int
+		count   int
+		needed  bool
+	}{
+		{"Vertices", triangle.Vertices, len(obj.Vertices.Points), true},
+		{"Normals", triangle.Normals, len(obj.Normals), true},
+		{"TexCoords", triangle.TexCoords, len(obj.TexCoords), false},
+	}
+	for _, list := range lists {
+		if len(list.indices) == 0 && !list.needed {
+			continue
+		}
+		if err := utils.CheckSize(len(list.indices), 3); err != nil {
+			report.errorf(join(path, list.field), "%s", err)
+			if list.field == "Vertices" {
+				validPoints = false
+			}
+			continue
+		}
+		for _, idx := range list.indices {
+			if idx < 0 || idx >= list.count {
+				if list.field == "Normals" {
+					// EnsureNormals generates the normals of such corners when the object is loaded
+					report.warnf(join(path, list.field), "index %d outside of [0, %d), the normal will be generated", idx, list.count)
+					break
+				}
+				report.errorf(join(path, list.field), "index %d outside of [0, %d)", idx, list.count)
+				if list.field == "Vertices" {
+					validPoints = false
+				}
+				break
+			}
+		}
+	}
+	if !validPoints {
+		return
+	}
+	v := triangle.Vertices
+	if v[0] == v[1] || v[1] == v[2] || v[0] == v[2] {
+		report.warnf(path, "degenerate triangle, repeated vertex in %v", v)
+		return
+	}
+	p0 := entity.PointToVec3(&obj.Vertices.Points[v[0]])
+	p1 := entity.PointToVec3(&obj.Vertices.Points[v[1]])
+	p2 := entity.PointToVec3(&obj.Vertices.Points[v[2]])
+	edge1 := p1.Sub(p0)
+	edge2 := p2.Sub(p0)
+	area := edge1.Cross(edge2).Length() / 2
+	if area <= areaTolerance*math.Max(edge1.Dot(edge1), edge2.Dot(edge2)) {
+		report.warnf(path, "degenerate triangle, area %g", area)
+	}
+}
+
+// checkPrimitive is a function to check the values of a primitive with the right lengths.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the primitive.
+//  prim   - the primitive.
+//
+// Returns:
+//  none
+//
+func checkPrimitive(report *Report, path string, prim *general.Primitive) {
+	for _, vect := range []struct {
+		field  string
+		values []float64
+	}{{"Center", prim.Center}, {"Normal", prim.Normal}, {"Corner", prim.Corner}} {
+		if len(vect.values) != 0 && !isFinite(vect.values) {
+			report.errorf(join(path, vect.field), "NaN or infinite value in %v", vect.values)
+		}
+	}
+	if len(prim.Normal) == 3 && dot(prim.Normal, prim.Normal) == 0 {
+		report.errorf(join(path, "Normal"), "zero length normal")
+	}
+	if prim.Type == general.PrimitiveQuad {
+		edge1 := utils.Vec3{prim.Edges[0][0], prim.Edges[0][1], prim.Edges[0][2]}
+		edge2 := utils.Vec3{prim.Edges[1][0], prim.Edges[1][1], prim.Edges[1][2]}
+		if edge1.Cross(edge2).Length() == 0 {
+			report.errorf(join(path, "Edges"), "parallel or zero length edges")
+		}
+	}
+}
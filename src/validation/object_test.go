@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// triangleObject is a function to create a valid object with a single triangle.
+func triangleObject() general.Object {
+	points := []entity.Point{
+		{Coordinates: []float64{0, 0, 0}},
+		{Coordinates: []float64{1, 0, 0}},
+		{Coordinates: []float64{0, 1, 0}},
+	}
+	return general.Object{
+		Vertices:          entity.InitVertices(points),
+		Triangles:         []entity.Triangle{entity.InitTriangle([]int{0, 1, 2}, []int{0, 0, 0})},
+		Normals:           []utils.Vector{{Coordinates: []float64{0, 0, 1}}},
+		Color:             []float64{1, 1, 1},
+		DiffuseReflection: 1,
+	}
+}
+
+// findIssue is a function to get the issue of a path.
+func findIssue(report *Report, path string) (Issue, bool) {
+	for _, issue := range report.Issues {
+		if issue.Path == path {
+			return issue, true
+		}
+	}
+	return Issue{}, false
+}
+
+func TestValidateObjectsColor(t *testing.T) {
+	obj := triangleObject()
+	objs := general.InitObjects("test", []general.Object{obj})
+	objs.Meshes = []general.Object{triangleObject()}
+	objs.Meshes[0].Name = "mesh"
+	objs.ObjList[0].Color = nil
+	// the material of the instance keeps the color of the mesh
+	objs.Instances = []general.Instance{{Mesh: "mesh", Material: &general.Material{DiffuseReflection: 0.5}}}
+
+	report := ValidateObjects(objs, "")
+	if issue, found := findIssue(report, "ObjList[0].Color"); !found || issue.Severity != SeverityError {
+		t.Errorf("object without color: got %+v, want an error", report.Issues)
+	}
+	if issue, found := findIssue(report, "Instances[0].Material.Color"); found {
+		t.Errorf("material without color: got %v", issue)
+	}
+	if len(report.Issues) != 1 {
+		t.Errorf("got %v, want a single issue", report.Issues)
+	}
+}
+
+func TestValidateObjectsNormalIndex(t *testing.T) {
+	obj := triangleObject()
+	obj.Triangles[0].Normals = []int{0, 3, -1}
+	report := ValidateObjects(general.InitObjects("test", []general.Object{obj}), "")
+	issue, found := findIssue(report, "ObjList[0].Triangles[0].Normals")
+	if !found || issue.Severity != SeverityWarning || len(report.Issues) != 1 {
+		t.Fatalf("got %v, want a single warning on the normals", report.Issues)
+	}
+
+	// the warning is true, the normals of the corners are generated
+	if err := obj.Prepare(); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	if !obj.HasValidNormals() {
+		t.Errorf("normals not generated: %+v", obj.Triangles[0])
+	}
+}
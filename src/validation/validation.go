@@ -0,0 +1,480 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/scene"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Severities of the issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// basisTolerance is the tolerance of the norms and dot products of the camera basis.
+const basisTolerance = 1e-3
+
+// Issue is a class for a problem found on a scene.
+//
+// Members:
+//  Severity - SeverityError when the scene can not be rendered correctly, SeverityWarning otherwise.
+//  Path     - path to the field, like ObjList[3].Triangles[12].
+//  Msg      - the description of the problem.
+//
+type Issue struct {
+	Severity string
+	Path     string
+	Msg      string
+}
+
+// String is a function to format the Issue.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  the issue as "severity path: msg".
+//
+func (issue Issue) String() string {
+	return issue.Severity + " " + issue.Path + ": " + issue.Msg
+}
+
+// Report is a class for all the issues found on a scene.
+//
+// Members:
+//  Issues - the issues, in the order they were found.
+//
+type Report struct {
+	Issues []Issue
+}
+
+// errorf is a function to add an error to the report.
+//
+// Parameters:
+//  path   - path to the field.
+//  format - the format of the message.
+//  args   - the arguments of the format.
+//
+// Returns:
+//  none
+//
+func (report *Report) errorf(path, format string, args ...interface{}) {
+	report.Issues = append(report.Issues, Issue{Severity: SeverityError, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// warnf is a function to add a warning to the report.
+//
+// Parameters:
+//  path   - path to the field.
+//  format - the format of the message.
+//  args   - the arguments of the format.
+//
+// Returns:
+//  none
+//
+func (report *Report) warnf(path, format string, args ...interface{}) {
+	report.Issues = append(report.Issues, Issue{Severity: SeverityWarning, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Count is a function to count the issues of a severity.
+//
+// Parameters:
+//  severity - SeverityError or SeverityWarning.
+//
+// Returns:
+//  the number of issues.
+//
+func (report *Report) Count(severity string) int {
+	count := 0
+	for _, issue := range report.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors is a function to check if the report has any error.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  true if an issue is an error.
+//
+func (report *Report) HasErrors() bool {
+	return report.Count(SeverityError) > 0
+}
+
+// Err is a function to summarize the errors of the report as an error.
+//
+// Parameters:
+//  none
+//
+// Returns:
+//  nil if there are no errors, otherwise an error with the first one and the count.
+//
+func (report *Report) Err() error {
+	errs := report.Count(SeverityError)
+	if errs == 0 {
+		return nil
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			msg := issue.Path + ": " + issue.Msg
+			if errs > 1 {
+				msg += fmt.Sprintf(" (and %d more errors)", errs-1)
+			}
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+// join is a function to append a field to a path.
+//
+// Parameters:
+//  path  - the path, may be empty.
+//  field - the field, an index like [3] or a name.
+//
+// Returns:
+//  the path to the field.
+//
+func join(path, field string) string {
+	if path == "" || field == "" || field[0] == '[' {
+		return path + field
+	}
+	return path + "." + field
+}
+
+// index is a function to append an index to a path.
+//
+// Parameters:
+//  path - the path.
+//  i    - the index.
+//
+// Returns:
+//  path[i].
+//
+func index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// isFinite is a function to check that no value is NaN or infinite.
+//
+// Parameters:
+//  values - the values.
+//
+// Returns:
+//  true if every value is finite.
+//
+func isFinite(values []float64) bool {
+	for _, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkVector is a function to check the length and the values of a list.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the list.
+//  values - the list.
+//  size   - the expected length.
+//
+// Returns:
+//  true if the list is valid.
+//
+func checkVector(report *Report, path string, values []float64, size int) bool {
+	if err := utils.CheckSize(len(values), size); err != nil {
+		report.errorf(path, "%s", err)
+		return false
+	}
+	if !isFinite(values) {
+		report.errorf(path, "NaN or infinite value in %v", values)
+		return false
+	}
+	return true
+}
+
+// checkColor is a function to check a RGB.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the color.
+//  color  - the color.
+//
+// Returns:
+//  true if the color is valid.
+//
+func checkColor(report *Report, path string, color []float64) bool {
+	if !checkVector(report, path, color, 3) {
+		return false
+	}
+	for _, value := range color {
+		if value < 0 {
+			report.errorf(path, "negative component in %v", color)
+			return false
+		}
+	}
+	return true
+}
+
+// checkDirection is a function to check a 3D vector with a length.
+//
+// Parameters:
+//  report - the report.
+//  path   - path to the direction.
+//  values - the direction.
+//
+// Returns:
+//  true if the direction is valid.
+//
+func checkDirection(report *Report, path string, values []float64) bool {
+	if !checkVector(report, path, values, 3) {
+		return false
+	}
+	if dot(values, values) == 0 {
+		report.errorf(path, "zero length direction")
+		return false
+	}
+	return true
+}
+
+// checkProfile is a function to check that the IES file of a light can be read.
+//
+// Parameters:
+//  report  - the report.
+//  path    - path to the profile.
+//  profile - the profile, may be nil.
+//
+// Returns:
+//  none
+//
+func checkProfile(report *Report, path string, profile *light.Profile) {
+	if profile == nil {
+		return
+	}
+	if profile.Path == "" {
+		report.errorf(path, "no path")
+	} else if !utils.PathExists(profile.Path) {
+		report.errorf(join(path, "Path"), "profile %q not found", profile.Path)
+	} else if !profile.Loaded() {
+		if err := profile.Load(); err != nil {
+			report.errorf(join(path, "Path"), "%s", err)
+		}
+	}
+}
+
+// ValidateCamera is a function to check the position, the basis and the field of view of a camera.
+//
+// Parameters:
+//  cam  - the camera.
+//  path - path to the camera, empty for a camera file.
+//
+// Returns:
+//  the report.
+//
+func ValidateCamera(cam *camera.Camera, path string) *Report {
+	report := &Report{}
+	checkVector(report, join(path, "Pos"), cam.Pos.Coordinates, 3)
+	basis := []struct {
+		field  string
+		values []float64
+	}{{"Look", cam.Look.Coordinates}, {"Up", cam.Up.Coordinates}, {"Right", cam.Right.Coordinates}}
+	valid := true
+	for _, vect := range basis {
+		if !checkVector(report, join(path, vect.field), vect.values, 3) {
+			valid = false
+			continue
+		}
+		norm := math.Sqrt(dot(vect.values, vect.values))
+		if math.Abs(norm-1) > basisTolerance {
+			report.errorf(join(path, vect.field), "norm %g, the camera basis must be orthonormal", norm)
+			valid = false
+		}
+	}
+	if valid {
+		for i := 0; i < 3; i++ {
+			for j := i + 1; j < 3; j++ {
+				if cos := dot(basis[i].values, basis[j].values); math.Abs(cos) > basisTolerance {
+					report.errorf(path, "%s and %s are not orthogonal (dot product %g)", basis[i].field, basis[j].field, cos)
+				}
+			}
+		}
+	}
+	if cam.FieldOfView <= 0 || cam.FieldOfView >= 180 || math.IsNaN(cam.FieldOfView) {
+		report.errorf(join(path, "FieldOfView"), "%g outside of (0, 180) degrees", cam.FieldOfView)
+	}
+	return report
+}
+
+// ValidateLights is a function to check the lights and their objects.
+//
+// Parameters:
+//  lgts - the lights.
+//  path - path to the lights, empty for a light file.
+//
+// Returns:
+//  the report.
+//
+func ValidateLights(lgts *light.Lights, path string) *Report {
+	report := &Report{}
+	listPath := join(path, "LightList")
+	if len(lgts.LightList) == 0 {
+		report.errorf(listPath, "no lights")
+	}
+	for i, lgt := range lgts.LightList {
+		lgtPath := index(listPath, i)
+		if lgt == nil {
+			report.errorf(lgtPath, "empty light")
+			continue
+		}
+		emitter := lgt.GetEmitter()
+		if checkColor(report, join(lgtPath, "Color"), emitter.Color) && emitter.Color[0]+emitter.Color[1]+emitter.Color[2] == 0 {
+			report.warnf(join(lgtPath, "Color"), "black light")
+		}
+		if emitter.LightIntensity < 0 || math.IsNaN(emitter.LightIntensity) {
+			report.errorf(join(lgtPath, "LightIntensity"), "invalid intensity %g", emitter.LightIntensity)
+		} else if emitter.LightIntensity == 0 {
+			report.warnf(join(lgtPath, "LightIntensity"), "light with intensity 0")
+		}
+		if emitter.AmbientIntensity < 0 || math.IsNaN(emitter.AmbientIntensity) {
+			report.errorf(join(lgtPath, "AmbientIntensity"), "invalid intensity %g", emitter.AmbientIntensity)
+		}
+		switch lgt := lgt.(type) {
+		case *light.AreaLight:
+			objPath := join(lgtPath, "LightObject")
+			checkGeometry(report, objPath, &lgt.LightObject)
+			if lgt.LightObject.Primitive == nil && len(lgt.LightObject.Triangles) == 0 {
+				report.errorf(objPath, "light without triangles or primitive can not be hit")
+			}
+		case *light.PointLight:
+			checkVector(report, join(lgtPath, "Position"), lgt.Position, 3)
+			if lgt.Radius < 0 || math.IsNaN(lgt.Radius) {
+				report.errorf(join(lgtPath, "Radius"), "invalid radius %g", lgt.Radius)
+			}
+			if lgt.Direction != nil {
+				checkDirection(report, join(lgtPath, "Direction"), lgt.Direction)
+			}
+			checkProfile(report, join(lgtPath, "Profile"), lgt.Profile)
+		case *light.SpotLight:
+			checkVector(report, join(lgtPath, "Position"), lgt.Position, 3)
+			checkDirection(report, join(lgtPath, "Direction"), lgt.Direction)
+			if !(lgt.OuterAngle > 0 && lgt.OuterAngle < 180) {
+				report.errorf(join(lgtPath, "OuterAngle"), "%g outside of (0, 180) degrees", lgt.OuterAngle)
+			} else if !(lgt.InnerAngle >= 0 && lgt.InnerAngle <= lgt.OuterAngle) {
+				report.errorf(join(lgtPath, "InnerAngle"), "%g outside of [0, OuterAngle] degrees", lgt.InnerAngle)
+			}
+			checkProfile(report, join(lgtPath, "Profile"), lgt.Profile)
+		case *light.DirectionalLight:
+			checkDirection(report, join(lgtPath, "Direction"), lgt.Direction)
+			if !(lgt.AngularDiameter >= 0 && lgt.AngularDiameter < 180) {
+				report.errorf(join(lgtPath, "AngularDiameter"), "%g outside of [0, 180) degrees", lgt.AngularDiameter)
+			}
+		case *light.EnvironmentLight:
+			if lgt.Image == nil || lgt.Image.Path == "" {
+				report.errorf(join(lgtPath, "Image"), "no image")
+			} else if !utils.PathExists(lgt.Image.Path) {
+				report.errorf(join(lgtPath, "Image.Path"), "image %q not found", lgt.Image.Path)
+			}
+			if math.IsNaN(lgt.Rotation) || math.IsInf(lgt.Rotation, 0) {
+				report.errorf(join(lgtPath, "Rotation"), "invalid rotation %g", lgt.Rotation)
+			}
+		case *light.SkyLight:
+			if !(lgt.Elevation >= 0 && lgt.Elevation <= 90) {
+				report.errorf(join(lgtPath, "Elevation"), "%g outside of [0, 90] degrees", lgt.Elevation)
+			}
+			if math.IsNaN(lgt.Azimuth) || math.IsInf(lgt.Azimuth, 0) {
+				report.errorf(join(lgtPath, "Azimuth"), "invalid azimuth %g", lgt.Azimuth)
+			}
+			if !(lgt.Turbidity >= 1.7 && lgt.Turbidity <= 10) {
+				report.errorf(join(lgtPath, "Turbidity"), "%g outside of [1.7, 10]", lgt.Turbidity)
+			}
+			if lgt.SunIntensity < 0 || math.IsNaN(lgt.SunIntensity) {
+				report.errorf(join(lgtPath, "SunIntensity"), "invalid intensity %g", lgt.SunIntensity)
+			}
+		}
+	}
+	return report
+}
+
+// ValidateScene is a function to check everything used to render a scene.
+//
+// Parameters:
+//  sc - the scene.
+//
+// Returns:
+//  the report, with the paths of the objects and the lights files (ObjList, LightList...),
+//  Cameras["name"] and Settings.
+//
+func ValidateScene(sc *scene.Scene) *Report {
+	report := &Report{}
+	report.merge(ValidateObjects(sc.Objects, ""))
+	if len(sc.Lights.LightList) > 0 || !hasEmission(sc.Objects) {
+		report.merge(ValidateLights(sc.Lights, ""))
+	}
+	names := make([]string, 0, len(sc.Cameras))
+	for name := range sc.Cameras {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		report.merge(ValidateCamera(sc.Cameras[name], fmt.Sprintf("Cameras[%q]", name)))
+	}
+	settings := &sc.Settings
+	if settings.Width <= 0 || settings.Height <= 0 {
+		report.errorf("Settings", "invalid image size (%d, %d)", settings.Width, settings.Height)
+	}
+	if settings.RaysPerPixel <= 0 {
+		report.errorf("Settings.RaysPerPixel", "%d rays per pixel", settings.RaysPerPixel)
+	}
+	if settings.Iterations < 0 {
+		report.errorf("Settings.Iterations", "%d iterations", settings.Iterations)
+	}
+	switch settings.LightSampler {
+	case "", light.SamplerUniform, light.SamplerPower, light.SamplerBVH:
+	default:
+		report.errorf("Settings.LightSampler", "unknown light sampler %q", settings.LightSampler)
+	}
+	return report
+}
+
+// merge is a function to append the issues of another report.
+//
+// Parameters:
+//  other - the other report.
+//
+// Returns:
+//  none
+//
+func (report *Report) merge(other *Report) {
+	report.Issues = append(report.Issues, other.Issues...)
+}
+
+// dot is a function to calculate the dot product of two lists.
+//
+// Parameters:
+//  a - a list.
+//  b - a list with the same length.
+//
+// Returns:
+//  a . b.
+//
+func dot(a, b []float64) float64 {
+	total := 0.0
+	for i := range a {
+		total += a[i] * b[i]
+	}
+	return total
+}
@@ -0,0 +1,128 @@
+package visualizer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Image formats of the writers.
+const (
+	FormatPPM = "ppm"
+	FormatPNG = "png"
+)
+
+// WriteNamedPNG is a function to write a png with a given file name.
+//
+// Parameters:
+//  sc      - the colored screen.
+//  outPath - path to the output folder.
+//  name    - name of the file without extension.
+//
+// Returns:
+//  an error if the file could not be written.
+//
+func WriteNamedPNG(sc screen.ColoredScreen, outPath, name string) error {
+	img := image.NewRGBA(image.Rect(0, 0, sc.Width, sc.Height))
+	for i := 0; i < sc.Height; i++ {
+		for j := 0; j < sc.Width; j++ {
+			rgb := sc.Colors[i][j]
+			img.SetRGBA(j, i, color.RGBA{R: clampByte(rgb[0]), G: clampByte(rgb[1]), B: clampByte(rgb[2]), A: 255})
+		}
+	}
+	// getting the right path
+	filePath, err := filepath.Abs(path.Join(outPath, name+".png"))
+	if err != nil {
+		return err
+	}
+	// creating the folder if it doesn't exists.
+	if !utils.PathExists(filePath) {
+		if err = os.MkdirAll(outPath, 0700); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteNamedImage is a function to write an image in a given format.
+//
+// Parameters:
+//  sc      - the colored screen.
+//  outPath - path to the output folder.
+//  name    - name of the file without extension.
+//  format  - FormatPPM or FormatPNG.
+//
+// Returns:
+//  an error if the format is unknown or the file could not be written.
+//
+func WriteNamedImage(sc screen.ColoredScreen, outPath, name, format string) error {
+	switch format {
+	case FormatPPM:
+		return WriteNamedPPM(sc, outPath, name)
+	case FormatPNG:
+		return WriteNamedPNG(sc, outPath, name)
+	default:
+		return fmt.Errorf("unknown image format %q", format)
+	}
+}
+
+// WriteNamedStereoImage is a function to write the images of a stereo rig in a given format.
+//
+// Parameters:
+//  left    - the colored screen of the left eye.
+//  right   - the colored screen of the right eye.
+//  layout  - camera.LayoutSeparate, camera.LayoutSideBySide or camera.LayoutTopBottom.
+//  outPath - path to the output folder.
+//  name    - name of the composite file without extension, followed by -left and -right when separate.
+//  format  - FormatPPM or FormatPNG.
+//
+// Returns:
+//  an error if the format is unknown or a file could not be written.
+//
+func WriteNamedStereoImage(left, right *screen.ColoredScreen, layout, outPath, name, format string) error {
+	switch layout {
+	case camera.LayoutSideBySide:
+		return WriteNamedImage(screen.ComposeSideBySide(left, right), outPath, name, format)
+	case camera.LayoutTopBottom:
+		return WriteNamedImage(screen.ComposeTopBottom(left, right), outPath, name, format)
+	default:
+		if err := WriteNamedImage(*left, outPath, name+"-left", format); err != nil {
+			return err
+		}
+		return WriteNamedImage(*right, outPath, name+"-right", format)
+	}
+}
+
+// clampByte is a function to convert a color component to a byte.
+//
+// Parameters:
+//  value - the component, from 0 to 255.
+//
+// Returns:
+//  the component clamped to [0, 255].
+//
+func clampByte(value int) uint8 {
+	if value < 0 {
+		return 0
+	}
+	if value > 255 {
+		return 255
+	}
+	return uint8(value)
+}
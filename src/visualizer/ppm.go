@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/lucas625/Projeto-CG/src/camera"
 	"github.com/lucas625/Projeto-CG/src/screen"
 	"github.com/lucas625/Projeto-CG/src/utils"
 )
@@ -18,11 +19,25 @@ import (
 //  outPath - path to the output folder.
 //
 // Returns:
-//  none
+//  an error if the file could not be written.
 //
-func WritePPM(sc screen.ColoredScreen, outPath string) {
+func WritePPM(sc screen.ColoredScreen, outPath string) error {
+	return WriteNamedPPM(sc, outPath, "object")
+}
+
+// WriteNamedPPM is a function to write a ppm with a given file name.
+//
+// Parameters:
+//  sc      - the colored screen.
+//  outPath - path to the output folder.
+//  name    - name of the file without extension.
+//
+// Returns:
+//  an error if the file could not be written.
+//
+func WriteNamedPPM(sc screen.ColoredScreen, outPath, name string) error {
 	ppmAsString := ""
-	header := "P3\n# object.ppm\n" + strconv.Itoa(sc.Width) + " " + strconv.Itoa(sc.Height) + "\n255\n"
+	header := "P3\n# " + name + ".ppm\n" + strconv.Itoa(sc.Width) + " " + strconv.Itoa(sc.Height) + "\n255\n"
 	body := ""
 	count := 0
 	for i := 0; i < sc.Height; i++ {
@@ -51,7 +66,32 @@ func WritePPM(sc screen.ColoredScreen, outPath string) {
 		count = 0
 	}
 	ppmAsString = header + body
-	Write(outPath, ppmAsString)
+	return WriteNamed(outPath, name, ppmAsString)
+}
+
+// WriteStereoPPM is a function to write the images of a stereo rig.
+//
+// Parameters:
+//  left    - the colored screen of the left eye.
+//  right   - the colored screen of the right eye.
+//  layout  - camera.LayoutSeparate, camera.LayoutSideBySide or camera.LayoutTopBottom.
+//  outPath - path to the output folder.
+//
+// Returns:
+//  an error if the file could not be written.
+//
+func WriteStereoPPM(left, right *screen.ColoredScreen, layout, outPath string) error {
+	switch layout {
+	case camera.LayoutSideBySide:
+		return WriteNamedPPM(screen.ComposeSideBySide(left, right), outPath, "stereo")
+	case camera.LayoutTopBottom:
+		return WriteNamedPPM(screen.ComposeTopBottom(left, right), outPath, "stereo")
+	default:
+		if err := WriteNamedPPM(*left, outPath, "left"); err != nil {
+			return err
+		}
+		return WriteNamedPPM(*right, outPath, "right")
+	}
 }
 
 // Write is a function to write a ppm with its string.
@@ -61,20 +101,36 @@ func WritePPM(sc screen.ColoredScreen, outPath string) {
 //  ppmAsString - the ppm formated as string.
 //
 // Returns:
-//  none
+//  an error if the file could not be written.
 //
-func Write(outPath, ppmAsString string) {
+func Write(outPath, ppmAsString string) error {
+	return WriteNamed(outPath, "object", ppmAsString)
+}
+
+// WriteNamed is a function to write a ppm with its string and file name.
+//
+// Parameters:
+//  outPath     - path to the output folder.
+//  name        - name of the file without extension.
+//  ppmAsString - the ppm formated as string.
+//
+// Returns:
+//  an error if the file could not be written.
+//
+func WriteNamed(outPath, name, ppmAsString string) error {
 	// creating the json
 	file := []byte(ppmAsString)
 	// getting the right path
-	filePath, err := filepath.Abs(path.Join(outPath, "object.ppm"))
-	utils.ShowError(err, "Unable to get objects's absolute path.")
+	filePath, err := filepath.Abs(path.Join(outPath, name+".ppm"))
+	if err != nil {
+		return err
+	}
 	// creating the folder if it doesn't exists.
 	if !utils.PathExists(filePath) {
-		err = os.MkdirAll(outPath, 0700)
-		utils.ShowError(err, "Unable to create dirs.")
+		if err = os.MkdirAll(outPath, 0700); err != nil {
+			return err
+		}
 	}
 	// writing
-	err = ioutil.WriteFile(filePath, file, 0700)
-	utils.ShowError(err, "Unable to write objects.")
+	return ioutil.WriteFile(filePath, file, 0700)
 }
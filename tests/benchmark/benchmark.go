@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/lucas625/Projeto-CG/src/algorithms/pathtracing"
+	"github.com/lucas625/Projeto-CG/src/camera"
+	"github.com/lucas625/Projeto-CG/src/entity"
+	"github.com/lucas625/Projeto-CG/src/general"
+	"github.com/lucas625/Projeto-CG/src/light"
+	"github.com/lucas625/Projeto-CG/src/screen"
+	"github.com/lucas625/Projeto-CG/src/shape"
+	"github.com/lucas625/Projeto-CG/src/utils"
+)
+
+// Benchmark of the intersection and bounce code, comparing the slice based types (Line, Point, Vector)
+// with the value types (Ray, Vec3) used by the integrators, and of a whole path traced image.
+//
+// Usage:
+//  go run ./tests/benchmark [objects.json]
+//
+func main() {
+	objectsPath := "resources/run/json/objects.json"
+	if len(os.Args) > 1 {
+		objectsPath = os.Args[1]
+	}
+	const numRays = 20000
+	const size = 100
+	const renderSize = 64
+	const renderRays = 4
+
+	cam, err := camera.LoadJSONCamera("resources/run/json/camera.json")
+	utils.ShowError(err, "Unable to load camera.")
+	lights, err := light.LoadJSONLights("resources/run/json/light.json")
+	utils.ShowError(err, "Unable to load lights.")
+	objects, err := general.LoadJSONObjects(objectsPath)
+	utils.ShowError(err, "Unable to load objects.")
+	camMatrix := camera.CamToWorld(cam)
+	sc, err := screen.InitScreen(size, size)
+	utils.ShowError(err, "Invalid screen.")
+	sc.CamToWorld = &camMatrix
+
+	rand.Seed(1)
+	lines := make([]entity.Line, numRays)
+	rays := make([]entity.Ray, numRays)
+	for i := range lines {
+		dir, err := sc.PixelToWorld(rand.Intn(size), rand.Intn(size), 1.0, rand.Float64(), rand.Float64(), cam.FieldOfView)
+		utils.ShowError(err, "Invalid pixel.")
+		lines[i] = entity.Line{Start: cam.Pos, Director: dir}
+		rays[i], err = lines[i].ToRay()
+		utils.ShowError(err, "Invalid ray.")
+	}
+	shapes, err := shape.FromObjects(objects)
+	utils.ShowError(err, "Unable to build the shapes.")
+
+	triangles := 0
+	for _, obj := range objects.ObjList {
+		triangles += len(obj.Triangles)
+	}
+	fmt.Printf("%s: %d objects, %d triangles, %d rays\n\n", objectsPath, len(objects.ObjList), triangles, numRays)
+
+	hitsBefore := 0
+	before := measure("intersection (Line, []Point)", numRays, func(i int) {
+		if _, found := closestLine(objects, lines[i]); found {
+			hitsBefore++
+		}
+	})
+	hitsAfter := 0
+	after := measure("intersection (Ray, Vec3)", numRays, func(i int) {
+		if _, found := closestRay(&shapes, rays[i]); found {
+			hitsAfter++
+		}
+	})
+	fmt.Printf("  speedup %.1fx, hits %d / %d\n\n", after/before, hitsBefore, hitsAfter)
+
+	normal := utils.Vector{Coordinates: []float64{0, 1, 0}}
+	normalVec3, _ := utils.VectorToVec3(&normal) // a 3D vector
+	before = measure("bounce direction (Vector)", numRays, func(i int) {
+		vectorBounce(&normal)
+	})
+	after = measure("bounce direction (Vec3)", numRays, func(i int) {
+		pathtracing.RandomInSemiSphere(normalVec3)
+	})
+	fmt.Printf("  speedup %.1fx\n\n", after/before)
+
+	measure("closest hit with BVH (Ray, Vec3)", numRays, func(i int) {
+		shapes.Closest(rays[i], 1, math.MaxFloat64)
+	})
+	pathTracer, err := pathtracing.InitPathTracer(objects, &sc, cam, lights)
+	utils.ShowError(err, "Unable to build the shapes.")
+	measure("path traced samples, 5 bounces", numRays/10, func(i int) {
+		pathTracer.TraceRayDepth(lines[i], 5)
+	})
+
+	// the whole image, lines traced by the goroutines of the path tracer
+	renderScreen, err := screen.InitScreen(renderSize, renderSize)
+	utils.ShowError(err, "Invalid screen.")
+	renderScreen.CamToWorld = &camMatrix
+	pathTracer.PixelScreen = &renderScreen
+	start := time.Now()
+	pathTracer.Run(renderRays, 5)
+	elapsed := time.Since(start)
+	fmt.Printf("\n%-36s %12.0f rays/s\n", "path traced image (Run), 5 bounces", float64(renderSize*renderSize*renderRays)/elapsed.Seconds())
+	fmt.Printf("  %dx%d, %d rays per pixel, %d threads in %s\n", renderSize, renderSize, renderRays, pathTracer.Threads, elapsed.Round(time.Millisecond))
+}
+
+// measure is a function to time a benchmark and print the rays per second.
+//
+// Parameters:
+//  name  - the name of the benchmark.
+//  count - number of iterations.
+//  run   - function running the iteration i.
+//
+// Returns:
+//  the rays per second.
+//
+func measure(name string, count int, run func(i int)) float64 {
+	start := time.Now()
+	for i := 0; i < count; i++ {
+		run(i)
+	}
+	perSecond := float64(count) / time.Since(start).Seconds()
+	fmt.Printf("%-36s %12.0f rays/s\n", name, perSecond)
+	return perSecond
+}
+
+// closestLine is a function to intersect every triangle as done before the Vec3 types.
+//
+// Parameters:
+//  objects - the objects.
+//  line    - the line.
+//
+// Returns:
+//  the t of the closest hit.
+//  false if nothing was intersected.
+//
+func closestLine(objects *general.Objects, line entity.Line) (float64, bool) {
+	closest := math.MaxFloat64
+	found := false
+	for _, obj := range objects.ObjList {
+		for _, triangle := range obj.Triangles {
+			points := make([]entity.Point, 3)
+			for pi := 0; pi < 3; pi++ {
+				points[pi] = obj.Vertices.Points[triangle.Vertices[pi]]
+			}
+			t, _, intersected, _ := line.IntersectTriangle(points) // the objects are checked by LoadJSONObjects
+			if intersected && t >= 1 && t < closest {
+				closest = t
+				found = true
+			}
+		}
+	}
+	if found {
+		line.FindPos(closest)
+	}
+	return closest, found
+}
+
+// closestRay is a function to intersect every mesh without the BVH of the List, matching closestLine.
+//
+// Parameters:
+//  shapes - the shapes.
+//  ray    - the ray.
+//
+// Returns:
+//  the t of the closest hit.
+//  false if nothing was intersected.
+//
+func closestRay(shapes *shape.List, ray entity.Ray) (float64, bool) {
+	closest := math.MaxFloat64
+	found := false
+	for _, s := range shapes.Shapes {
+		if _, isMesh := s.(*shape.Mesh); !isMesh {
+			continue
+		}
+		if hit, intersected := s.Intersect(ray, 1, closest); intersected {
+			closest = hit.T
+			found = true
+		}
+	}
+	return closest, found
+}
+
+// vectorBounce is a function to find a diffuse bounce direction as done before the Vec3 types.
+//
+// Parameters:
+//  normal - the normal.
+//
+// Returns:
+//  the direction.
+//
+func vectorBounce(normal *utils.Vector) utils.Vector {
+	var v utils.Vector
+	for {
+		v = utils.Vector{Coordinates: []float64{rand.Float64(), rand.Float64(), rand.Float64()}}
+		v = utils.CMultVector(&v, 2)
+		vaux := utils.Vector{Coordinates: []float64{1, 1, 1}}
+		v = utils.SumVector(&v, &vaux, 1, -1)
+		if math.Pow(utils.VectorNorm(&v), 2) < 1 {
+			break
+		}
+	}
+	vect := utils.SumVector(normal, &v, 1, 1)
+	return utils.NormalizeVector(&vect)
+}
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bufio"
-	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
@@ -18,66 +17,80 @@ import (
 	"github.com/lucas625/Projeto-CG/src/visualizer"
 )
 
+// testObjects are the .obj files chosen by their number.
+var testObjects = map[int]string{
+	1: "resources/obj/simple/cone.obj",
+	2: "resources/obj/simple/cube.obj",
+	3: "resources/obj/simple/plane.obj",
+	4: "resources/obj/complex/horned_ball.obj",
+	5: "resources/obj/complex/monkey_with_cube.obj",
+	6: "resources/obj/complex/spikedball.obj",
+}
+
+// Ray casting of .obj files lit from the camera, the first one painted red.
+//
+// Usage:
+//  go run tests/test.go [-o out/] [-camera resources/json/camera.json] [-size 200] object.obj|1-6...
+//
 func main() {
-	ended := false
-	objList := make([]general.Object, 0, 10)
-	current := 0
-	reader := bufio.NewReader(os.Stdin)
-	for !ended {
-		fmt.Print("Enter the path to a .obj file: ")
-		objPath, _ := reader.ReadString('\n')
-		obi, err := strconv.Atoi(objPath[:len(objPath)-2]) // removing \n
-		utils.ShowError(err, "fail")
-		switch obi { //test cases
-		case 1:
-			objPath = "resources/obj/simple/cone.obj"
-		case 2:
-			objPath = "resources/obj/simple/cube.obj"
-		case 3:
-			objPath = "resources/obj/simple/plane.obj"
-		case 4:
-			objPath = "resources/obj/complex/horned_ball.obj"
-		case 5:
-			objPath = "resources/obj/complex/monkey_with_cube.obj"
-		case 6:
-			objPath = "resources/obj/complex/spikedball.obj"
-		case 0:
-			if current >= 1 {
-				ended = true
-				continue
-			} else {
-				utils.ShowError(errors.New("Need at least one object"), "Try again")
-			}
+	outPath := flag.String("o", "out/", "output folder")
+	cameraPath := flag.String("camera", "resources/json/camera.json", "camera json file")
+	size := flag.Int("size", 200, "width and height of the image in pixels")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: go run tests/test.go [flags] object.obj|number...")
+		fmt.Fprintln(os.Stderr, "\nNumbers choose the test objects:")
+		for i := 1; i <= len(testObjects); i++ {
+			fmt.Fprintf(os.Stderr, "  %d  %s\n", i, testObjects[i])
 		}
-
-		object := obj.ReadObj(objPath)
-		objList = append(objList, *object)
-		current++
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	fmt.Print("Enter the path to the output folder: ")
-	outPath, _ := reader.ReadString('\n')
-	outPath = outPath[:len(outPath)-2] // removing \n
-	if outPath == "-1" {               // test case
-		outPath = "out/"
+	objList := make([]general.Object, 0, flag.NArg())
+	for _, objPath := range flag.Args() {
+		if obi, err := strconv.Atoi(objPath); err == nil { //test cases
+			testPath, found := testObjects[obi]
+			if !found {
+				utils.ShowError(fmt.Errorf("no test object %d", obi), "Invalid object.")
+			}
+			objPath = testPath
+		}
+		object, err := obj.ReadObj(objPath)
+		utils.ShowError(err, "Unable to read object")
+		objList = append(objList, *object)
 	}
 
 	objects := general.InitObjects("teste", objList)
-	objects.ObjList[0].Color = []float64{255, 0, 0}
+	for i := range objects.ObjList {
+		objects.ObjList[i].Color = []float64{0.8, 0.8, 0.8}
+		objects.ObjList[i].DiffuseReflection = 1
+		objects.ObjList[i].AmbientReflection = 1
+	}
+	objects.ObjList[0].Color = []float64{1, 0, 0}
 
-	cameraPath := "resources/json/camera.json"
-	cam := camera.LoadJSONCamera(cameraPath)
-	// cam = objects.ObjList[0].FindCamera(&cam.Pos)
+	cam, err := camera.LoadJSONCamera(*cameraPath)
+	utils.ShowError(err, "Unable to load camera.")
+	// cam, err = objects.ObjList[0].FindCamera(&cam.Pos)
 
 	camMatrix := camera.CamToWorld(cam)
 
-	sc := screen.InitScreen(200, 200)
+	sc, err := screen.InitScreen(*size, *size)
+	utils.ShowError(err, "Invalid screen.")
 	sc.CamToWorld = &camMatrix
 
-	lights := light.Lights{LightList: []light.Light{light.Light{}}}
+	headlight := light.InitDirectionalLight(3, []float64{1, 1, 1}, cam.Look.Coordinates, 0)
+	headlight.AmbientIntensity = 0.1
+	lights := light.InitLights([]light.Light{headlight})
 
-	rayCaster := raycasting.InitRayCaster(objects, &sc, cam, &lights)
+	rayCaster, err := raycasting.InitRayCaster(objects, &sc, cam, &lights)
+	utils.ShowError(err, "Unable to build the shapes.")
 
 	colorScreen := rayCaster.Run()
-	visualizer.WritePPM(*colorScreen, outPath)
+	err = visualizer.WritePPM(*colorScreen, *outPath)
+	utils.ShowError(err, "Unable to write image.")
 }